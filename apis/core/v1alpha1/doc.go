@@ -0,0 +1,22 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1alpha1 contains the types that extend the core.k8s.appscode.com v1alpha1 API
+// group served by the ui-server, eg, the JSON encoded annotations of a PodView, and the
+// kinds that are only served by the ui-server, eg, ProjectSummary.
+// +k8s:deepcopy-gen=package
+// +groupName=core.k8s.appscode.com
+package v1alpha1 // import "kubeops.dev/ui-server/apis/core/v1alpha1"
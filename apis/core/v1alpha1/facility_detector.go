@@ -0,0 +1,70 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	sharedapi "kmodules.xyz/resource-metadata/apis/shared"
+)
+
+// AnnotationFacilities is set on GenericResourceService to the JSON encoded map of the
+// detected facilities other than the ones in spec.facilities, keyed by the facility name.
+const AnnotationFacilities = "core.k8s.appscode.com/facilities"
+
+// Names of the facilities reported in the spec.facilities of a GenericResourceService.
+const (
+	FacilityExposed    = "exposed"
+	FacilityTLS        = "tls"
+	FacilityBackup     = "backup"
+	FacilityMonitoring = "monitoring"
+)
+
+// FacilityDetector decides whether a resource uses a facility, eg, backup or autoscaling.
+type FacilityDetector struct {
+	Name string `json:"name"`
+	// Targets are the kinds the detector applies to. A target without a kind matches
+	// every kind of its group. The detector applies to every kind if no target is set.
+	// +optional
+	Targets []metav1.GroupKind `json:"targets,omitempty"`
+	// Locators find the objects that provide the facility, and are tried in order until
+	// one finds any. The facility is Used if an object is found, Unused if no object is
+	// found by a locator whose kind is served by the cluster, and Unknown otherwise.
+	// +optional
+	Locators []FacilityLocator `json:"locators,omitempty"`
+	// Evaluator is the name of a built-in check of the resource itself, used instead of
+	// Locators. The only evaluator is UsesTLS.
+	// +optional
+	Evaluator string `json:"evaluator,omitempty"`
+	// Disabled removes the detector with the same name, eg, a built-in one.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// FacilityLocator finds objects of the kind in ref that provide a facility to a resource.
+// By default, the objects are found with the graph query of the locator.
+type FacilityLocator struct {
+	sharedapi.ResourceLocator `json:",inline"`
+	// SelectorPath is the path of a label selector in the objects, eg, spec.podSelector.
+	// When set, the objects in the namespace of the resource whose selector matches any of
+	// its pods are found instead of running the query.
+	// +optional
+	SelectorPath string `json:"selectorPath,omitempty"`
+	// Condition is a Go template rendered with each found object. Objects for which it
+	// does not render true are ignored.
+	// +optional
+	Condition string `json:"condition,omitempty"`
+}
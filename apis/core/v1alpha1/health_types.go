@@ -0,0 +1,112 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// AnnotationHealth is set on GenericResourceService to its JSON encoded HealthScore once the
+// resource has been scored in the background.
+const AnnotationHealth = "core.k8s.appscode.com/health"
+
+// +kubebuilder:validation:Enum=A;B;C;D;F
+type HealthGrade string
+
+const (
+	HealthGradeA HealthGrade = "A"
+	HealthGradeB HealthGrade = "B"
+	HealthGradeC HealthGrade = "C"
+	HealthGradeD HealthGrade = "D"
+	HealthGradeF HealthGrade = "F"
+)
+
+// +kubebuilder:validation:Enum=Status;Facility;CVE;PolicyViolation;Quota
+type HealthCheck string
+
+const (
+	HealthCheckStatus          HealthCheck = "Status"
+	HealthCheckFacility        HealthCheck = "Facility"
+	HealthCheckCVE             HealthCheck = "CVE"
+	HealthCheckPolicyViolation HealthCheck = "PolicyViolation"
+	HealthCheckQuota           HealthCheck = "Quota"
+)
+
+// HealthScore is the health of a resource. The score starts at 100 and every reason
+// deducts its penalty, down to 0.
+type HealthScore struct {
+	Score int32       `json:"score"`
+	Grade HealthGrade `json:"grade"`
+	// +optional
+	Reasons []HealthReason `json:"reasons,omitempty"`
+}
+
+// HealthReason is a deduction from a HealthScore.
+type HealthReason struct {
+	Check   HealthCheck `json:"check"`
+	Penalty int32       `json:"penalty"`
+	Message string      `json:"message"`
+}
+
+// HealthPolicy configures how a HealthScore is computed.
+type HealthPolicy struct {
+	// StatusPenalties are keyed by the computed status of the resource, eg, Failed or InProgress.
+	// +optional
+	StatusPenalties map[string]int32 `json:"statusPenalties,omitempty"`
+	// FacilityPenalties are keyed by the facility name, eg, backup. Penalties of the
+	// facilities added by a FacilityDetector can be set too.
+	// +optional
+	FacilityPenalties map[string]FacilityPenalty `json:"facilityPenalties,omitempty"`
+	// CVEPenalties are deducted per unique CVE and keyed by its severity, eg, CRITICAL.
+	// +optional
+	CVEPenalties map[string]int32 `json:"cvePenalties,omitempty"`
+	// MaxCVEPenalty caps the total penalty of the CVEs. Zero means no cap.
+	// +optional
+	MaxCVEPenalty int32 `json:"maxCVEPenalty,omitempty"`
+	// PolicyViolationPenalty is deducted per violation of a Gatekeeper constraint.
+	// +optional
+	PolicyViolationPenalty int32 `json:"policyViolationPenalty,omitempty"`
+	// MaxPolicyViolationPenalty caps the total penalty of the policy violations. Zero means no cap.
+	// +optional
+	MaxPolicyViolationPenalty int32 `json:"maxPolicyViolationPenalty,omitempty"`
+	// +optional
+	Quota QuotaPenalty `json:"quota,omitempty"`
+	// Grades are tried in order, and the first one whose minimum score is reached is
+	// used. F is used if none is reached.
+	// +optional
+	Grades []HealthGradeThreshold `json:"grades,omitempty"`
+}
+
+// FacilityPenalty is deducted if a facility has the given usage, eg, Unused for backup or
+// Used for exposed.
+type FacilityPenalty struct {
+	Usage   string `json:"usage"`
+	Penalty int32  `json:"penalty"`
+}
+
+// QuotaPenalty is deducted once per ProjectQuota resource whose usage crosses the threshold.
+type QuotaPenalty struct {
+	// ThresholdPercent of the hard limit at which the quota is under pressure.
+	// +optional
+	ThresholdPercent int32 `json:"thresholdPercent,omitempty"`
+	// +optional
+	Penalty int32 `json:"penalty,omitempty"`
+	// ExceededPenalty is deducted instead of Penalty when the usage reaches the hard limit.
+	// +optional
+	ExceededPenalty int32 `json:"exceededPenalty,omitempty"`
+}
+
+type HealthGradeThreshold struct {
+	Grade    HealthGrade `json:"grade"`
+	MinScore int32       `json:"minScore"`
+}
@@ -0,0 +1,62 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rscoreapi "kmodules.xyz/resource-metadata/apis/core/v1alpha1"
+)
+
+const (
+	ResourceKindPodDetail = "PodDetail"
+	ResourcePodDetail     = "poddetail"
+	ResourcePodDetails    = "poddetails"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PodDetail is the PodView of a pod along with what the pod page shows beyond it: the init and
+// ephemeral containers, the status of every container and the recent warning Events.
+// It is named after the pod.
+type PodDetail struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              PodDetailSpec   `json:"spec,omitempty"`
+	Status            PodDetailStatus `json:"status,omitempty"`
+}
+
+type PodDetailSpec struct {
+	// PodViewSpec is the spec of the PodView of the pod.
+	rscoreapi.PodViewSpec `json:",inline"`
+	// +optional
+	InitContainers []rscoreapi.ContainerView `json:"initContainers,omitempty"`
+	// +optional
+	EphemeralContainers []rscoreapi.ContainerView `json:"ephemeralContainers,omitempty"`
+}
+
+type PodDetailStatus struct {
+	core.PodStatus `json:",inline"`
+	// ContainerViews are the statuses of the containers, init containers and ephemeral
+	// containers, in that order.
+	// +optional
+	ContainerViews []ContainerViewStatus `json:"containerViews,omitempty"`
+	// RecentEvents are the latest warning Events of the pod, newest first.
+	// +optional
+	RecentEvents []PodViewEvent `json:"recentEvents,omitempty"`
+}
@@ -0,0 +1,77 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:validation:Enum=Container;InitContainer;SidecarContainer;EphemeralContainer
+type ContainerType string
+
+const (
+	ContainerTypeContainer ContainerType = "Container"
+	ContainerTypeInit      ContainerType = "InitContainer"
+	// ContainerTypeSidecar is an init container with restartPolicy Always.
+	ContainerTypeSidecar   ContainerType = "SidecarContainer"
+	ContainerTypeEphemeral ContainerType = "EphemeralContainer"
+)
+
+// ContainerViewStatus is the status of a container of a PodDetail.
+type ContainerViewStatus struct {
+	Name string        `json:"name"`
+	Type ContainerType `json:"type"`
+	// +optional
+	Ready bool `json:"ready,omitempty"`
+	// State is Waiting, Running or Terminated. It is empty until the kubelet reports the container.
+	// +optional
+	State string `json:"state,omitempty"`
+	// Reason is the reason of the current Waiting or Terminated state.
+	// +optional
+	Reason       string `json:"reason,omitempty"`
+	RestartCount int32  `json:"restartCount"`
+	// +optional
+	LastTermination *ContainerTermination `json:"lastTermination,omitempty"`
+	// OOMKilled is true if the current or the last run of the container was killed for running out of memory.
+	// +optional
+	OOMKilled bool `json:"oomKilled,omitempty"`
+}
+
+// ContainerTermination describes the last termination of a restarted container.
+type ContainerTermination struct {
+	// +optional
+	Reason   string `json:"reason,omitempty"`
+	ExitCode int32  `json:"exitCode"`
+	// +optional
+	FinishedAt metav1.Time `json:"finishedAt,omitempty"`
+}
+
+// PodViewEvent is an Event of a pod.
+type PodViewEvent struct {
+	Type   string `json:"type"`
+	Reason string `json:"reason,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+	// +optional
+	Count int32 `json:"count,omitempty"`
+	// +optional
+	Source string `json:"source,omitempty"`
+	// +optional
+	FirstTimestamp metav1.Time `json:"firstTimestamp,omitempty"`
+	// +optional
+	LastTimestamp metav1.Time `json:"lastTimestamp,omitempty"`
+}
@@ -0,0 +1,84 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kmapi "kmodules.xyz/client-go/api/v1"
+	rscoreapi "kmodules.xyz/resource-metadata/apis/core/v1alpha1"
+)
+
+const (
+	ResourceKindProjectSummary = "ProjectSummary"
+	ResourceProjectSummary     = "projectsummary"
+	ResourceProjectSummaries   = "projectsummaries"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ProjectSummary is the rollup of the resources in the namespaces of a Project that the
+// user is allowed to get. It is named after the Project.
+type ProjectSummary struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              ProjectSummarySpec `json:"spec,omitempty"`
+}
+
+type ProjectSummarySpec struct {
+	Cluster kmapi.ClusterMetadata `json:"cluster,omitempty"`
+	// Namespaces of the project.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Resources are the kinds with at least one object in the namespaces, ordered by group and kind.
+	// +optional
+	Resources []ProjectResourceCount `json:"resources,omitempty"`
+	// TotalResource is the sum of the requests and limits of the pods, and of the storage
+	// requested by the PersistentVolumeClaims.
+	// +optional
+	TotalResource rscoreapi.ResourceRequirements `json:"totalResource,omitempty"`
+	// Usage is the cpu and memory used by the pods and the storage used by the
+	// PersistentVolumeClaims, as reported by Prometheus.
+	// +optional
+	Usage rscoreapi.ResourceList `json:"usage,omitempty"`
+	// Failing is the number of objects whose status is Failed.
+	// +optional
+	Failing int `json:"failing,omitempty"`
+	// CVEs maps a severity to the number of distinct CVEs in the images of the pods.
+	// +optional
+	CVEs map[string]int `json:"cves,omitempty"`
+	// PolicyViolations is the number of Gatekeeper violations of the objects.
+	// +optional
+	PolicyViolations int `json:"policyViolations,omitempty"`
+}
+
+// ProjectResourceCount is the number of objects of a kind in a project.
+type ProjectResourceCount struct {
+	APIType kmapi.ResourceID `json:"apiType"`
+	Count   int              `json:"count"`
+	// +optional
+	Failing int `json:"failing,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type ProjectSummaryList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ProjectSummary `json:"items,omitempty"`
+}
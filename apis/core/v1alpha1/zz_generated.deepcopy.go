@@ -0,0 +1,464 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by deepcopy-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	corev1alpha1 "kmodules.xyz/resource-metadata/apis/core/v1alpha1"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerTermination) DeepCopyInto(out *ContainerTermination) {
+	*out = *in
+	in.FinishedAt.DeepCopyInto(&out.FinishedAt)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerTermination.
+func (in *ContainerTermination) DeepCopy() *ContainerTermination {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerTermination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerViewStatus) DeepCopyInto(out *ContainerViewStatus) {
+	*out = *in
+	if in.LastTermination != nil {
+		in, out := &in.LastTermination, &out.LastTermination
+		*out = new(ContainerTermination)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerViewStatus.
+func (in *ContainerViewStatus) DeepCopy() *ContainerViewStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerViewStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FacilityDetector) DeepCopyInto(out *FacilityDetector) {
+	*out = *in
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]v1.GroupKind, len(*in))
+		copy(*out, *in)
+	}
+	if in.Locators != nil {
+		in, out := &in.Locators, &out.Locators
+		*out = make([]FacilityLocator, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FacilityDetector.
+func (in *FacilityDetector) DeepCopy() *FacilityDetector {
+	if in == nil {
+		return nil
+	}
+	out := new(FacilityDetector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FacilityLocator) DeepCopyInto(out *FacilityLocator) {
+	*out = *in
+	out.ResourceLocator = in.ResourceLocator
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FacilityLocator.
+func (in *FacilityLocator) DeepCopy() *FacilityLocator {
+	if in == nil {
+		return nil
+	}
+	out := new(FacilityLocator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FacilityPenalty) DeepCopyInto(out *FacilityPenalty) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FacilityPenalty.
+func (in *FacilityPenalty) DeepCopy() *FacilityPenalty {
+	if in == nil {
+		return nil
+	}
+	out := new(FacilityPenalty)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthGradeThreshold) DeepCopyInto(out *HealthGradeThreshold) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthGradeThreshold.
+func (in *HealthGradeThreshold) DeepCopy() *HealthGradeThreshold {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthGradeThreshold)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthPolicy) DeepCopyInto(out *HealthPolicy) {
+	*out = *in
+	if in.StatusPenalties != nil {
+		in, out := &in.StatusPenalties, &out.StatusPenalties
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.FacilityPenalties != nil {
+		in, out := &in.FacilityPenalties, &out.FacilityPenalties
+		*out = make(map[string]FacilityPenalty, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CVEPenalties != nil {
+		in, out := &in.CVEPenalties, &out.CVEPenalties
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.Quota = in.Quota
+	if in.Grades != nil {
+		in, out := &in.Grades, &out.Grades
+		*out = make([]HealthGradeThreshold, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthPolicy.
+func (in *HealthPolicy) DeepCopy() *HealthPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthReason) DeepCopyInto(out *HealthReason) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthReason.
+func (in *HealthReason) DeepCopy() *HealthReason {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthReason)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthScore) DeepCopyInto(out *HealthScore) {
+	*out = *in
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]HealthReason, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthScore.
+func (in *HealthScore) DeepCopy() *HealthScore {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthScore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDetail) DeepCopyInto(out *PodDetail) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDetail.
+func (in *PodDetail) DeepCopy() *PodDetail {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDetail)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PodDetail) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDetailSpec) DeepCopyInto(out *PodDetailSpec) {
+	*out = *in
+	in.PodViewSpec.DeepCopyInto(&out.PodViewSpec)
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]corev1alpha1.ContainerView, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EphemeralContainers != nil {
+		in, out := &in.EphemeralContainers, &out.EphemeralContainers
+		*out = make([]corev1alpha1.ContainerView, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDetailSpec.
+func (in *PodDetailSpec) DeepCopy() *PodDetailSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDetailSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDetailStatus) DeepCopyInto(out *PodDetailStatus) {
+	*out = *in
+	in.PodStatus.DeepCopyInto(&out.PodStatus)
+	if in.ContainerViews != nil {
+		in, out := &in.ContainerViews, &out.ContainerViews
+		*out = make([]ContainerViewStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RecentEvents != nil {
+		in, out := &in.RecentEvents, &out.RecentEvents
+		*out = make([]PodViewEvent, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDetailStatus.
+func (in *PodDetailStatus) DeepCopy() *PodDetailStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDetailStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodViewEvent) DeepCopyInto(out *PodViewEvent) {
+	*out = *in
+	in.FirstTimestamp.DeepCopyInto(&out.FirstTimestamp)
+	in.LastTimestamp.DeepCopyInto(&out.LastTimestamp)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodViewEvent.
+func (in *PodViewEvent) DeepCopy() *PodViewEvent {
+	if in == nil {
+		return nil
+	}
+	out := new(PodViewEvent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectResourceCount) DeepCopyInto(out *ProjectResourceCount) {
+	*out = *in
+	out.APIType = in.APIType
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectResourceCount.
+func (in *ProjectResourceCount) DeepCopy() *ProjectResourceCount {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectResourceCount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectSummary) DeepCopyInto(out *ProjectSummary) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSummary.
+func (in *ProjectSummary) DeepCopy() *ProjectSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProjectSummary) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectSummaryList) DeepCopyInto(out *ProjectSummaryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ProjectSummary, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSummaryList.
+func (in *ProjectSummaryList) DeepCopy() *ProjectSummaryList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectSummaryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProjectSummaryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectSummarySpec) DeepCopyInto(out *ProjectSummarySpec) {
+	*out = *in
+	out.Cluster = in.Cluster
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ProjectResourceCount, len(*in))
+		copy(*out, *in)
+	}
+	in.TotalResource.DeepCopyInto(&out.TotalResource)
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = make(corev1alpha1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CVEs != nil {
+		in, out := &in.CVEs, &out.CVEs
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSummarySpec.
+func (in *ProjectSummarySpec) DeepCopy() *ProjectSummarySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectSummarySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaPenalty) DeepCopyInto(out *QuotaPenalty) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaPenalty.
+func (in *QuotaPenalty) DeepCopy() *QuotaPenalty {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaPenalty)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,195 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kmapi "kmodules.xyz/client-go/api/v1"
+)
+
+// +kubebuilder:validation:Enum=OpenCost;Local
+type CostBackend string
+
+const (
+	CostBackendOpenCost CostBackend = "OpenCost"
+	// CostBackendLocal estimates cost from resource requests and a pricing table.
+	CostBackendLocal CostBackend = "Local"
+)
+
+type CostReportResponse struct {
+	// Backend that computed the report.
+	// +optional
+	Backend CostBackend `json:"backend,omitempty"`
+	// Currency of the costs, if known.
+	// +optional
+	Currency string `json:"currency,omitempty"`
+	// Window is the time range covered by the report.
+	Window CostWindow `json:"window"`
+	// Sets contains one allocation set per step of the requested window.
+	// When the request accumulates, there is a single set.
+	Sets []CostAllocationSet `json:"sets"`
+	// Totals sums up the allocations of all the sets.
+	Totals CostTotals `json:"totals"`
+	// Components breaks down the cost of an object to the workloads found in its
+	// resource graph, eg, the StatefulSet of a database and its backup jobs.
+	// +optional
+	Components []ComponentCost `json:"components,omitempty"`
+	// Projects breaks down the cost of the cluster per project, if requested.
+	// +optional
+	Projects []ProjectCost `json:"projects,omitempty"`
+	// Conditions reports the health of the cost backend as of its last background check
+	// and, for the report of an object, whether the cost of its deleted pods is missing.
+	// +optional
+	Conditions []kmapi.Condition `json:"conditions,omitempty"`
+}
+
+const (
+	// ConditionDeletedPodsExcluded is set on the report of an object when the window starts
+	// before the oldest pod of the object. The pods of an object are the ones that exist at
+	// the time of the request, so the cost of deleted pods, eg, of completed backup Jobs or
+	// of old ReplicaSets, is not included.
+	ConditionDeletedPodsExcluded kmapi.ConditionType = "DeletedPodsExcluded"
+
+	ReasonPodsNotFound = "PodsNotFound"
+)
+
+// ProjectUnassigned follows the naming of the __idle__ and __unallocated__ allocations of OpenCost.
+const ProjectUnassigned = "__unassigned__"
+
+type ProjectCost struct {
+	// Project is the name of the project. Namespaces that do not belong to any project
+	// are reported under ProjectUnassigned.
+	Project    string     `json:"project"`
+	Namespaces []string   `json:"namespaces"`
+	Totals     CostTotals `json:"totals"`
+	// IdleCost is the share of the cluster idle cost assigned to the project,
+	// in proportion to its cpu and memory cost.
+	IdleCost float64 `json:"idleCost"`
+}
+
+type ComponentCost struct {
+	// Component is the top level controller of the pods, or the pod itself if it has none.
+	Component kmapi.ObjectID `json:"component"`
+	// Pods lists the pods of the component that have cost allocated in the window.
+	Pods   []string   `json:"pods"`
+	Totals CostTotals `json:"totals"`
+}
+
+type CostWindow struct {
+	// +optional
+	Start *metav1.Time `json:"start,omitempty"`
+	// +optional
+	End *metav1.Time `json:"end,omitempty"`
+}
+
+type CostAllocationSet struct {
+	Window      CostWindow       `json:"window"`
+	Allocations []CostAllocation `json:"allocations"`
+}
+
+type CostAllocation struct {
+	// Name is the aggregated name, eg, the namespace name when aggregated by namespace.
+	Name string `json:"name"`
+	// +optional
+	Properties *CostAllocationProperties `json:"properties,omitempty"`
+	Window     CostWindow                `json:"window"`
+	Minutes    float64                   `json:"minutes"`
+
+	// CPU is measured in cores.
+	CPU ResourceCost `json:"cpu"`
+	// GPU is measured in number of devices.
+	GPU ResourceCost `json:"gpu"`
+	// RAM is measured in bytes.
+	RAM ResourceCost `json:"ram"`
+	// PersistentVolume is measured in bytes.
+	PersistentVolume ResourceCost `json:"persistentVolume"`
+	Network          NetworkCost  `json:"network"`
+	LoadBalancer     ResourceCost `json:"loadBalancer"`
+
+	SharedCost   float64 `json:"sharedCost"`
+	ExternalCost float64 `json:"externalCost"`
+	TotalCost    float64 `json:"totalCost"`
+
+	Efficiency CostEfficiency `json:"efficiency"`
+}
+
+type CostAllocationProperties struct {
+	Cluster        string            `json:"cluster,omitempty"`
+	Node           string            `json:"node,omitempty"`
+	Namespace      string            `json:"namespace,omitempty"`
+	ControllerKind string            `json:"controllerKind,omitempty"`
+	Controller     string            `json:"controller,omitempty"`
+	Pod            string            `json:"pod,omitempty"`
+	Container      string            `json:"container,omitempty"`
+	Services       []string          `json:"services,omitempty"`
+	ProviderID     string            `json:"providerID,omitempty"`
+	Labels         map[string]string `json:"labels,omitempty"`
+	Annotations    map[string]string `json:"annotations,omitempty"`
+}
+
+type ResourceCost struct {
+	// Amount is the average amount of the resource allocated over the window.
+	// +optional
+	Amount float64 `json:"amount,omitempty"`
+	// AmountHours is the amount of the resource multiplied by the hours it was allocated for.
+	// +optional
+	AmountHours float64 `json:"amountHours,omitempty"`
+	// +optional
+	RequestAverage float64 `json:"requestAverage,omitempty"`
+	// +optional
+	UsageAverage float64 `json:"usageAverage,omitempty"`
+	Cost         float64 `json:"cost"`
+	// +optional
+	CostAdjustment float64 `json:"costAdjustment,omitempty"`
+}
+
+type NetworkCost struct {
+	// +optional
+	TransferBytes float64 `json:"transferBytes,omitempty"`
+	// +optional
+	ReceiveBytes float64 `json:"receiveBytes,omitempty"`
+	// +optional
+	CrossZoneCost float64 `json:"crossZoneCost,omitempty"`
+	// +optional
+	CrossRegionCost float64 `json:"crossRegionCost,omitempty"`
+	// +optional
+	InternetCost float64 `json:"internetCost,omitempty"`
+	Cost         float64 `json:"cost"`
+	// +optional
+	CostAdjustment float64 `json:"costAdjustment,omitempty"`
+}
+
+// CostEfficiency is the ratio of usage to request, weighted by cost. 1 means fully utilized.
+type CostEfficiency struct {
+	CPU   float64 `json:"cpu"`
+	RAM   float64 `json:"ram"`
+	Total float64 `json:"total"`
+}
+
+type CostTotals struct {
+	CPUCost              float64 `json:"cpuCost"`
+	GPUCost              float64 `json:"gpuCost"`
+	RAMCost              float64 `json:"ramCost"`
+	PersistentVolumeCost float64 `json:"persistentVolumeCost"`
+	NetworkCost          float64 `json:"networkCost"`
+	LoadBalancerCost     float64 `json:"loadBalancerCost"`
+	SharedCost           float64 `json:"sharedCost"`
+	ExternalCost         float64 `json:"externalCost"`
+	TotalCost            float64 `json:"totalCost"`
+	// TotalEfficiency is the cost weighted efficiency of all the allocations.
+	TotalEfficiency float64 `json:"totalEfficiency"`
+}
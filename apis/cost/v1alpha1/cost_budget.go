@@ -0,0 +1,140 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ResourceKindCostBudget = "CostBudget"
+	ResourceCostBudget     = "costbudget"
+	ResourceCostBudgets    = "costbudgets"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=costbudgets,singular=costbudget,scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Amount",type="number",JSONPath=".spec.monthlyAmount"
+// +kubebuilder:printcolumn:name="Spend",type="number",JSONPath=".status.spend"
+// +kubebuilder:printcolumn:name="Forecast",type="number",JSONPath=".status.forecast"
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// CostBudget tracks the monthly spend of a namespace, a project or the namespaces selected
+// by labels against a budget, and alerts when the spend reaches the thresholds.
+type CostBudget struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              CostBudgetSpec `json:"spec,omitempty"`
+	// +optional
+	Status CostBudgetStatus `json:"status,omitempty"`
+}
+
+// CostBudgetSpec selects the spend counted against the budget. Exactly one of
+// namespace, project and namespaceSelector must be set.
+type CostBudgetSpec struct {
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Project is the name of a project of the configured project source, as reported in
+	// the project costs of a cost report.
+	// +optional
+	Project string `json:"project,omitempty"`
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// MonthlyAmount is the budget for a calendar month, in the currency of the cost backend.
+	// +kubebuilder:validation:Minimum=0
+	MonthlyAmount float64 `json:"monthlyAmount"`
+	// Thresholds are percentages of the monthly amount that raise an alert when
+	// the spend or the forecasted spend reaches them.
+	// +kubebuilder:default={{percent: 80}, {percent: 100}, {percent: 100, forecast: true}}
+	// +optional
+	Thresholds []CostBudgetThreshold `json:"thresholds,omitempty"`
+	// Interval between evaluations of the spend.
+	// +kubebuilder:default="1h"
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+type CostBudgetThreshold struct {
+	// +kubebuilder:validation:Minimum=1
+	Percent int32 `json:"percent"`
+	// Forecast compares the threshold with the spend forecasted for the end of the month
+	// instead of the current spend.
+	// +optional
+	Forecast bool `json:"forecast,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=UnderBudget;AtRisk;OverBudget;Failed
+type CostBudgetPhase string
+
+const (
+	CostBudgetPhaseUnderBudget CostBudgetPhase = "UnderBudget"
+	// CostBudgetPhaseAtRisk means the forecasted spend exceeds the budget.
+	CostBudgetPhaseAtRisk     CostBudgetPhase = "AtRisk"
+	CostBudgetPhaseOverBudget CostBudgetPhase = "OverBudget"
+	// CostBudgetPhaseFailed means the spend could not be evaluated.
+	CostBudgetPhaseFailed CostBudgetPhase = "Failed"
+)
+
+type CostBudgetStatus struct {
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// +optional
+	Phase CostBudgetPhase `json:"phase,omitempty"`
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Backend that computed the spend.
+	// +optional
+	Backend CostBackend `json:"backend,omitempty"`
+	// +optional
+	Currency string `json:"currency,omitempty"`
+	// Period is the calendar month the spend is counted for.
+	// +optional
+	Period CostWindow `json:"period,omitempty"`
+	// Spend since the start of the period.
+	// +optional
+	Spend float64 `json:"spend"`
+	// Forecast is the spend at the end of the period, at the current rate.
+	// +optional
+	Forecast float64 `json:"forecast"`
+	// Alerts are the thresholds reached in the current period.
+	// +optional
+	Alerts []CostBudgetAlert `json:"alerts,omitempty"`
+	// +optional
+	LastEvaluationTime *metav1.Time `json:"lastEvaluationTime,omitempty"`
+}
+
+type CostBudgetAlert struct {
+	CostBudgetThreshold `json:",inline"`
+	TriggeredAt         metav1.Time `json:"triggeredAt"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+type CostBudgetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CostBudget `json:"items,omitempty"`
+}
@@ -0,0 +1,73 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	ResourceKindCostEstimate = "CostEstimate"
+	ResourceCostEstimate     = "costestimate"
+	ResourceCostEstimates    = "costestimates"
+)
+
+// CostEstimate estimates the cost of a manifest before it is created or edited.
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:onlyVerbs=create
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type CostEstimate struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	Request *CostEstimateRequest `json:"request,omitempty"`
+	// +optional
+	Response *CostEstimateResponse `json:"response,omitempty"`
+}
+
+// CostEstimateRequest takes the same input as a ResourceCalculator request.
+type CostEstimateRequest struct {
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Resource *runtime.RawExtension `json:"resource,omitempty"`
+	// Edit compares the cost to the resource running in the cluster.
+	// +optional
+	Edit bool `json:"edit,omitempty"`
+}
+
+// CostEstimateResponse is the cost of running a resource at its requested resources. Resources that
+// are not scheduled yet are priced at the default rates of the cost backend.
+type CostEstimateResponse struct {
+	// Backend whose rates were used for the estimate.
+	Backend CostBackend `json:"backend"`
+	// Currency of the costs, if known.
+	// +optional
+	Currency string  `json:"currency,omitempty"`
+	Hourly   float64 `json:"hourly"`
+	Monthly  float64 `json:"monthly"`
+	// Delta is the change in cost compared to the resource running in the cluster.
+	// It is set for edits and ops requests.
+	// +optional
+	Delta *CostDelta `json:"delta,omitempty"`
+}
+
+type CostDelta struct {
+	Hourly  float64 `json:"hourly"`
+	Monthly float64 `json:"monthly"`
+}
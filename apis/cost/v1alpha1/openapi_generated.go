@@ -392,8 +392,29 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"kmodules.xyz/client-go/api/v1.TypedObjectReference":                 schema_kmodulesxyz_client_go_api_v1_TypedObjectReference(ref),
 		"kmodules.xyz/client-go/api/v1.X509Subject":                          schema_kmodulesxyz_client_go_api_v1_X509Subject(ref),
 		"kmodules.xyz/client-go/api/v1.stringSetMerger":                      schema_kmodulesxyz_client_go_api_v1_stringSetMerger(ref),
+		"kubeops.dev/ui-server/apis/cost/v1alpha1.ComponentCost":             schema_ui_server_apis_cost_v1alpha1_ComponentCost(ref),
+		"kubeops.dev/ui-server/apis/cost/v1alpha1.CostAllocation":            schema_ui_server_apis_cost_v1alpha1_CostAllocation(ref),
+		"kubeops.dev/ui-server/apis/cost/v1alpha1.CostAllocationProperties":  schema_ui_server_apis_cost_v1alpha1_CostAllocationProperties(ref),
+		"kubeops.dev/ui-server/apis/cost/v1alpha1.CostAllocationSet":         schema_ui_server_apis_cost_v1alpha1_CostAllocationSet(ref),
+		"kubeops.dev/ui-server/apis/cost/v1alpha1.CostBudget":                schema_ui_server_apis_cost_v1alpha1_CostBudget(ref),
+		"kubeops.dev/ui-server/apis/cost/v1alpha1.CostBudgetAlert":           schema_ui_server_apis_cost_v1alpha1_CostBudgetAlert(ref),
+		"kubeops.dev/ui-server/apis/cost/v1alpha1.CostBudgetList":            schema_ui_server_apis_cost_v1alpha1_CostBudgetList(ref),
+		"kubeops.dev/ui-server/apis/cost/v1alpha1.CostBudgetSpec":            schema_ui_server_apis_cost_v1alpha1_CostBudgetSpec(ref),
+		"kubeops.dev/ui-server/apis/cost/v1alpha1.CostBudgetStatus":          schema_ui_server_apis_cost_v1alpha1_CostBudgetStatus(ref),
+		"kubeops.dev/ui-server/apis/cost/v1alpha1.CostBudgetThreshold":       schema_ui_server_apis_cost_v1alpha1_CostBudgetThreshold(ref),
+		"kubeops.dev/ui-server/apis/cost/v1alpha1.CostDelta":                 schema_ui_server_apis_cost_v1alpha1_CostDelta(ref),
+		"kubeops.dev/ui-server/apis/cost/v1alpha1.CostEfficiency":            schema_ui_server_apis_cost_v1alpha1_CostEfficiency(ref),
+		"kubeops.dev/ui-server/apis/cost/v1alpha1.CostEstimate":              schema_ui_server_apis_cost_v1alpha1_CostEstimate(ref),
+		"kubeops.dev/ui-server/apis/cost/v1alpha1.CostEstimateRequest":       schema_ui_server_apis_cost_v1alpha1_CostEstimateRequest(ref),
+		"kubeops.dev/ui-server/apis/cost/v1alpha1.CostEstimateResponse":      schema_ui_server_apis_cost_v1alpha1_CostEstimateResponse(ref),
 		"kubeops.dev/ui-server/apis/cost/v1alpha1.CostReport":                schema_ui_server_apis_cost_v1alpha1_CostReport(ref),
 		"kubeops.dev/ui-server/apis/cost/v1alpha1.CostReportRequest":         schema_ui_server_apis_cost_v1alpha1_CostReportRequest(ref),
+		"kubeops.dev/ui-server/apis/cost/v1alpha1.CostReportResponse":        schema_ui_server_apis_cost_v1alpha1_CostReportResponse(ref),
+		"kubeops.dev/ui-server/apis/cost/v1alpha1.CostTotals":                schema_ui_server_apis_cost_v1alpha1_CostTotals(ref),
+		"kubeops.dev/ui-server/apis/cost/v1alpha1.CostWindow":                schema_ui_server_apis_cost_v1alpha1_CostWindow(ref),
+		"kubeops.dev/ui-server/apis/cost/v1alpha1.NetworkCost":               schema_ui_server_apis_cost_v1alpha1_NetworkCost(ref),
+		"kubeops.dev/ui-server/apis/cost/v1alpha1.ProjectCost":               schema_ui_server_apis_cost_v1alpha1_ProjectCost(ref),
+		"kubeops.dev/ui-server/apis/cost/v1alpha1.ResourceCost":              schema_ui_server_apis_cost_v1alpha1_ResourceCost(ref),
 	}
 }
 
@@ -20227,69 +20248,206 @@ func schema_kmodulesxyz_client_go_api_v1_stringSetMerger(ref common.ReferenceCal
 	}
 }
 
-func schema_ui_server_apis_cost_v1alpha1_CostReport(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_ui_server_apis_cost_v1alpha1_ComponentCost(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"component": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Component is the top level controller of the pods, or the pod itself if it has none.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("kmodules.xyz/client-go/api/v1.ObjectID"),
 						},
 					},
-					"apiVersion": {
+					"pods": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Description: "Pods lists the pods of the component that have cost allocated in the window.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"totals": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("kubeops.dev/ui-server/apis/cost/v1alpha1.CostTotals"),
+						},
+					},
+				},
+				Required: []string{"component", "pods", "totals"},
+			},
+		},
+		Dependencies: []string{
+			"kmodules.xyz/client-go/api/v1.ObjectID", "kubeops.dev/ui-server/apis/cost/v1alpha1.CostTotals"},
+	}
+}
+
+func schema_ui_server_apis_cost_v1alpha1_CostAllocation(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the aggregated name, eg, the namespace name when aggregated by namespace.",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"request": {
+					"properties": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("kubeops.dev/ui-server/apis/cost/v1alpha1.CostReportRequest"),
+							Ref: ref("kubeops.dev/ui-server/apis/cost/v1alpha1.CostAllocationProperties"),
 						},
 					},
-					"response": {
+					"window": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("kubeops.dev/ui-server/apis/cost/v1alpha1.CostWindow"),
+						},
+					},
+					"minutes": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"number"},
+							Format:  "double",
+						},
+					},
+					"cpu": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CPU is measured in cores.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("kubeops.dev/ui-server/apis/cost/v1alpha1.ResourceCost"),
+						},
+					},
+					"gpu": {
+						SchemaProps: spec.SchemaProps{
+							Description: "GPU is measured in number of devices.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("kubeops.dev/ui-server/apis/cost/v1alpha1.ResourceCost"),
+						},
+					},
+					"ram": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RAM is measured in bytes.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("kubeops.dev/ui-server/apis/cost/v1alpha1.ResourceCost"),
+						},
+					},
+					"persistentVolume": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1.JSON"),
+							Description: "PersistentVolume is measured in bytes.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("kubeops.dev/ui-server/apis/cost/v1alpha1.ResourceCost"),
+						},
+					},
+					"network": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("kubeops.dev/ui-server/apis/cost/v1alpha1.NetworkCost"),
+						},
+					},
+					"loadBalancer": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("kubeops.dev/ui-server/apis/cost/v1alpha1.ResourceCost"),
+						},
+					},
+					"sharedCost": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"number"},
+							Format:  "double",
+						},
+					},
+					"externalCost": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"number"},
+							Format:  "double",
+						},
+					},
+					"totalCost": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"number"},
+							Format:  "double",
+						},
+					},
+					"efficiency": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("kubeops.dev/ui-server/apis/cost/v1alpha1.CostEfficiency"),
 						},
 					},
 				},
+				Required: []string{"name", "window", "minutes", "cpu", "gpu", "ram", "persistentVolume", "network", "loadBalancer", "sharedCost", "externalCost", "totalCost", "efficiency"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1.JSON", "kubeops.dev/ui-server/apis/cost/v1alpha1.CostReportRequest"},
+			"kubeops.dev/ui-server/apis/cost/v1alpha1.CostAllocationProperties", "kubeops.dev/ui-server/apis/cost/v1alpha1.CostEfficiency", "kubeops.dev/ui-server/apis/cost/v1alpha1.CostWindow", "kubeops.dev/ui-server/apis/cost/v1alpha1.NetworkCost", "kubeops.dev/ui-server/apis/cost/v1alpha1.ResourceCost"},
 	}
 }
 
-func schema_ui_server_apis_cost_v1alpha1_CostReportRequest(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_ui_server_apis_cost_v1alpha1_CostAllocationProperties(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"window": {
+					"cluster": {
 						SchemaProps: spec.SchemaProps{
 							Type:   []string{"string"},
 							Format: "",
 						},
 					},
-					"resolution": {
+					"node": {
 						SchemaProps: spec.SchemaProps{
 							Type:   []string{"string"},
 							Format: "",
 						},
 					},
-					"step": {
+					"namespace": {
 						SchemaProps: spec.SchemaProps{
 							Type:   []string{"string"},
 							Format: "",
 						},
 					},
-					"aggregate": {
+					"controllerKind": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"controller": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"pod": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"container": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"services": {
 						SchemaProps: spec.SchemaProps{
 							Type: []string{"array"},
 							Items: &spec.SchemaOrArray{
@@ -20303,43 +20461,1065 @@ func schema_ui_server_apis_cost_v1alpha1_CostReportRequest(ref common.ReferenceC
 							},
 						},
 					},
-					"includeIdle": {
+					"providerID": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"boolean"},
+							Type:   []string{"string"},
 							Format: "",
 						},
 					},
-					"accumulate": {
+					"labels": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"boolean"},
-							Format: "",
+							Type: []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
-					"accumulateBy": {
+					"annotations": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Type: []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
-					"idleByNode": {
+				},
+			},
+		},
+	}
+}
+
+func schema_ui_server_apis_cost_v1alpha1_CostAllocationSet(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"window": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"boolean"},
-							Format: "",
+							Default: map[string]interface{}{},
+							Ref:     ref("kubeops.dev/ui-server/apis/cost/v1alpha1.CostWindow"),
 						},
 					},
-					"includeProportionalAssetResourceCosts": {
+					"allocations": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"boolean"},
-							Format: "",
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kubeops.dev/ui-server/apis/cost/v1alpha1.CostAllocation"),
+									},
+								},
+							},
 						},
 					},
-					"includeAggregatedMetadata": {
+				},
+				Required: []string{"window", "allocations"},
+			},
+		},
+		Dependencies: []string{
+			"kubeops.dev/ui-server/apis/cost/v1alpha1.CostAllocation", "kubeops.dev/ui-server/apis/cost/v1alpha1.CostWindow"},
+	}
+}
+
+func schema_ui_server_apis_cost_v1alpha1_CostBudget(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CostBudget tracks the monthly spend of a namespace, a project or the namespaces selected by labels against a budget, and alerts when the spend reaches the thresholds.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"boolean"},
-							Format: "",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("kubeops.dev/ui-server/apis/cost/v1alpha1.CostBudgetSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("kubeops.dev/ui-server/apis/cost/v1alpha1.CostBudgetStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "kubeops.dev/ui-server/apis/cost/v1alpha1.CostBudgetSpec", "kubeops.dev/ui-server/apis/cost/v1alpha1.CostBudgetStatus"},
+	}
+}
+
+func schema_ui_server_apis_cost_v1alpha1_CostBudgetAlert(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"percent": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"integer"},
+							Format:  "int32",
+						},
+					},
+					"forecast": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Forecast compares the threshold with the spend forecasted for the end of the month instead of the current spend.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"triggeredAt": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"percent", "triggeredAt"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_ui_server_apis_cost_v1alpha1_CostBudgetList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kubeops.dev/ui-server/apis/cost/v1alpha1.CostBudget"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "kubeops.dev/ui-server/apis/cost/v1alpha1.CostBudget"},
+	}
+}
+
+func schema_ui_server_apis_cost_v1alpha1_CostBudgetSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CostBudgetSpec selects the spend counted against the budget. Exactly one of namespace, project and namespaceSelector must be set.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"project": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Project is the name of a project of the configured project source, as reported in the project costs of a cost report.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"namespaceSelector": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+						},
+					},
+					"monthlyAmount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MonthlyAmount is the budget for a calendar month, in the currency of the cost backend.",
+							Default:     0,
+							Type:        []string{"number"},
+							Format:      "double",
+						},
+					},
+					"thresholds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Thresholds are percentages of the monthly amount that raise an alert when the spend or the forecasted spend reaches them.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kubeops.dev/ui-server/apis/cost/v1alpha1.CostBudgetThreshold"),
+									},
+								},
+							},
+						},
+					},
+					"interval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Interval between evaluations of the spend.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+				Required: []string{"monthlyAmount"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector", "kubeops.dev/ui-server/apis/cost/v1alpha1.CostBudgetThreshold"},
+	}
+}
+
+func schema_ui_server_apis_cost_v1alpha1_CostBudgetStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"observedGeneration": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int64",
+						},
+					},
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"backend": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Backend that computed the spend.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"currency": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"period": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Period is the calendar month the spend is counted for.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("kubeops.dev/ui-server/apis/cost/v1alpha1.CostWindow"),
+						},
+					},
+					"spend": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Spend since the start of the period.",
+							Default:     0,
+							Type:        []string{"number"},
+							Format:      "double",
+						},
+					},
+					"forecast": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Forecast is the spend at the end of the period, at the current rate.",
+							Default:     0,
+							Type:        []string{"number"},
+							Format:      "double",
+						},
+					},
+					"alerts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Alerts are the thresholds reached in the current period.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kubeops.dev/ui-server/apis/cost/v1alpha1.CostBudgetAlert"),
+									},
+								},
+							},
+						},
+					},
+					"lastEvaluationTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time", "kubeops.dev/ui-server/apis/cost/v1alpha1.CostBudgetAlert", "kubeops.dev/ui-server/apis/cost/v1alpha1.CostWindow"},
+	}
+}
+
+func schema_ui_server_apis_cost_v1alpha1_CostBudgetThreshold(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"percent": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"integer"},
+							Format:  "int32",
+						},
+					},
+					"forecast": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Forecast compares the threshold with the spend forecasted for the end of the month instead of the current spend.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"percent"},
+			},
+		},
+	}
+}
+
+func schema_ui_server_apis_cost_v1alpha1_CostDelta(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"hourly": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"number"},
+							Format:  "double",
+						},
+					},
+					"monthly": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"number"},
+							Format:  "double",
+						},
+					},
+				},
+				Required: []string{"hourly", "monthly"},
+			},
+		},
+	}
+}
+
+func schema_ui_server_apis_cost_v1alpha1_CostEfficiency(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CostEfficiency is the ratio of usage to request, weighted by cost. 1 means fully utilized.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"cpu": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"number"},
+							Format:  "double",
+						},
+					},
+					"ram": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"number"},
+							Format:  "double",
+						},
+					},
+					"total": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"number"},
+							Format:  "double",
+						},
+					},
+				},
+				Required: []string{"cpu", "ram", "total"},
+			},
+		},
+	}
+}
+
+func schema_ui_server_apis_cost_v1alpha1_CostEstimate(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"request": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubeops.dev/ui-server/apis/cost/v1alpha1.CostEstimateRequest"),
+						},
+					},
+					"response": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubeops.dev/ui-server/apis/cost/v1alpha1.CostEstimateResponse"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubeops.dev/ui-server/apis/cost/v1alpha1.CostEstimateRequest", "kubeops.dev/ui-server/apis/cost/v1alpha1.CostEstimateResponse"},
+	}
+}
+
+func schema_ui_server_apis_cost_v1alpha1_CostEstimateRequest(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CostEstimateRequest takes the same input as a ResourceCalculator request.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"resource": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/runtime.RawExtension"),
+						},
+					},
+					"edit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Edit compares the cost to the resource running in the cluster.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/runtime.RawExtension"},
+	}
+}
+
+func schema_ui_server_apis_cost_v1alpha1_CostEstimateResponse(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CostEstimateResponse is the cost of running a resource at its requested resources. Resources that are not scheduled yet are priced at the default rates of the cost backend.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"backend": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Backend whose rates were used for the estimate.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"currency": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Currency of the costs, if known.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"hourly": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"number"},
+							Format:  "double",
+						},
+					},
+					"monthly": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"number"},
+							Format:  "double",
+						},
+					},
+					"delta": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Delta is the change in cost compared to the resource running in the cluster. It is set for edits and ops requests.",
+							Ref:         ref("kubeops.dev/ui-server/apis/cost/v1alpha1.CostDelta"),
+						},
+					},
+				},
+				Required: []string{"backend", "hourly", "monthly"},
+			},
+		},
+		Dependencies: []string{
+			"kubeops.dev/ui-server/apis/cost/v1alpha1.CostDelta"},
+	}
+}
+
+func schema_ui_server_apis_cost_v1alpha1_CostReport(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"request": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubeops.dev/ui-server/apis/cost/v1alpha1.CostReportRequest"),
+						},
+					},
+					"response": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubeops.dev/ui-server/apis/cost/v1alpha1.CostReportResponse"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubeops.dev/ui-server/apis/cost/v1alpha1.CostReportRequest", "kubeops.dev/ui-server/apis/cost/v1alpha1.CostReportResponse"},
+	}
+}
+
+func schema_ui_server_apis_cost_v1alpha1_CostReportRequest(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"resource": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("kmodules.xyz/client-go/api/v1.ResourceID"),
+						},
+					},
+					"ref": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("kmodules.xyz/client-go/api/v1.ObjectReference"),
+						},
+					},
+					"projectCost": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ProjectCost reports the cost of the cluster per project of the configured project source. The namespaces of a project are aggregated and the idle cost of the cluster is shared among the projects. It can't be combined with an object or namespace scope.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"window": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"resolution": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"step": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"aggregate": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"includeIdle": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"boolean"},
+							Format: "",
+						},
+					},
+					"accumulate": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"boolean"},
+							Format: "",
+						},
+					},
+					"accumulateBy": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"idleByNode": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"boolean"},
+							Format: "",
+						},
+					},
+					"includeProportionalAssetResourceCosts": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"boolean"},
+							Format: "",
+						},
+					},
+					"includeAggregatedMetadata": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"boolean"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"resource", "ref"},
+			},
+		},
+		Dependencies: []string{
+			"kmodules.xyz/client-go/api/v1.ObjectReference", "kmodules.xyz/client-go/api/v1.ResourceID"},
+	}
+}
+
+func schema_ui_server_apis_cost_v1alpha1_CostReportResponse(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"backend": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Backend that computed the report.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"currency": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Currency of the costs, if known.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"window": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Window is the time range covered by the report.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("kubeops.dev/ui-server/apis/cost/v1alpha1.CostWindow"),
+						},
+					},
+					"sets": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Sets contains one allocation set per step of the requested window. When the request accumulates, there is a single set.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kubeops.dev/ui-server/apis/cost/v1alpha1.CostAllocationSet"),
+									},
+								},
+							},
+						},
+					},
+					"totals": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Totals sums up the allocations of all the sets.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("kubeops.dev/ui-server/apis/cost/v1alpha1.CostTotals"),
+						},
+					},
+					"components": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Components breaks down the cost of an object to the workloads found in its resource graph, eg, the StatefulSet of a database and its backup jobs.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kubeops.dev/ui-server/apis/cost/v1alpha1.ComponentCost"),
+									},
+								},
+							},
+						},
+					},
+					"projects": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Projects breaks down the cost of the cluster per project, if requested.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kubeops.dev/ui-server/apis/cost/v1alpha1.ProjectCost"),
+									},
+								},
+							},
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions reports the health of the cost backend as of its last background check and, for the report of an object, whether the cost of its deleted pods is missing.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kmodules.xyz/client-go/api/v1.Condition"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"window", "sets", "totals"},
+			},
+		},
+		Dependencies: []string{
+			"kmodules.xyz/client-go/api/v1.Condition", "kubeops.dev/ui-server/apis/cost/v1alpha1.ComponentCost", "kubeops.dev/ui-server/apis/cost/v1alpha1.CostAllocationSet", "kubeops.dev/ui-server/apis/cost/v1alpha1.CostTotals", "kubeops.dev/ui-server/apis/cost/v1alpha1.CostWindow", "kubeops.dev/ui-server/apis/cost/v1alpha1.ProjectCost"},
+	}
+}
+
+func schema_ui_server_apis_cost_v1alpha1_CostTotals(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"cpuCost": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"number"},
+							Format:  "double",
+						},
+					},
+					"gpuCost": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"number"},
+							Format:  "double",
+						},
+					},
+					"ramCost": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"number"},
+							Format:  "double",
+						},
+					},
+					"persistentVolumeCost": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"number"},
+							Format:  "double",
+						},
+					},
+					"networkCost": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"number"},
+							Format:  "double",
+						},
+					},
+					"loadBalancerCost": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"number"},
+							Format:  "double",
+						},
+					},
+					"sharedCost": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"number"},
+							Format:  "double",
+						},
+					},
+					"externalCost": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"number"},
+							Format:  "double",
+						},
+					},
+					"totalCost": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"number"},
+							Format:  "double",
+						},
+					},
+					"totalEfficiency": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TotalEfficiency is the cost weighted efficiency of all the allocations.",
+							Default:     0,
+							Type:        []string{"number"},
+							Format:      "double",
+						},
+					},
+				},
+				Required: []string{"cpuCost", "gpuCost", "ramCost", "persistentVolumeCost", "networkCost", "loadBalancerCost", "sharedCost", "externalCost", "totalCost", "totalEfficiency"},
+			},
+		},
+	}
+}
+
+func schema_ui_server_apis_cost_v1alpha1_CostWindow(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"start": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"end": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_ui_server_apis_cost_v1alpha1_NetworkCost(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"transferBytes": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"number"},
+							Format: "double",
+						},
+					},
+					"receiveBytes": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"number"},
+							Format: "double",
+						},
+					},
+					"crossZoneCost": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"number"},
+							Format: "double",
+						},
+					},
+					"crossRegionCost": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"number"},
+							Format: "double",
+						},
+					},
+					"internetCost": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"number"},
+							Format: "double",
+						},
+					},
+					"cost": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"number"},
+							Format:  "double",
+						},
+					},
+					"costAdjustment": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"number"},
+							Format: "double",
+						},
+					},
+				},
+				Required: []string{"cost"},
+			},
+		},
+	}
+}
+
+func schema_ui_server_apis_cost_v1alpha1_ProjectCost(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"project": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Project is the name of the project. Namespaces that do not belong to any project are reported under ProjectUnassigned.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"namespaces": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"totals": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("kubeops.dev/ui-server/apis/cost/v1alpha1.CostTotals"),
+						},
+					},
+					"idleCost": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IdleCost is the share of the cluster idle cost assigned to the project, in proportion to its cpu and memory cost.",
+							Default:     0,
+							Type:        []string{"number"},
+							Format:      "double",
+						},
+					},
+				},
+				Required: []string{"project", "namespaces", "totals", "idleCost"},
+			},
+		},
+		Dependencies: []string{
+			"kubeops.dev/ui-server/apis/cost/v1alpha1.CostTotals"},
+	}
+}
+
+func schema_ui_server_apis_cost_v1alpha1_ResourceCost(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"amount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Amount is the average amount of the resource allocated over the window.",
+							Type:        []string{"number"},
+							Format:      "double",
+						},
+					},
+					"amountHours": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AmountHours is the amount of the resource multiplied by the hours it was allocated for.",
+							Type:        []string{"number"},
+							Format:      "double",
+						},
+					},
+					"requestAverage": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"number"},
+							Format: "double",
+						},
+					},
+					"usageAverage": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"number"},
+							Format: "double",
+						},
+					},
+					"cost": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"number"},
+							Format:  "double",
+						},
+					},
+					"costAdjustment": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"number"},
+							Format: "double",
 						},
 					},
 				},
+				Required: []string{"cost"},
 			},
 		},
 	}
@@ -17,8 +17,8 @@ limitations under the License.
 package v1alpha1
 
 import (
-	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kmapi "kmodules.xyz/client-go/api/v1"
 )
 
 const (
@@ -37,7 +37,7 @@ type CostReport struct {
 	// +optional
 	Request *CostReportRequest `json:"request,omitempty"`
 	// +optional
-	Response *apiextensionsv1.JSON `json:"response,omitempty"`
+	Response *CostReportResponse `json:"response,omitempty"`
 }
 
 type AccumulateOption string
@@ -53,6 +53,18 @@ const (
 )
 
 type CostReportRequest struct {
+	// ObjectInfo scopes the report to a namespace or to the resource graph of an object.
+	// The cost of the whole cluster is reported, if it is not set. The cost of an object
+	// only includes the pods that exist at the time of the request, the response has the
+	// DeletedPodsExcluded condition if its window starts before them.
+	kmapi.ObjectInfo `json:",inline" schema:"-"`
+	// ProjectCost reports the cost of the cluster per project of the configured project source.
+	// The namespaces of a project are aggregated and the idle cost of the cluster is shared
+	// among the projects.
+	// It can't be combined with an object or namespace scope.
+	// +optional
+	ProjectCost bool `json:"projectCost,omitempty" schema:"-"`
+
 	Window                                string           `json:"window,omitempty" schema:"window,omitempty"`
 	Resolution                            string           `json:"resolution,omitempty" schema:"resolution,omitempty"`
 	Step                                  string           `json:"step,omitempty" schema:"step,omitempty"`
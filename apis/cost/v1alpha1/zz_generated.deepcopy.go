@@ -22,10 +22,385 @@ limitations under the License.
 package v1alpha1
 
 import (
-	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	apiv1 "kmodules.xyz/client-go/api/v1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentCost) DeepCopyInto(out *ComponentCost) {
+	*out = *in
+	out.Component = in.Component
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.Totals = in.Totals
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentCost.
+func (in *ComponentCost) DeepCopy() *ComponentCost {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentCost)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostAllocation) DeepCopyInto(out *CostAllocation) {
+	*out = *in
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = new(CostAllocationProperties)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Window.DeepCopyInto(&out.Window)
+	out.CPU = in.CPU
+	out.GPU = in.GPU
+	out.RAM = in.RAM
+	out.PersistentVolume = in.PersistentVolume
+	out.Network = in.Network
+	out.LoadBalancer = in.LoadBalancer
+	out.Efficiency = in.Efficiency
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostAllocation.
+func (in *CostAllocation) DeepCopy() *CostAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(CostAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostAllocationProperties) DeepCopyInto(out *CostAllocationProperties) {
+	*out = *in
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostAllocationProperties.
+func (in *CostAllocationProperties) DeepCopy() *CostAllocationProperties {
+	if in == nil {
+		return nil
+	}
+	out := new(CostAllocationProperties)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostAllocationSet) DeepCopyInto(out *CostAllocationSet) {
+	*out = *in
+	in.Window.DeepCopyInto(&out.Window)
+	if in.Allocations != nil {
+		in, out := &in.Allocations, &out.Allocations
+		*out = make([]CostAllocation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostAllocationSet.
+func (in *CostAllocationSet) DeepCopy() *CostAllocationSet {
+	if in == nil {
+		return nil
+	}
+	out := new(CostAllocationSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostBudget) DeepCopyInto(out *CostBudget) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostBudget.
+func (in *CostBudget) DeepCopy() *CostBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(CostBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CostBudget) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostBudgetAlert) DeepCopyInto(out *CostBudgetAlert) {
+	*out = *in
+	out.CostBudgetThreshold = in.CostBudgetThreshold
+	in.TriggeredAt.DeepCopyInto(&out.TriggeredAt)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostBudgetAlert.
+func (in *CostBudgetAlert) DeepCopy() *CostBudgetAlert {
+	if in == nil {
+		return nil
+	}
+	out := new(CostBudgetAlert)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostBudgetList) DeepCopyInto(out *CostBudgetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CostBudget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostBudgetList.
+func (in *CostBudgetList) DeepCopy() *CostBudgetList {
+	if in == nil {
+		return nil
+	}
+	out := new(CostBudgetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CostBudgetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostBudgetSpec) DeepCopyInto(out *CostBudgetSpec) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Thresholds != nil {
+		in, out := &in.Thresholds, &out.Thresholds
+		*out = make([]CostBudgetThreshold, len(*in))
+		copy(*out, *in)
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostBudgetSpec.
+func (in *CostBudgetSpec) DeepCopy() *CostBudgetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CostBudgetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostBudgetStatus) DeepCopyInto(out *CostBudgetStatus) {
+	*out = *in
+	in.Period.DeepCopyInto(&out.Period)
+	if in.Alerts != nil {
+		in, out := &in.Alerts, &out.Alerts
+		*out = make([]CostBudgetAlert, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastEvaluationTime != nil {
+		in, out := &in.LastEvaluationTime, &out.LastEvaluationTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostBudgetStatus.
+func (in *CostBudgetStatus) DeepCopy() *CostBudgetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CostBudgetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostBudgetThreshold) DeepCopyInto(out *CostBudgetThreshold) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostBudgetThreshold.
+func (in *CostBudgetThreshold) DeepCopy() *CostBudgetThreshold {
+	if in == nil {
+		return nil
+	}
+	out := new(CostBudgetThreshold)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostDelta) DeepCopyInto(out *CostDelta) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostDelta.
+func (in *CostDelta) DeepCopy() *CostDelta {
+	if in == nil {
+		return nil
+	}
+	out := new(CostDelta)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostEfficiency) DeepCopyInto(out *CostEfficiency) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostEfficiency.
+func (in *CostEfficiency) DeepCopy() *CostEfficiency {
+	if in == nil {
+		return nil
+	}
+	out := new(CostEfficiency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostEstimate) DeepCopyInto(out *CostEstimate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.Request != nil {
+		in, out := &in.Request, &out.Request
+		*out = new(CostEstimateRequest)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Response != nil {
+		in, out := &in.Response, &out.Response
+		*out = new(CostEstimateResponse)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostEstimate.
+func (in *CostEstimate) DeepCopy() *CostEstimate {
+	if in == nil {
+		return nil
+	}
+	out := new(CostEstimate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CostEstimate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostEstimateRequest) DeepCopyInto(out *CostEstimateRequest) {
+	*out = *in
+	if in.Resource != nil {
+		in, out := &in.Resource, &out.Resource
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostEstimateRequest.
+func (in *CostEstimateRequest) DeepCopy() *CostEstimateRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(CostEstimateRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostEstimateResponse) DeepCopyInto(out *CostEstimateResponse) {
+	*out = *in
+	if in.Delta != nil {
+		in, out := &in.Delta, &out.Delta
+		*out = new(CostDelta)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostEstimateResponse.
+func (in *CostEstimateResponse) DeepCopy() *CostEstimateResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(CostEstimateResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CostReport) DeepCopyInto(out *CostReport) {
 	*out = *in
@@ -37,7 +412,7 @@ func (in *CostReport) DeepCopyInto(out *CostReport) {
 	}
 	if in.Response != nil {
 		in, out := &in.Response, &out.Response
-		*out = new(v1.JSON)
+		*out = new(CostReportResponse)
 		(*in).DeepCopyInto(*out)
 	}
 	return
@@ -64,6 +439,7 @@ func (in *CostReport) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CostReportRequest) DeepCopyInto(out *CostReportRequest) {
 	*out = *in
+	out.ObjectInfo = in.ObjectInfo
 	if in.Aggregate != nil {
 		in, out := &in.Aggregate, &out.Aggregate
 		*out = make([]string, len(*in))
@@ -81,3 +457,143 @@ func (in *CostReportRequest) DeepCopy() *CostReportRequest {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostReportResponse) DeepCopyInto(out *CostReportResponse) {
+	*out = *in
+	in.Window.DeepCopyInto(&out.Window)
+	if in.Sets != nil {
+		in, out := &in.Sets, &out.Sets
+		*out = make([]CostAllocationSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.Totals = in.Totals
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentCost, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Projects != nil {
+		in, out := &in.Projects, &out.Projects
+		*out = make([]ProjectCost, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]apiv1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostReportResponse.
+func (in *CostReportResponse) DeepCopy() *CostReportResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(CostReportResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostTotals) DeepCopyInto(out *CostTotals) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostTotals.
+func (in *CostTotals) DeepCopy() *CostTotals {
+	if in == nil {
+		return nil
+	}
+	out := new(CostTotals)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostWindow) DeepCopyInto(out *CostWindow) {
+	*out = *in
+	if in.Start != nil {
+		in, out := &in.Start, &out.Start
+		*out = (*in).DeepCopy()
+	}
+	if in.End != nil {
+		in, out := &in.End, &out.End
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostWindow.
+func (in *CostWindow) DeepCopy() *CostWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(CostWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkCost) DeepCopyInto(out *NetworkCost) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkCost.
+func (in *NetworkCost) DeepCopy() *NetworkCost {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkCost)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectCost) DeepCopyInto(out *ProjectCost) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.Totals = in.Totals
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectCost.
+func (in *ProjectCost) DeepCopy() *ProjectCost {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectCost)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceCost) DeepCopyInto(out *ResourceCost) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceCost.
+func (in *ResourceCost) DeepCopy() *ResourceCost {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceCost)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,55 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	core "k8s.io/api/core/v1"
+)
+
+// AnnotationNodeInventory is set on SiteInfo to the JSON encoded NodeInventory of the cluster.
+const AnnotationNodeInventory = "identity.k8s.appscode.com/node-inventory"
+
+// NodeInventory breaks down the nodes of a cluster. The maps count the nodes by each value.
+type NodeInventory struct {
+	ControlPlane int `json:"controlPlane"`
+	Workers      int `json:"workers"`
+	// +optional
+	Architectures map[string]int `json:"architectures,omitempty"`
+	// +optional
+	OperatingSystems map[string]int `json:"operatingSystems,omitempty"`
+	// +optional
+	OSImages map[string]int `json:"osImages,omitempty"`
+	// +optional
+	KernelVersions map[string]int `json:"kernelVersions,omitempty"`
+	// +optional
+	ContainerRuntimeVersions map[string]int `json:"containerRuntimeVersions,omitempty"`
+	// +optional
+	KubeletVersions map[string]int `json:"kubeletVersions,omitempty"`
+	// KubeletVersionSkew is the number of minor versions between the oldest and the newest kubelet.
+	// +optional
+	KubeletVersionSkew int `json:"kubeletVersionSkew,omitempty"`
+	// +optional
+	InstanceTypes map[string]int `json:"instanceTypes,omitempty"`
+	// +optional
+	Zones map[string]int `json:"zones,omitempty"`
+	// +optional
+	Regions map[string]int `json:"regions,omitempty"`
+	// ExtendedResources is the total capacity of the resources other than cpu, memory, storage,
+	// pods and hugepages, eg, nvidia.com/gpu.
+	// +optional
+	ExtendedResources core.ResourceList `json:"extendedResources,omitempty"`
+}
@@ -0,0 +1,93 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	rbac "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ResourceKindResourceAccessReview = "ResourceAccessReview"
+	ResourceResourceAccessReview     = "resourceaccessreview"
+	ResourceResourceAccessReviews    = "resourceaccessreviews"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:onlyVerbs=create
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ResourceAccessReview finds the users, groups and ServiceAccounts that RBAC allows to access an object.
+type ResourceAccessReview struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ResourceAccessReviewSpec `json:"spec"`
+	// +optional
+	Status ResourceAccessReviewStatus `json:"status,omitempty"`
+}
+
+type ResourceAccessReviewSpec struct {
+	// +optional
+	Group    string `json:"group,omitempty"`
+	Resource string `json:"resource"`
+	// +optional
+	Subresource string `json:"subresource,omitempty"`
+	// Namespace of the object. Empty for cluster scoped objects.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Verbs to review. Defaults to get, update and delete.
+	// +optional
+	Verbs []string `json:"verbs,omitempty"`
+}
+
+type ResourceAccessReviewStatus struct {
+	// +optional
+	Verbs []ResourceAccessVerb `json:"verbs,omitempty"`
+	// EvaluationError is set if some roles could not be resolved. The subjects found using
+	// the other roles are still returned.
+	// +optional
+	EvaluationError string `json:"evaluationError,omitempty"`
+}
+
+type ResourceAccessVerb struct {
+	Verb string `json:"verb"`
+	// +optional
+	Subjects []AccessSubject `json:"subjects,omitempty"`
+}
+
+// AccessSubject is a subject that is allowed a verb, with the bindings that grant it.
+// Subjects without grants are allowed regardless of RBAC, eg, the system:masters group.
+type AccessSubject struct {
+	rbac.Subject `json:",inline"`
+	// +optional
+	Grants []AccessGrant `json:"grants,omitempty"`
+}
+
+// AccessGrant is a RoleBinding or ClusterRoleBinding and the role it binds.
+type AccessGrant struct {
+	// Kind is RoleBinding or ClusterRoleBinding.
+	Kind string `json:"kind"`
+	// +optional
+	Namespace string       `json:"namespace,omitempty"`
+	Name      string       `json:"name"`
+	RoleRef   rbac.RoleRef `json:"roleRef"`
+}
@@ -0,0 +1,91 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ResourceKindSelfSubjectAccessMatrix = "SelfSubjectAccessMatrix"
+	ResourceSelfSubjectAccessMatrix     = "selfsubjectaccessmatrix"
+	ResourceSelfSubjectAccessMatrices   = "selfsubjectaccessmatrices"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:onlyVerbs=create
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SelfSubjectAccessMatrix checks in one call which of the verbs the current user may perform
+// on each of the resources.
+type SelfSubjectAccessMatrix struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec SelfSubjectAccessMatrixSpec `json:"spec"`
+	// +optional
+	Status SelfSubjectAccessMatrixStatus `json:"status,omitempty"`
+}
+
+type SelfSubjectAccessMatrixSpec struct {
+	// Resources are the objects or resources to check.
+	Resources []AccessMatrixResource `json:"resources"`
+	// Verbs are checked for every resource.
+	Verbs []AccessMatrixVerb `json:"verbs"`
+}
+
+// AccessMatrixResource identifies a resource, or an object if the name is set.
+type AccessMatrixResource struct {
+	// +optional
+	Group string `json:"group,omitempty"`
+	// +optional
+	Version  string `json:"version,omitempty"`
+	Resource string `json:"resource"`
+	// Namespace of the objects. Empty means all namespaces for namespaced resources.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// AccessMatrixVerb is a verb on a resource or on one of its subresources, eg, create on pods/exec.
+type AccessMatrixVerb struct {
+	Verb string `json:"verb"`
+	// +optional
+	Subresource string `json:"subresource,omitempty"`
+}
+
+type SelfSubjectAccessMatrixStatus struct {
+	// Resources has a row for every resource in the spec, in the same order.
+	// +optional
+	Resources []AccessMatrixRow `json:"resources,omitempty"`
+}
+
+type AccessMatrixRow struct {
+	AccessMatrixResource `json:",inline"`
+	// Verbs has a decision for every verb in the spec, in the same order.
+	Verbs []AccessMatrixDecision `json:"verbs"`
+}
+
+type AccessMatrixDecision struct {
+	AccessMatrixVerb `json:",inline"`
+	Allowed          bool `json:"allowed"`
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
@@ -0,0 +1,22 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1alpha1 contains the CRDs of the ui-server in the management.k8s.appscode.com
+// API group, next to the ProjectQuota CRD.
+
+// +k8s:deepcopy-gen=package
+// +groupName=management.k8s.appscode.com
+package v1alpha1 // import "kubeops.dev/ui-server/apis/management/v1alpha1"
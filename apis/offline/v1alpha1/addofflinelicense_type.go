@@ -43,9 +43,18 @@ type AddOfflineLicense struct {
 type AddOfflineLicenseRequest struct {
 	Namespace string `json:"namespace"`
 	License   string `json:"license"`
+	// PruneExpired removes the expired licenses of other products from the license Secret.
+	// +optional
+	PruneExpired bool `json:"pruneExpired,omitempty"`
 }
 
 type AddOfflineLicenseResponse struct {
 	// +optional
 	SecretKeyRef *core.SecretKeySelector `json:"secretKeyRef,omitempty"`
+	// Pruned lists the products whose expired licenses were removed.
+	// +optional
+	Pruned []string `json:"pruned,omitempty"`
+	// Review explains whether the license would work in this cluster. It is only set for dry-run requests.
+	// +optional
+	Review *OfflineLicenseReviewResponse `json:"review,omitempty"`
 }
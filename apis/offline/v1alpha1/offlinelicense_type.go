@@ -41,7 +41,16 @@ type OfflineLicense struct {
 	metav1.TypeMeta `json:",inline"`
 	// +optional
 	metav1.ObjectMeta `json:"metadata,omitempty"`
-	Status            OfflineLicenseStatus `json:"status,omitempty"`
+	// +optional
+	Spec   OfflineLicenseSpec   `json:"spec,omitempty"`
+	Status OfflineLicenseStatus `json:"status,omitempty"`
+}
+
+// OfflineLicenseSpec is only set in requests to replace the license of a product.
+type OfflineLicenseSpec struct {
+	// License is the new license of the product. It is never returned by the server.
+	// +optional
+	License string `json:"license,omitempty"`
 }
 
 // OfflineLicenseStatus defines the observed state of OfflineLicense
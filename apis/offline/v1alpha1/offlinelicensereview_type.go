@@ -0,0 +1,93 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	licenseapi "go.bytebuilders.dev/license-verifier/apis/licenses/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ResourceKindOfflineLicenseReview = "OfflineLicenseReview"
+	ResourceOfflineLicenseReview     = "offlinelicensereview"
+	ResourceOfflineLicenseReviews    = "offlinelicensereviews"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:onlyVerbs=create
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// OfflineLicenseReview checks whether a license would work in this cluster, without storing it.
+type OfflineLicenseReview struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	Request *OfflineLicenseReviewRequest `json:"request,omitempty"`
+	// +optional
+	Response *OfflineLicenseReviewResponse `json:"response,omitempty"`
+}
+
+type OfflineLicenseReviewRequest struct {
+	License string `json:"license"`
+	// Product is the expected product of the license, eg, kubedb-enterprise.
+	// +optional
+	Product string `json:"product,omitempty"`
+	// Features that the license must include.
+	// +optional
+	Features []string `json:"features,omitempty"`
+}
+
+type OfflineLicenseReviewResponse struct {
+	// Valid is true if the license can be used in this cluster.
+	Valid bool `json:"valid"`
+	// Product is the key the license would be stored under in the license Secret.
+	// +optional
+	Product string `json:"product,omitempty"`
+	// ClusterUID is the UID of this cluster.
+	ClusterUID string `json:"clusterUID"`
+	// +optional
+	License *licenseapi.License `json:"license,omitempty"`
+	// +optional
+	Diagnostics []LicenseDiagnostic `json:"diagnostics,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=InvalidFormat;ClusterMismatch;Expired;NotYetValid;ProductMismatch;MissingFeature;UntrustedIssuer;VerificationFailed
+type LicenseDiagnosticType string
+
+const (
+	LicenseDiagnosticInvalidFormat      LicenseDiagnosticType = "InvalidFormat"
+	LicenseDiagnosticClusterMismatch    LicenseDiagnosticType = "ClusterMismatch"
+	LicenseDiagnosticExpired            LicenseDiagnosticType = "Expired"
+	LicenseDiagnosticNotYetValid        LicenseDiagnosticType = "NotYetValid"
+	LicenseDiagnosticProductMismatch    LicenseDiagnosticType = "ProductMismatch"
+	LicenseDiagnosticMissingFeature     LicenseDiagnosticType = "MissingFeature"
+	LicenseDiagnosticUntrustedIssuer    LicenseDiagnosticType = "UntrustedIssuer"
+	LicenseDiagnosticVerificationFailed LicenseDiagnosticType = "VerificationFailed"
+)
+
+// LicenseDiagnostic describes one reason a license would not work.
+type LicenseDiagnostic struct {
+	Type LicenseDiagnosticType `json:"type"`
+	// Field of the license that does not match, eg, clusters or notAfter.
+	// +optional
+	Field string `json:"field,omitempty"`
+	// +optional
+	Expected string `json:"expected,omitempty"`
+	// +optional
+	Actual  string `json:"actual,omitempty"`
+	Message string `json:"message"`
+}
@@ -50,6 +50,7 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&AddOfflineLicense{},
 		&OfflineLicense{},
 		&OfflineLicenseList{},
+		&OfflineLicenseReview{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -22,6 +22,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	licensesv1alpha1 "go.bytebuilders.dev/license-verifier/apis/licenses/v1alpha1"
 	v1 "k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -85,6 +86,16 @@ func (in *AddOfflineLicenseResponse) DeepCopyInto(out *AddOfflineLicenseResponse
 		*out = new(v1.SecretKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Pruned != nil {
+		in, out := &in.Pruned, &out.Pruned
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Review != nil {
+		in, out := &in.Review, &out.Review
+		*out = new(OfflineLicenseReviewResponse)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -98,11 +109,28 @@ func (in *AddOfflineLicenseResponse) DeepCopy() *AddOfflineLicenseResponse {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LicenseDiagnostic) DeepCopyInto(out *LicenseDiagnostic) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LicenseDiagnostic.
+func (in *LicenseDiagnostic) DeepCopy() *LicenseDiagnostic {
+	if in == nil {
+		return nil
+	}
+	out := new(LicenseDiagnostic)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OfflineLicense) DeepCopyInto(out *OfflineLicense) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
@@ -158,6 +186,104 @@ func (in *OfflineLicenseList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OfflineLicenseReview) DeepCopyInto(out *OfflineLicenseReview) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.Request != nil {
+		in, out := &in.Request, &out.Request
+		*out = new(OfflineLicenseReviewRequest)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Response != nil {
+		in, out := &in.Response, &out.Response
+		*out = new(OfflineLicenseReviewResponse)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OfflineLicenseReview.
+func (in *OfflineLicenseReview) DeepCopy() *OfflineLicenseReview {
+	if in == nil {
+		return nil
+	}
+	out := new(OfflineLicenseReview)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OfflineLicenseReview) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OfflineLicenseReviewRequest) DeepCopyInto(out *OfflineLicenseReviewRequest) {
+	*out = *in
+	if in.Features != nil {
+		in, out := &in.Features, &out.Features
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OfflineLicenseReviewRequest.
+func (in *OfflineLicenseReviewRequest) DeepCopy() *OfflineLicenseReviewRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(OfflineLicenseReviewRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OfflineLicenseReviewResponse) DeepCopyInto(out *OfflineLicenseReviewResponse) {
+	*out = *in
+	if in.License != nil {
+		in, out := &in.License, &out.License
+		*out = new(licensesv1alpha1.License)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Diagnostics != nil {
+		in, out := &in.Diagnostics, &out.Diagnostics
+		*out = make([]LicenseDiagnostic, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OfflineLicenseReviewResponse.
+func (in *OfflineLicenseReviewResponse) DeepCopy() *OfflineLicenseReviewResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(OfflineLicenseReviewResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OfflineLicenseSpec) DeepCopyInto(out *OfflineLicenseSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OfflineLicenseSpec.
+func (in *OfflineLicenseSpec) DeepCopy() *OfflineLicenseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OfflineLicenseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OfflineLicenseStatus) DeepCopyInto(out *OfflineLicenseStatus) {
 	*out = *in
@@ -30,5 +30,11 @@ var Funcs = func(codecs runtimeserializer.CodecFactory) []any {
 		func(s *v1alpha1.PolicyReport, c randfill.Continue) {
 			c.Fill(s) // fuzz self without calling this function again
 		},
+		func(s *v1alpha1.PolicyTrend, c randfill.Continue) {
+			c.Fill(s) // fuzz self without calling this function again
+		},
+		func(s *v1alpha1.PolicyCheck, c randfill.Continue) {
+			c.Fill(s) // fuzz self without calling this function again
+		},
 	}
 }
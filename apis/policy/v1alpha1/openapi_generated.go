@@ -392,11 +392,21 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"kmodules.xyz/client-go/api/v1.TypedObjectReference":                 schema_kmodulesxyz_client_go_api_v1_TypedObjectReference(ref),
 		"kmodules.xyz/client-go/api/v1.X509Subject":                          schema_kmodulesxyz_client_go_api_v1_X509Subject(ref),
 		"kmodules.xyz/client-go/api/v1.stringSetMerger":                      schema_kmodulesxyz_client_go_api_v1_stringSetMerger(ref),
+		"kubeops.dev/ui-server/apis/policy/v1alpha1.ApplicableConstraint":    schema_ui_server_apis_policy_v1alpha1_ApplicableConstraint(ref),
 		"kubeops.dev/ui-server/apis/policy/v1alpha1.Constraint":              schema_ui_server_apis_policy_v1alpha1_Constraint(ref),
+		"kubeops.dev/ui-server/apis/policy/v1alpha1.ExportedReport":          schema_ui_server_apis_policy_v1alpha1_ExportedReport(ref),
+		"kubeops.dev/ui-server/apis/policy/v1alpha1.PolicyCheck":             schema_ui_server_apis_policy_v1alpha1_PolicyCheck(ref),
+		"kubeops.dev/ui-server/apis/policy/v1alpha1.PolicyCheckRequest":      schema_ui_server_apis_policy_v1alpha1_PolicyCheckRequest(ref),
+		"kubeops.dev/ui-server/apis/policy/v1alpha1.PolicyCheckResponse":     schema_ui_server_apis_policy_v1alpha1_PolicyCheckResponse(ref),
 		"kubeops.dev/ui-server/apis/policy/v1alpha1.PolicyReport":            schema_ui_server_apis_policy_v1alpha1_PolicyReport(ref),
 		"kubeops.dev/ui-server/apis/policy/v1alpha1.PolicyReportRequest":     schema_ui_server_apis_policy_v1alpha1_PolicyReportRequest(ref),
 		"kubeops.dev/ui-server/apis/policy/v1alpha1.PolicyReportResponse":    schema_ui_server_apis_policy_v1alpha1_PolicyReportResponse(ref),
+		"kubeops.dev/ui-server/apis/policy/v1alpha1.PolicyTrend":             schema_ui_server_apis_policy_v1alpha1_PolicyTrend(ref),
+		"kubeops.dev/ui-server/apis/policy/v1alpha1.PolicyTrendRequest":      schema_ui_server_apis_policy_v1alpha1_PolicyTrendRequest(ref),
+		"kubeops.dev/ui-server/apis/policy/v1alpha1.PolicyTrendResponse":     schema_ui_server_apis_policy_v1alpha1_PolicyTrendResponse(ref),
 		"kubeops.dev/ui-server/apis/policy/v1alpha1.StatusViolation":         schema_ui_server_apis_policy_v1alpha1_StatusViolation(ref),
+		"kubeops.dev/ui-server/apis/policy/v1alpha1.ViolationPoint":          schema_ui_server_apis_policy_v1alpha1_ViolationPoint(ref),
+		"kubeops.dev/ui-server/apis/policy/v1alpha1.ViolationSeries":         schema_ui_server_apis_policy_v1alpha1_ViolationSeries(ref),
 	}
 }
 
@@ -20230,6 +20240,71 @@ func schema_kmodulesxyz_client_go_api_v1_stringSetMerger(ref common.ReferenceCal
 	}
 }
 
+func schema_ui_server_apis_policy_v1alpha1_ApplicableConstraint(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"gvr": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/runtime/schema.GroupVersionResource"),
+						},
+					},
+					"enforcementAction": {
+						SchemaProps: spec.SchemaProps{
+							Description: "EnforcementAction is the spec.enforcementAction of the constraint.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"enforcementActions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "EnforcementActions lists the actions taken by the admission webhook when EnforcementAction is scoped.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"violations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Violations reported by the last audit run for an object with the same name.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kubeops.dev/ui-server/apis/policy/v1alpha1.StatusViolation"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"name", "gvr", "enforcementAction"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/runtime/schema.GroupVersionResource", "kubeops.dev/ui-server/apis/policy/v1alpha1.StatusViolation"},
+	}
+}
+
 func schema_ui_server_apis_policy_v1alpha1_Constraint(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -20267,6 +20342,7 @@ func schema_ui_server_apis_policy_v1alpha1_Constraint(ref common.ReferenceCallba
 						},
 					},
 				},
+				Required: []string{"auditTimestamp", "gvr"},
 			},
 		},
 		Dependencies: []string{
@@ -20274,6 +20350,125 @@ func schema_ui_server_apis_policy_v1alpha1_Constraint(ref common.ReferenceCallba
 	}
 }
 
+func schema_ui_server_apis_policy_v1alpha1_ExportedReport(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"format": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"contentType": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"data": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+				},
+				Required: []string{"format", "contentType", "data"},
+			},
+		},
+	}
+}
+
+func schema_ui_server_apis_policy_v1alpha1_PolicyCheck(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PolicyCheck finds the Gatekeeper constraints that would apply to a manifest before it is submitted to the cluster.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"request": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubeops.dev/ui-server/apis/policy/v1alpha1.PolicyCheckRequest"),
+						},
+					},
+					"response": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubeops.dev/ui-server/apis/policy/v1alpha1.PolicyCheckResponse"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubeops.dev/ui-server/apis/policy/v1alpha1.PolicyCheckRequest", "kubeops.dev/ui-server/apis/policy/v1alpha1.PolicyCheckResponse"},
+	}
+}
+
+func schema_ui_server_apis_policy_v1alpha1_PolicyCheckRequest(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"resource": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/runtime.RawExtension"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/runtime.RawExtension"},
+	}
+}
+
+func schema_ui_server_apis_policy_v1alpha1_PolicyCheckResponse(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"constraints": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kubeops.dev/ui-server/apis/policy/v1alpha1.ApplicableConstraint"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubeops.dev/ui-server/apis/policy/v1alpha1.ApplicableConstraint"},
+	}
+}
+
 func schema_ui_server_apis_policy_v1alpha1_PolicyReport(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -20330,6 +20525,13 @@ func schema_ui_server_apis_policy_v1alpha1_PolicyReportRequest(ref common.Refere
 							Ref:     ref("kmodules.xyz/client-go/api/v1.ObjectReference"),
 						},
 					},
+					"exportFormat": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ExportFormat renders the report as a file in the given format in response.export",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"resource", "ref"},
 			},
@@ -20358,11 +20560,129 @@ func schema_ui_server_apis_policy_v1alpha1_PolicyReportResponse(ref common.Refer
 							},
 						},
 					},
+					"export": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubeops.dev/ui-server/apis/policy/v1alpha1.ExportedReport"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubeops.dev/ui-server/apis/policy/v1alpha1.Constraint", "kubeops.dev/ui-server/apis/policy/v1alpha1.ExportedReport"},
+	}
+}
+
+func schema_ui_server_apis_policy_v1alpha1_PolicyTrend(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"request": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubeops.dev/ui-server/apis/policy/v1alpha1.PolicyTrendRequest"),
+						},
+					},
+					"response": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("kubeops.dev/ui-server/apis/policy/v1alpha1.PolicyTrendResponse"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"kubeops.dev/ui-server/apis/policy/v1alpha1.PolicyTrendRequest", "kubeops.dev/ui-server/apis/policy/v1alpha1.PolicyTrendResponse"},
+	}
+}
+
+func schema_ui_server_apis_policy_v1alpha1_PolicyTrendRequest(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"resource": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("kmodules.xyz/client-go/api/v1.ResourceID"),
+						},
+					},
+					"ref": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("kmodules.xyz/client-go/api/v1.ObjectReference"),
+						},
+					},
+					"since": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Since drops audit runs older than the given time from the returned series.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"resource", "ref"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time", "kmodules.xyz/client-go/api/v1.ObjectReference", "kmodules.xyz/client-go/api/v1.ResourceID"},
+	}
+}
+
+func schema_ui_server_apis_policy_v1alpha1_PolicyTrendResponse(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"series": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Series breaks the violations down by namespace. Gatekeeper lists a limited number of violations per constraint (20 by default), so these counts are capped.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kubeops.dev/ui-server/apis/policy/v1alpha1.ViolationSeries"),
+									},
+								},
+							},
+						},
+					},
+					"totals": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Totals is the total number of violations of each constraint reported by Gatekeeper, with an empty namespace. It is only returned for cluster wide requests.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kubeops.dev/ui-server/apis/policy/v1alpha1.ViolationSeries"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"kubeops.dev/ui-server/apis/policy/v1alpha1.Constraint"},
+			"kubeops.dev/ui-server/apis/policy/v1alpha1.ViolationSeries"},
 	}
 }
 
@@ -20440,3 +20760,78 @@ func schema_ui_server_apis_policy_v1alpha1_StatusViolation(ref common.ReferenceC
 		},
 	}
 }
+
+func schema_ui_server_apis_policy_v1alpha1_ViolationPoint(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"auditTimestamp": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"violations": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"integer"},
+							Format:  "int32",
+						},
+					},
+				},
+				Required: []string{"auditTimestamp", "violations"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_ui_server_apis_policy_v1alpha1_ViolationSeries(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ViolationSeries is the number of violations of a constraint, in a single namespace or cluster-scoped objects (empty namespace), over the recorded audit runs.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"constraint": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"gvr": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/runtime/schema.GroupVersionResource"),
+						},
+					},
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"points": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("kubeops.dev/ui-server/apis/policy/v1alpha1.ViolationPoint"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"constraint", "gvr", "points"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/runtime/schema.GroupVersionResource", "kubeops.dev/ui-server/apis/policy/v1alpha1.ViolationPoint"},
+	}
+}
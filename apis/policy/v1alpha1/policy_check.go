@@ -0,0 +1,67 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	ResourceKindPolicyCheck = "PolicyCheck"
+	ResourcePolicyCheck     = "policycheck"
+	ResourcePolicyChecks    = "policychecks"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:onlyVerbs=create
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PolicyCheck finds the Gatekeeper constraints that would apply to a
+// manifest before it is submitted to the cluster.
+type PolicyCheck struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	Request *PolicyCheckRequest `json:"request,omitempty"`
+	// +optional
+	Response *PolicyCheckResponse `json:"response,omitempty"`
+}
+
+type PolicyCheckRequest struct {
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Resource *runtime.RawExtension `json:"resource,omitempty"`
+}
+
+type PolicyCheckResponse struct {
+	Constraints []ApplicableConstraint `json:"constraints,omitempty"`
+}
+
+type ApplicableConstraint struct {
+	Name string                      `json:"name"`
+	GVR  schema.GroupVersionResource `json:"gvr"`
+	// EnforcementAction is the spec.enforcementAction of the constraint.
+	EnforcementAction string `json:"enforcementAction"`
+	// EnforcementActions lists the actions taken by the admission webhook
+	// when EnforcementAction is scoped.
+	// +optional
+	EnforcementActions []string `json:"enforcementActions,omitempty"`
+	// Violations reported by the last audit run for an object with the same name.
+	// +optional
+	Violations []StatusViolation `json:"violations,omitempty"`
+}
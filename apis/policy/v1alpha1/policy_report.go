@@ -43,10 +43,30 @@ type PolicyReport struct {
 
 type PolicyReportRequest struct {
 	kmapi.ObjectInfo `json:",inline"`
+	// ExportFormat renders the report as a file in the given format in response.export
+	// +optional
+	ExportFormat ExportFormat `json:"exportFormat,omitempty"`
 }
 
+// +kubebuilder:validation:Enum=sarif;csv;html
+type ExportFormat string
+
+const (
+	ExportFormatSARIF ExportFormat = "sarif"
+	ExportFormatCSV   ExportFormat = "csv"
+	ExportFormatHTML  ExportFormat = "html"
+)
+
 type PolicyReportResponse struct {
 	Constraints []Constraint `json:"constraints,omitempty"`
+	// +optional
+	Export *ExportedReport `json:"export,omitempty"`
+}
+
+type ExportedReport struct {
+	Format      ExportFormat `json:"format"`
+	ContentType string       `json:"contentType"`
+	Data        string       `json:"data"`
 }
 
 type Constraint struct {
@@ -0,0 +1,73 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kmapi "kmodules.xyz/client-go/api/v1"
+)
+
+const (
+	ResourceKindPolicyTrend = "PolicyTrend"
+	ResourcePolicyTrend     = "policytrend"
+	ResourcePolicyTrends    = "policytrends"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:onlyVerbs=create
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type PolicyTrend struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	Request *PolicyTrendRequest `json:"request,omitempty"`
+	// +optional
+	Response *PolicyTrendResponse `json:"response,omitempty"`
+}
+
+type PolicyTrendRequest struct {
+	kmapi.ObjectInfo `json:",inline"`
+	// Since drops audit runs older than the given time from the returned series.
+	// +optional
+	Since *metav1.Time `json:"since,omitempty"`
+}
+
+type PolicyTrendResponse struct {
+	// Series breaks the violations down by namespace. Gatekeeper lists a limited number
+	// of violations per constraint (20 by default), so these counts are capped.
+	Series []ViolationSeries `json:"series,omitempty"`
+	// Totals is the total number of violations of each constraint reported by Gatekeeper,
+	// with an empty namespace. It is only returned for cluster wide requests.
+	// +optional
+	Totals []ViolationSeries `json:"totals,omitempty"`
+}
+
+// ViolationSeries is the number of violations of a constraint, in a single namespace
+// or cluster-scoped objects (empty namespace), over the recorded audit runs.
+type ViolationSeries struct {
+	Constraint string                      `json:"constraint"`
+	GVR        schema.GroupVersionResource `json:"gvr"`
+	Namespace  string                      `json:"namespace,omitempty"`
+	Points     []ViolationPoint            `json:"points"`
+}
+
+type ViolationPoint struct {
+	AuditTimestamp metav1.Time `json:"auditTimestamp"`
+	Violations     int         `json:"violations"`
+}
@@ -47,7 +47,9 @@ func Resource(resource string) schema.GroupResource {
 // Adds the list of known types to api.Scheme.
 func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
+		&PolicyCheck{},
 		&PolicyReport{},
+		&PolicyTrend{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -25,6 +25,35 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicableConstraint) DeepCopyInto(out *ApplicableConstraint) {
+	*out = *in
+	out.GVR = in.GVR
+	if in.EnforcementActions != nil {
+		in, out := &in.EnforcementActions, &out.EnforcementActions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Violations != nil {
+		in, out := &in.Violations, &out.Violations
+		*out = make([]StatusViolation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicableConstraint.
+func (in *ApplicableConstraint) DeepCopy() *ApplicableConstraint {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicableConstraint)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Constraint) DeepCopyInto(out *Constraint) {
 	*out = *in
@@ -50,6 +79,101 @@ func (in *Constraint) DeepCopy() *Constraint {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportedReport) DeepCopyInto(out *ExportedReport) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportedReport.
+func (in *ExportedReport) DeepCopy() *ExportedReport {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportedReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyCheck) DeepCopyInto(out *PolicyCheck) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.Request != nil {
+		in, out := &in.Request, &out.Request
+		*out = new(PolicyCheckRequest)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Response != nil {
+		in, out := &in.Response, &out.Response
+		*out = new(PolicyCheckResponse)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyCheck.
+func (in *PolicyCheck) DeepCopy() *PolicyCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PolicyCheck) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyCheckRequest) DeepCopyInto(out *PolicyCheckRequest) {
+	*out = *in
+	if in.Resource != nil {
+		in, out := &in.Resource, &out.Resource
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyCheckRequest.
+func (in *PolicyCheckRequest) DeepCopy() *PolicyCheckRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyCheckRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyCheckResponse) DeepCopyInto(out *PolicyCheckResponse) {
+	*out = *in
+	if in.Constraints != nil {
+		in, out := &in.Constraints, &out.Constraints
+		*out = make([]ApplicableConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyCheckResponse.
+func (in *PolicyCheckResponse) DeepCopy() *PolicyCheckResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyCheckResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PolicyReport) DeepCopyInto(out *PolicyReport) {
 	*out = *in
@@ -112,6 +236,11 @@ func (in *PolicyReportResponse) DeepCopyInto(out *PolicyReportResponse) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(ExportedReport)
+		**out = **in
+	}
 	return
 }
 
@@ -125,6 +254,92 @@ func (in *PolicyReportResponse) DeepCopy() *PolicyReportResponse {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyTrend) DeepCopyInto(out *PolicyTrend) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.Request != nil {
+		in, out := &in.Request, &out.Request
+		*out = new(PolicyTrendRequest)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Response != nil {
+		in, out := &in.Response, &out.Response
+		*out = new(PolicyTrendResponse)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyTrend.
+func (in *PolicyTrend) DeepCopy() *PolicyTrend {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyTrend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PolicyTrend) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyTrendRequest) DeepCopyInto(out *PolicyTrendRequest) {
+	*out = *in
+	out.ObjectInfo = in.ObjectInfo
+	if in.Since != nil {
+		in, out := &in.Since, &out.Since
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyTrendRequest.
+func (in *PolicyTrendRequest) DeepCopy() *PolicyTrendRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyTrendRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyTrendResponse) DeepCopyInto(out *PolicyTrendResponse) {
+	*out = *in
+	if in.Series != nil {
+		in, out := &in.Series, &out.Series
+		*out = make([]ViolationSeries, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Totals != nil {
+		in, out := &in.Totals, &out.Totals
+		*out = make([]ViolationSeries, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyTrendResponse.
+func (in *PolicyTrendResponse) DeepCopy() *PolicyTrendResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyTrendResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StatusViolation) DeepCopyInto(out *StatusViolation) {
 	*out = *in
@@ -145,3 +360,44 @@ func (in *StatusViolation) DeepCopy() *StatusViolation {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ViolationPoint) DeepCopyInto(out *ViolationPoint) {
+	*out = *in
+	in.AuditTimestamp.DeepCopyInto(&out.AuditTimestamp)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ViolationPoint.
+func (in *ViolationPoint) DeepCopy() *ViolationPoint {
+	if in == nil {
+		return nil
+	}
+	out := new(ViolationPoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ViolationSeries) DeepCopyInto(out *ViolationSeries) {
+	*out = *in
+	out.GVR = in.GVR
+	if in.Points != nil {
+		in, out := &in.Points, &out.Points
+		*out = make([]ViolationPoint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ViolationSeries.
+func (in *ViolationSeries) DeepCopy() *ViolationSeries {
+	if in == nil {
+		return nil
+	}
+	out := new(ViolationSeries)
+	in.DeepCopyInto(out)
+	return out
+}
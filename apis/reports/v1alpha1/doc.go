@@ -0,0 +1,22 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1alpha1 contains the types that the ui-server adds to the
+// reports.scanner.appscode.com v1alpha1 API group of the scanner project.
+
+// +k8s:deepcopy-gen=package
+// +groupName=reports.scanner.appscode.com
+package v1alpha1 // import "kubeops.dev/ui-server/apis/reports/v1alpha1"
@@ -0,0 +1,51 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	policyapi "kubeops.dev/ui-server/apis/policy/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kmapi "kmodules.xyz/client-go/api/v1"
+)
+
+const (
+	ResourceKindCVEReportExport = "CVEReportExport"
+	ResourceCVEReportExport     = "cvereportexport"
+	ResourceCVEReportExports    = "cvereportexports"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:onlyVerbs=create
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CVEReportExport renders a CVEReport as a file. The CVEReport type is owned by the
+// scanner project, so the export is a separate kind of the same group.
+type CVEReportExport struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	Request *CVEReportExportRequest `json:"request,omitempty"`
+	// +optional
+	Response *policyapi.ExportedReport `json:"response,omitempty"`
+}
+
+// CVEReportExportRequest selects the objects of the report like a CVEReportRequest.
+type CVEReportExportRequest struct {
+	kmapi.ObjectInfo `json:",inline"`
+	ExportFormat     policyapi.ExportFormat `json:"exportFormat"`
+}
@@ -25,8 +25,10 @@ import (
 
 	"kubeops.dev/ui-server/pkg/apiserver"
 	"kubeops.dev/ui-server/pkg/graph"
+	"kubeops.dev/ui-server/pkg/registry/core/project"
 	"kubeops.dev/ui-server/pkg/registry/core/resourceservice"
 	"kubeops.dev/ui-server/pkg/registry/identity/selfsubjectnamespaceaccessreview"
+	"kubeops.dev/ui-server/pkg/shared"
 
 	authorization "k8s.io/api/authorization/v1"
 	kerr "k8s.io/apimachinery/pkg/api/errors"
@@ -122,7 +124,7 @@ func main_2() {
 	if err != nil {
 		panic(err)
 	}
-	s := selfsubjectnamespaceaccessreview.NewStorage(kc, rtc)
+	s := selfsubjectnamespaceaccessreview.NewStorage(kc, rtc, nil, project.NewSource(rtc, nil, project.Options{}), selfsubjectnamespaceaccessreview.Options{})
 
 	ctx := context.Background()
 	ctx = request.WithUser(ctx, &user.DefaultInfo{
@@ -413,7 +415,7 @@ func main_5() {
 
 	rbacAuthorizer := rbacauthz.New(rtc)
 
-	s := resourceservice.NewStorage(rtc, kc.Discovery(), cid, rbacAuthorizer)
+	s := resourceservice.NewStorage(rtc, shared.NewRESTMapper(kc.Discovery()), cid, rbacAuthorizer, nil, false, "", nil)
 
 	ctx := context.TODO()
 	ctx = request.WithNamespace(ctx, "default")
@@ -442,7 +444,7 @@ func main_6() {
 
 	rbacAuthorizer := rbacauthz.New(rtc)
 
-	s := resourceservice.NewStorage(rtc, kc.Discovery(), cid, rbacAuthorizer)
+	s := resourceservice.NewStorage(rtc, shared.NewRESTMapper(kc.Discovery()), cid, rbacAuthorizer, nil, false, "", nil)
 
 	ctx := context.TODO()
 	ctx = request.WithNamespace(ctx, "ace")
@@ -466,7 +468,7 @@ func main() {
 		panic(err)
 	}
 
-	s := selfsubjectnamespaceaccessreview.NewStorage(kc, rtc)
+	s := selfsubjectnamespaceaccessreview.NewStorage(kc, rtc, nil, project.NewSource(rtc, nil, project.Options{}), selfsubjectnamespaceaccessreview.Options{})
 
 	ctx := context.TODO()
 	ctx = request.WithNamespace(ctx, "ace")
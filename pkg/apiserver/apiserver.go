@@ -26,27 +26,39 @@ import (
 	scannerreports "kubeops.dev/scanner/apis/reports"
 	scannerreportsapi "kubeops.dev/scanner/apis/reports/v1alpha1"
 	scannerscheme "kubeops.dev/scanner/client/clientset/versioned/scheme"
+	uicoreapi "kubeops.dev/ui-server/apis/core/v1alpha1"
 	costinstall "kubeops.dev/ui-server/apis/cost/install"
 	costapi "kubeops.dev/ui-server/apis/cost/v1alpha1"
+	identitylocalinstall "kubeops.dev/ui-server/apis/identity/install"
+	identitylocalapi "kubeops.dev/ui-server/apis/identity/v1alpha1"
+	projectapi "kubeops.dev/ui-server/apis/management/v1alpha1"
 	licenseinstall "kubeops.dev/ui-server/apis/offline/install"
 	licenseapi "kubeops.dev/ui-server/apis/offline/v1alpha1"
 	policyinstall "kubeops.dev/ui-server/apis/policy/install"
 	policyapi "kubeops.dev/ui-server/apis/policy/v1alpha1"
+	uireportsapi "kubeops.dev/ui-server/apis/reports/v1alpha1"
 	clustermetacontroller "kubeops.dev/ui-server/pkg/controllers/clustermetadata"
+	costbudgetcontroller "kubeops.dev/ui-server/pkg/controllers/costbudget"
 	clusterclaimcontroller "kubeops.dev/ui-server/pkg/controllers/feature"
 	projectquotacontroller "kubeops.dev/ui-server/pkg/controllers/projectquota"
 	"kubeops.dev/ui-server/pkg/graph"
+	"kubeops.dev/ui-server/pkg/health"
 	"kubeops.dev/ui-server/pkg/metricshandler"
+	"kubeops.dev/ui-server/pkg/opencost"
 	genericresourcestorage "kubeops.dev/ui-server/pkg/registry/core/genericresource"
 	podviewstorage "kubeops.dev/ui-server/pkg/registry/core/podview"
 	projecttorage "kubeops.dev/ui-server/pkg/registry/core/project"
+	projectsummarystorage "kubeops.dev/ui-server/pkg/registry/core/projectsummary"
 	resourcesservicestorage "kubeops.dev/ui-server/pkg/registry/core/resourceservice"
 	resourcesummarystorage "kubeops.dev/ui-server/pkg/registry/core/resourcesummary"
+	costestimatestorage "kubeops.dev/ui-server/pkg/registry/cost/estimate"
 	coststorage "kubeops.dev/ui-server/pkg/registry/cost/reports"
 	editormodelstorage "kubeops.dev/ui-server/pkg/registry/editor/editormodel"
 	audittokenreqstorage "kubeops.dev/ui-server/pkg/registry/identity/audittokenrequest"
 	clusteridstorage "kubeops.dev/ui-server/pkg/registry/identity/clusteridentity"
 	inboxtokenreqstorage "kubeops.dev/ui-server/pkg/registry/identity/inboxtokenrequest"
+	"kubeops.dev/ui-server/pkg/registry/identity/resourceaccessreview"
+	"kubeops.dev/ui-server/pkg/registry/identity/selfsubjectaccessmatrix"
 	"kubeops.dev/ui-server/pkg/registry/identity/selfsubjectnamespaceaccessreview"
 	siteinfostorage "kubeops.dev/ui-server/pkg/registry/identity/siteinfo"
 	"kubeops.dev/ui-server/pkg/registry/meta/chartpresetquery"
@@ -72,9 +84,13 @@ import (
 	"kubeops.dev/ui-server/pkg/registry/meta/vendormenu"
 	"kubeops.dev/ui-server/pkg/registry/offline/addofflinelicense"
 	"kubeops.dev/ui-server/pkg/registry/offline/offlinelicense"
+	"kubeops.dev/ui-server/pkg/registry/offline/offlinelicensereview"
+	policycheckstorage "kubeops.dev/ui-server/pkg/registry/policy/check"
 	policystorage "kubeops.dev/ui-server/pkg/registry/policy/reports"
+	policytrendstorage "kubeops.dev/ui-server/pkg/registry/policy/trends"
 	imagestorage "kubeops.dev/ui-server/pkg/registry/scanner/image"
 	reportstorage "kubeops.dev/ui-server/pkg/registry/scanner/reports"
+	"kubeops.dev/ui-server/pkg/shared"
 
 	fluxsrc "github.com/fluxcd/source-controller/api/v1"
 	"github.com/graphql-go/handler"
@@ -114,6 +130,7 @@ import (
 	identitylib "kmodules.xyz/resource-metadata/pkg/identity"
 	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
@@ -132,6 +149,7 @@ var (
 
 func init() {
 	identityinstall.Install(Scheme)
+	identitylocalinstall.Install(Scheme)
 	policyinstall.Install(Scheme)
 	costinstall.Install(Scheme)
 	rsinstall.Install(Scheme)
@@ -139,9 +157,12 @@ func init() {
 	editorinstall.Install(Scheme)
 	rscoreinstall.Install(Scheme)
 	mgmtinstall.Install(Scheme)
+	utilruntime.Must(uicoreapi.AddToScheme(Scheme))
+	utilruntime.Must(projectapi.AddToScheme(Scheme))
 	crdinstall.Install(Scheme)
 	licenseinstall.Install(Scheme)
 	utilruntime.Must(scannerscheme.AddToScheme(Scheme))
+	utilruntime.Must(uireportsapi.AddToScheme(Scheme))
 	utilruntime.Must(chartsapi.AddToScheme(Scheme))
 	utilruntime.Must(clientgoscheme.AddToScheme(Scheme))
 	utilruntime.Must(appcatalogapi.AddToScheme(Scheme))
@@ -173,6 +194,14 @@ type ExtraConfig struct {
 	ClientConfig *restclient.Config
 	PromConfig   promclient.Config
 
+	OpenCostConfig   opencost.Config
+	CostBackend      coststorage.Backend
+	PricingConfigMap string
+
+	AccessReviewConfig selfsubjectnamespaceaccessreview.Options
+	ProjectConfig      projecttorage.Options
+	WatchAllKinds      bool
+
 	BaseURL string
 	Token   string
 	CACert  []byte
@@ -251,11 +280,29 @@ func (c completedConfig) New(ctx context.Context) (*UIServer, error) {
 	}
 
 	rbacAuthorizer := authorizer.NewForManagerOrDie(ctx, mgr)
+	projects := projecttorage.NewSource(ctrlClient, rbacAuthorizer, c.ExtraConfig.ProjectConfig)
 
 	builder, err := promclient.NewBuilder(mgr, &c.ExtraConfig.PromConfig)
 	if err != nil {
 		return nil, err
 	}
+
+	oc, err := opencost.NewClient(ctrlClient, c.ExtraConfig.OpenCostConfig)
+	if err != nil {
+		return nil, err
+	}
+	costHealth := opencost.NewHealthChecker(oc)
+	if err := mgr.Add(costHealth); err != nil {
+		return nil, err
+	}
+	costStorage := coststorage.NewStorage(ctrlClient, oc, costHealth, projects, c.ExtraConfig.CostBackend, client.ObjectKey{
+		Namespace: meta.PodNamespace(),
+		Name:      c.ExtraConfig.PricingConfigMap,
+	})
+	healthScores := health.NewCollector(ctrlClient, meta.PodNamespace())
+	if err := mgr.Add(healthScores); err != nil {
+		return nil, err
+	}
 	if err := builder.Setup(); err != nil {
 		return nil, err
 	}
@@ -271,6 +318,18 @@ func (c completedConfig) New(ctx context.Context) (*UIServer, error) {
 		os.Exit(1)
 	}
 
+	// CostBudgets are optional, the controller runs only if the CRD is installed.
+	if _, err := mgr.GetRESTMapper().RESTMapping(schema.GroupKind{
+		Group: costapi.GroupName,
+		Kind:  costapi.ResourceKindCostBudget,
+	}); err == nil {
+		err = costbudgetcontroller.NewReconciler(mgr.GetClient(), costStorage, mgr.GetEventRecorderFor("costbudget-controller")).SetupWithManager(mgr)
+		if err != nil {
+			klog.Error(err, "unable to create controller", "controller", "CostBudget")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.Add(manager.RunnableFunc(graph.PollNewResourceTypes(cfg, pqr))); err != nil {
 		setupLog.Error(err, "unable to set up resource poller")
 		os.Exit(1)
@@ -281,6 +340,11 @@ func (c completedConfig) New(ctx context.Context) (*UIServer, error) {
 		os.Exit(1)
 	}
 
+	if err := mgr.Add(manager.RunnableFunc(policytrendstorage.StartRecorder(mgr.GetClient(), meta.PodNamespace()))); err != nil {
+		setupLog.Error(err, "unable to set up policy trend recorder")
+		os.Exit(1)
+	}
+
 	if c.ExtraConfig.Token != "" {
 		if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
 			md, err := bc.Identify(cid)
@@ -363,8 +427,9 @@ func (c completedConfig) New(ctx context.Context) (*UIServer, error) {
 		apiGroupInfo := genericapiserver.NewDefaultAPIGroupInfo(licenseapi.GroupName, Scheme, metav1.ParameterCodec, Codecs)
 
 		v1alpha1storage := map[string]rest.Storage{}
-		v1alpha1storage[licenseapi.ResourceOfflineLicenses] = offlinelicense.NewStorage(ctrlClient)
-		v1alpha1storage[licenseapi.ResourceAddOfflineLicenses] = addofflinelicense.NewStorage(ctrlClient, rbacAuthorizer)
+		v1alpha1storage[licenseapi.ResourceOfflineLicenses] = offlinelicense.NewStorage(ctrlClient, rbacAuthorizer, mgr.GetCache())
+		v1alpha1storage[licenseapi.ResourceAddOfflineLicenses] = addofflinelicense.NewStorage(ctrlClient, rbacAuthorizer, cid)
+		v1alpha1storage[licenseapi.ResourceOfflineLicenseReviews] = offlinelicensereview.NewStorage(cid)
 		apiGroupInfo.VersionedResourcesStorageMap["v1alpha1"] = v1alpha1storage
 
 		if err := s.GenericAPIServer.InstallAPIGroup(&apiGroupInfo); err != nil {
@@ -378,8 +443,10 @@ func (c completedConfig) New(ctx context.Context) (*UIServer, error) {
 		v1alpha1storage[identityapi.ResourceClusterIdentities] = clusteridstorage.NewStorage(ctrlClient, bc)
 		v1alpha1storage[identityapi.ResourceInboxTokenRequests] = inboxtokenreqstorage.NewStorage(ctrlClient, bc)
 		v1alpha1storage[identityapi.ResourceAuditTokenRequests] = audittokenreqstorage.NewStorage(ctrlClient, bc)
-		v1alpha1storage[identityapi.ResourceSelfSubjectNamespaceAccessReviews] = selfsubjectnamespaceaccessreview.NewStorage(kc, ctrlClient)
+		v1alpha1storage[identityapi.ResourceSelfSubjectNamespaceAccessReviews] = selfsubjectnamespaceaccessreview.NewStorage(kc, ctrlClient, rbacAuthorizer, projects, c.ExtraConfig.AccessReviewConfig)
 		v1alpha1storage[identityapi.ResourceSiteInfos] = siteinfostorage.NewStorage(mgr.GetConfig(), kc, ctrlClient)
+		v1alpha1storage[identitylocalapi.ResourceSelfSubjectAccessMatrices] = selfsubjectaccessmatrix.NewStorage(ctrlClient, rbacAuthorizer)
+		v1alpha1storage[identitylocalapi.ResourceResourceAccessReviews] = resourceaccessreview.NewStorage(ctrlClient, rbacAuthorizer)
 		apiGroupInfo.VersionedResourcesStorageMap["v1alpha1"] = v1alpha1storage
 
 		if err := s.GenericAPIServer.InstallAPIGroup(&apiGroupInfo); err != nil {
@@ -389,12 +456,18 @@ func (c completedConfig) New(ctx context.Context) (*UIServer, error) {
 	{
 		apiGroupInfo := genericapiserver.NewDefaultAPIGroupInfo(rscoreapi.GroupName, Scheme, metav1.ParameterCodec, Codecs)
 
+		mapper := shared.NewRESTMapper(kc)
 		v1alpha1storage := map[string]rest.Storage{}
-		v1alpha1storage[rscoreapi.ResourceGenericResourceServices] = resourcesservicestorage.NewStorage(ctrlClient, kc, cid, rbacAuthorizer)
-		v1alpha1storage[rscoreapi.ResourceGenericResources] = genericresourcestorage.NewStorage(ctrlClient, kc, cid, rbacAuthorizer)
-		v1alpha1storage[rscoreapi.ResourcePodViews] = podviewstorage.NewStorage(ctrlClient, rbacAuthorizer, builder)
-		v1alpha1storage[rscoreapi.ResourceProjects] = projecttorage.NewStorage(ctrlClient)
-		v1alpha1storage[rscoreapi.ResourceResourceSummaries] = resourcesummarystorage.NewStorage(ctrlClient, kc, cid, rbacAuthorizer)
+		services := resourcesservicestorage.NewStorage(ctrlClient, mapper, cid, rbacAuthorizer, mgr.GetCache(), c.ExtraConfig.WatchAllKinds, meta.PodNamespace(), healthScores)
+		healthScores.SetTargets(services.HealthTargets)
+		v1alpha1storage[rscoreapi.ResourceGenericResourceServices] = services
+		v1alpha1storage[rscoreapi.ResourceGenericResources] = genericresourcestorage.NewStorage(ctrlClient, mapper, cid, rbacAuthorizer, mgr.GetCache(), c.ExtraConfig.WatchAllKinds)
+		podViews := podviewstorage.NewStorage(ctrlClient, kc, rbacAuthorizer, builder, mgr.GetCache())
+		v1alpha1storage[rscoreapi.ResourcePodViews] = podViews
+		v1alpha1storage[uicoreapi.ResourcePodDetails] = podviewstorage.NewDetailStorage(podViews)
+		v1alpha1storage[rscoreapi.ResourceProjects] = projecttorage.NewStorage(ctrlClient, projects)
+		v1alpha1storage[uicoreapi.ResourceProjectSummaries] = projectsummarystorage.NewStorage(ctrlClient, mapper, cid, rbacAuthorizer, mgr.GetCache(), projects, builder)
+		v1alpha1storage[rscoreapi.ResourceResourceSummaries] = resourcesummarystorage.NewStorage(ctrlClient, mapper, cid, rbacAuthorizer, mgr.GetCache(), c.ExtraConfig.WatchAllKinds)
 		apiGroupInfo.VersionedResourcesStorageMap["v1alpha1"] = v1alpha1storage
 
 		if err := s.GenericAPIServer.InstallAPIGroup(&apiGroupInfo); err != nil {
@@ -407,6 +480,7 @@ func (c completedConfig) New(ctx context.Context) (*UIServer, error) {
 		v1alpha1storage := map[string]rest.Storage{}
 		v1alpha1storage[scannerreportsapi.ResourceImages] = imagestorage.NewStorage(ctrlClient)
 		v1alpha1storage[scannerreportsapi.ResourceCVEReports] = reportstorage.NewStorage(ctrlClient)
+		v1alpha1storage[uireportsapi.ResourceCVEReportExports] = reportstorage.NewExportStorage(ctrlClient)
 		apiGroupInfo.VersionedResourcesStorageMap["v1alpha1"] = v1alpha1storage
 
 		if err := s.GenericAPIServer.InstallAPIGroup(&apiGroupInfo); err != nil {
@@ -417,7 +491,9 @@ func (c completedConfig) New(ctx context.Context) (*UIServer, error) {
 		apiGroupInfo := genericapiserver.NewDefaultAPIGroupInfo(policyapi.GroupName, Scheme, metav1.ParameterCodec, Codecs)
 
 		v1alpha1storage := map[string]rest.Storage{}
+		v1alpha1storage[policyapi.ResourcePolicyChecks] = policycheckstorage.NewStorage(ctrlClient)
 		v1alpha1storage[policyapi.ResourcePolicyReports] = policystorage.NewStorage(ctrlClient)
+		v1alpha1storage[policyapi.ResourcePolicyTrends] = policytrendstorage.NewStorage(ctrlClient, meta.PodNamespace())
 		apiGroupInfo.VersionedResourcesStorageMap["v1alpha1"] = v1alpha1storage
 
 		if err := s.GenericAPIServer.InstallAPIGroup(&apiGroupInfo); err != nil {
@@ -428,7 +504,8 @@ func (c completedConfig) New(ctx context.Context) (*UIServer, error) {
 		apiGroupInfo := genericapiserver.NewDefaultAPIGroupInfo(costapi.GroupName, Scheme, metav1.ParameterCodec, Codecs)
 
 		v1alpha1storage := map[string]rest.Storage{}
-		v1alpha1storage[costapi.ResourceCostReports] = coststorage.NewStorage(ctrlClient)
+		v1alpha1storage[costapi.ResourceCostReports] = costStorage
+		v1alpha1storage[costapi.ResourceCostEstimates] = costestimatestorage.NewStorage(ctrlClient, costStorage)
 		apiGroupInfo.VersionedResourcesStorageMap["v1alpha1"] = v1alpha1storage
 
 		if err := s.GenericAPIServer.InstallAPIGroup(&apiGroupInfo); err != nil {
@@ -454,7 +531,7 @@ func (c completedConfig) New(ctx context.Context) (*UIServer, error) {
 		}
 		m.Install(genericServer.Handler.NonGoRestfulMux)
 	}
-	if err := mgr.Add(manager.RunnableFunc(metricshandler.StartMetricsCollector(mgr))); err != nil {
+	if err := mgr.Add(manager.RunnableFunc(metricshandler.StartMetricsCollector(mgr, healthScores))); err != nil {
 		setupLog.Error(err, "unable to start metrics collector")
 		os.Exit(1)
 	}
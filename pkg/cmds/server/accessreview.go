@@ -0,0 +1,63 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"time"
+
+	"kubeops.dev/ui-server/pkg/apiserver"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+type AccessReviewOptions struct {
+	Workers   int
+	CacheTTL  time.Duration
+	InProcess bool
+}
+
+func NewAccessReviewOptions() *AccessReviewOptions {
+	return &AccessReviewOptions{
+		Workers:  16,
+		CacheTTL: 10 * time.Second,
+	}
+}
+
+func (s *AccessReviewOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&s.Workers, "access-review.workers", s.Workers, "Number of namespaces checked in parallel by a SelfSubjectNamespaceAccessReview.")
+	fs.DurationVar(&s.CacheTTL, "access-review.cache-ttl", s.CacheTTL, "How long access decisions are cached per user. Set to 0 to disable caching.")
+	fs.BoolVar(&s.InProcess, "access-review.in-process", s.InProcess, "Evaluate access checks with the in-process RBAC authorizer instead of creating SubjectAccessReviews.")
+}
+
+func (s *AccessReviewOptions) Validate() []error {
+	var errs []error
+	if s.Workers <= 0 {
+		errs = append(errs, errors.Errorf("--access-review.workers must be positive, found %d", s.Workers))
+	}
+	if s.CacheTTL < 0 {
+		errs = append(errs, errors.Errorf("--access-review.cache-ttl must not be negative, found %s", s.CacheTTL))
+	}
+	return errs
+}
+
+func (s *AccessReviewOptions) ApplyTo(cfg *apiserver.ExtraConfig) error {
+	cfg.AccessReviewConfig.Workers = s.Workers
+	cfg.AccessReviewConfig.CacheTTL = s.CacheTTL
+	cfg.AccessReviewConfig.InProcess = s.InProcess
+	return nil
+}
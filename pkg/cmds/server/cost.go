@@ -0,0 +1,56 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"kubeops.dev/ui-server/pkg/apiserver"
+	costreports "kubeops.dev/ui-server/pkg/registry/cost/reports"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+type CostOptions struct {
+	Backend          string
+	PricingConfigMap string
+}
+
+func NewCostOptions() *CostOptions {
+	return &CostOptions{
+		Backend:          string(costreports.BackendAuto),
+		PricingConfigMap: "cost-pricing",
+	}
+}
+
+func (s *CostOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.Backend, "cost.backend", s.Backend, "Cost backend, one of auto, opencost or local. auto uses OpenCost if found, otherwise the local estimator.")
+	fs.StringVar(&s.PricingConfigMap, "cost.pricing-configmap", s.PricingConfigMap, "Name of the ConfigMap in the pod namespace with the pricing used by the local cost estimator.")
+}
+
+func (s *CostOptions) Validate() []error {
+	switch costreports.Backend(s.Backend) {
+	case costreports.BackendAuto, costreports.BackendOpenCost, costreports.BackendLocal:
+		return nil
+	}
+	return []error{errors.Errorf("unknown --cost.backend %s", s.Backend)}
+}
+
+func (s *CostOptions) ApplyTo(cfg *apiserver.ExtraConfig) error {
+	cfg.CostBackend = costreports.Backend(s.Backend)
+	cfg.PricingConfigMap = s.PricingConfigMap
+	return nil
+}
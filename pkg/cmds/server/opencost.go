@@ -0,0 +1,83 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"net/url"
+	"os"
+	"time"
+
+	"kubeops.dev/ui-server/pkg/apiserver"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+type OpenCostOptions struct {
+	URL             string
+	CAFile          string
+	BearerToken     string
+	BearerTokenFile string
+	Timeout         time.Duration
+}
+
+func NewOpenCostOptions() *OpenCostOptions {
+	return &OpenCostOptions{
+		Timeout: 30 * time.Second,
+	}
+}
+
+func (s *OpenCostOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.URL, "opencost.address", s.URL, "The address of the OpenCost api. If not set, the OpenCost service is discovered in the cluster.")
+	fs.StringVar(&s.CAFile, "opencost.ca-cert-file", s.CAFile, "The path of the CA cert to use for the OpenCost api.")
+	fs.StringVar(&s.BearerToken, "opencost.bearer-token", s.BearerToken, "The bearer token for the OpenCost api.")
+	fs.StringVar(&s.BearerTokenFile, "opencost.bearer-token-file", s.BearerTokenFile, "The bearer token file for the OpenCost api.")
+	fs.DurationVar(&s.Timeout, "opencost.timeout", s.Timeout, "Timeout for calls to the OpenCost api.")
+}
+
+func (s *OpenCostOptions) Validate() []error {
+	var errs []error
+	if s.URL != "" {
+		if u, err := url.Parse(s.URL); err != nil {
+			errs = append(errs, errors.Wrap(err, "invalid --opencost.address"))
+		} else if u.Scheme != "http" && u.Scheme != "https" {
+			errs = append(errs, errors.Errorf("--opencost.address must be a http or https url, found %s", s.URL))
+		}
+	}
+	if s.BearerToken != "" && s.BearerTokenFile != "" {
+		errs = append(errs, errors.New("--opencost.bearer-token and --opencost.bearer-token-file are mutually exclusive"))
+	}
+	if s.Timeout <= 0 {
+		errs = append(errs, errors.New("--opencost.timeout must be positive"))
+	}
+	return errs
+}
+
+func (s *OpenCostOptions) ApplyTo(cfg *apiserver.ExtraConfig) error {
+	cfg.OpenCostConfig.URL = s.URL
+	cfg.OpenCostConfig.BearerToken = s.BearerToken
+	cfg.OpenCostConfig.BearerTokenFile = s.BearerTokenFile
+	cfg.OpenCostConfig.Timeout = s.Timeout
+	if s.CAFile != "" {
+		caCert, err := os.ReadFile(s.CAFile)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read CA file %s", s.CAFile)
+		}
+		cfg.OpenCostConfig.CACert = caCert
+	}
+	return nil
+}
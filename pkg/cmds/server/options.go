@@ -32,6 +32,8 @@ type ExtraOptions struct {
 	TelemetryHost string
 	TelemetryPort int
 
+	WatchAllKinds bool
+
 	BaseURL string
 	Token   string
 	CAFile  string
@@ -53,6 +55,8 @@ func (s *ExtraOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&s.TelemetryHost, "telemetry-host", s.TelemetryHost, `Host to expose self metrics on.`)
 	fs.IntVar(&s.TelemetryPort, "telemetry-port", s.TelemetryPort, `Port to expose self metrics on.`)
 
+	fs.BoolVar(&s.WatchAllKinds, "watch-all-kinds", s.WatchAllKinds, "Serve watches of GenericResources, GenericResourceServices and ResourceSummaries without a k8s.io/group or k8s.io/group-kind label selector. Such a watch starts a cluster wide informer of every registered kind, which is kept in memory until the server stops.")
+
 	fs.StringVar(&s.BaseURL, "baseURL", s.BaseURL, "License server base url")
 	fs.StringVar(&s.Token, "token", s.Token, "License server token")
 	fs.StringVar(&s.CAFile, "platform-ca-file", s.CAFile, "Path to platform CA cert file")
@@ -62,6 +66,8 @@ func (s *ExtraOptions) ApplyTo(cfg *apiserver.ExtraConfig) error {
 	cfg.ClientConfig.QPS = float32(s.QPS)
 	cfg.ClientConfig.Burst = s.Burst
 
+	cfg.WatchAllKinds = s.WatchAllKinds
+
 	cfg.BaseURL = s.BaseURL
 	cfg.Token = s.Token
 	if s.CAFile != "" {
@@ -0,0 +1,61 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"slices"
+
+	"kubeops.dev/ui-server/pkg/apiserver"
+	"kubeops.dev/ui-server/pkg/registry/core/project"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+type ProjectOptions struct {
+	Source   string
+	LabelKey string
+}
+
+func NewProjectOptions() *ProjectOptions {
+	return &ProjectOptions{
+		Source:   string(project.SourceAuto),
+		LabelKey: project.DefaultLabelKey,
+	}
+}
+
+func (s *ProjectOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.Source, "project.source", s.Source, "Source of the projects of the cluster. One of Auto, Rancher, Label, ProjectQuota or CRD. Auto uses Rancher projects on Rancher managed clusters and namespace labels otherwise.")
+	fs.StringVar(&s.LabelKey, "project.label-key", s.LabelKey, "Namespace label whose value names the project of the namespace, used by the Label and ProjectQuota sources.")
+}
+
+func (s *ProjectOptions) Validate() []error {
+	var errs []error
+	if !slices.Contains(project.SourceTypes, project.SourceType(s.Source)) {
+		errs = append(errs, errors.Errorf("--project.source must be one of %v, found %q", project.SourceTypes, s.Source))
+	}
+	if s.LabelKey == "" {
+		errs = append(errs, errors.New("--project.label-key must not be empty"))
+	}
+	return errs
+}
+
+func (s *ProjectOptions) ApplyTo(cfg *apiserver.ExtraConfig) error {
+	cfg.ProjectConfig.Source = project.SourceType(s.Source)
+	cfg.ProjectConfig.LabelKey = s.LabelKey
+	return nil
+}
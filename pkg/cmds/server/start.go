@@ -25,9 +25,12 @@ import (
 	"strconv"
 
 	reportsapi "kubeops.dev/scanner/apis/reports/v1alpha1"
+	uicoreapi "kubeops.dev/ui-server/apis/core/v1alpha1"
 	costapi "kubeops.dev/ui-server/apis/cost/v1alpha1"
+	identitylocalapi "kubeops.dev/ui-server/apis/identity/v1alpha1"
 	licenseapi "kubeops.dev/ui-server/apis/offline/v1alpha1"
 	policyapi "kubeops.dev/ui-server/apis/policy/v1alpha1"
+	uireportsapi "kubeops.dev/ui-server/apis/reports/v1alpha1"
 	"kubeops.dev/ui-server/pkg/apiserver"
 	featurecontroller "kubeops.dev/ui-server/pkg/controllers/feature"
 	"kubeops.dev/ui-server/pkg/metricshandler"
@@ -63,9 +66,13 @@ const defaultEtcdPathPrefix = "/registry/k8s.appscode.com"
 
 // UIServerOptions contains state for master/api server
 type UIServerOptions struct {
-	RecommendedOptions *genericoptions.RecommendedOptions
-	PrometheusOptions  *promclient.Config
-	ExtraOptions       *ExtraOptions
+	RecommendedOptions  *genericoptions.RecommendedOptions
+	PrometheusOptions   *promclient.Config
+	OpenCostOptions     *OpenCostOptions
+	CostOptions         *CostOptions
+	AccessReviewOptions *AccessReviewOptions
+	ProjectOptions      *ProjectOptions
+	ExtraOptions        *ExtraOptions
 
 	StdOut io.Writer
 	StdErr io.Writer
@@ -82,10 +89,14 @@ func NewUIServerOptions(out, errOut io.Writer) *UIServerOptions {
 				rscoreapi.SchemeGroupVersion,
 			),
 		),
-		PrometheusOptions: promclient.NewPrometheusConfig(),
-		ExtraOptions:      NewExtraOptions(),
-		StdOut:            out,
-		StdErr:            errOut,
+		PrometheusOptions:   promclient.NewPrometheusConfig(),
+		OpenCostOptions:     NewOpenCostOptions(),
+		CostOptions:         NewCostOptions(),
+		AccessReviewOptions: NewAccessReviewOptions(),
+		ProjectOptions:      NewProjectOptions(),
+		ExtraOptions:        NewExtraOptions(),
+		StdOut:              out,
+		StdErr:              errOut,
 	}
 	o.RecommendedOptions.Etcd = nil
 	o.RecommendedOptions.Admission = nil
@@ -95,6 +106,10 @@ func NewUIServerOptions(out, errOut io.Writer) *UIServerOptions {
 func (o UIServerOptions) AddFlags(fs *pflag.FlagSet) {
 	o.RecommendedOptions.AddFlags(fs)
 	o.PrometheusOptions.AddFlags(fs)
+	o.OpenCostOptions.AddFlags(fs)
+	o.CostOptions.AddFlags(fs)
+	o.AccessReviewOptions.AddFlags(fs)
+	o.ProjectOptions.AddFlags(fs)
 	o.ExtraOptions.AddFlags(fs)
 }
 
@@ -103,6 +118,10 @@ func (o UIServerOptions) Validate(args []string) error {
 	var errors []error
 	errors = append(errors, o.RecommendedOptions.Validate()...)
 	errors = append(errors, o.PrometheusOptions.Validate())
+	errors = append(errors, o.OpenCostOptions.Validate()...)
+	errors = append(errors, o.CostOptions.Validate()...)
+	errors = append(errors, o.AccessReviewOptions.Validate()...)
+	errors = append(errors, o.ProjectOptions.Validate()...)
 	return utilerrors.NewAggregate(errors)
 }
 
@@ -132,11 +151,18 @@ func (o *UIServerOptions) Config() (*apiserver.Config, error) {
 	ignorePrefixes := []string{
 		"/swaggerapi",
 
+		fmt.Sprintf("/apis/%s/%s", costapi.SchemeGroupVersion, costapi.ResourceCostEstimates),
 		fmt.Sprintf("/apis/%s/%s", costapi.SchemeGroupVersion, costapi.ResourceCostReports),
 
+		fmt.Sprintf("/apis/%s/%s", uicoreapi.SchemeGroupVersion, uicoreapi.ResourcePodDetails),
+		fmt.Sprintf("/apis/%s/%s", uicoreapi.SchemeGroupVersion, uicoreapi.ResourceProjectSummaries),
+
+		fmt.Sprintf("/apis/%s/%s", policyapi.SchemeGroupVersion, policyapi.ResourcePolicyChecks),
 		fmt.Sprintf("/apis/%s/%s", policyapi.SchemeGroupVersion, policyapi.ResourcePolicyReports),
+		fmt.Sprintf("/apis/%s/%s", policyapi.SchemeGroupVersion, policyapi.ResourcePolicyTrends),
 
 		fmt.Sprintf("/apis/%s/%s", reportsapi.SchemeGroupVersion, reportsapi.ResourceCVEReports),
+		fmt.Sprintf("/apis/%s/%s", reportsapi.SchemeGroupVersion, uireportsapi.ResourceCVEReportExports),
 		fmt.Sprintf("/apis/%s/%s", reportsapi.SchemeGroupVersion, reportsapi.ResourceImages),
 
 		fmt.Sprintf("/apis/%s/%s", rsapi.SchemeGroupVersion, "usermenus"),
@@ -173,6 +199,8 @@ func (o *UIServerOptions) Config() (*apiserver.Config, error) {
 		fmt.Sprintf("/apis/%s", identityapi.SchemeGroupVersion),
 		fmt.Sprintf("/apis/%s/%s", identityapi.SchemeGroupVersion, identityapi.ResourceClusterIdentities),
 		fmt.Sprintf("/apis/%s/%s", identityapi.SchemeGroupVersion, identityapi.ResourceInboxTokenRequests),
+		fmt.Sprintf("/apis/%s/%s", identityapi.SchemeGroupVersion, identitylocalapi.ResourceResourceAccessReviews),
+		fmt.Sprintf("/apis/%s/%s", identityapi.SchemeGroupVersion, identitylocalapi.ResourceSelfSubjectAccessMatrices),
 		fmt.Sprintf("/apis/%s/%s", identityapi.SchemeGroupVersion, identityapi.ResourceSelfSubjectNamespaceAccessReviews),
 		fmt.Sprintf("/apis/%s/%s", identityapi.SchemeGroupVersion, identityapi.ResourceSiteInfos),
 	}
@@ -206,6 +234,18 @@ func (o *UIServerOptions) Config() (*apiserver.Config, error) {
 	if err := o.ExtraOptions.ApplyTo(&extraConfig); err != nil {
 		return nil, err
 	}
+	if err := o.OpenCostOptions.ApplyTo(&extraConfig); err != nil {
+		return nil, err
+	}
+	if err := o.CostOptions.ApplyTo(&extraConfig); err != nil {
+		return nil, err
+	}
+	if err := o.AccessReviewOptions.ApplyTo(&extraConfig); err != nil {
+		return nil, err
+	}
+	if err := o.ProjectOptions.ApplyTo(&extraConfig); err != nil {
+		return nil, err
+	}
 
 	config := &apiserver.Config{
 		GenericConfig: serverConfig,
@@ -0,0 +1,262 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package costbudget
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	costapi "kubeops.dev/ui-server/apis/cost/v1alpha1"
+
+	"gomodules.xyz/sets"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	cu "kmodules.xyz/client-go/client"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	EventReasonThresholdReached = "ThresholdReached"
+	EventReasonEvaluationFailed = "EvaluationFailed"
+
+	defaultInterval = time.Hour
+	idleAllocation  = "__idle__"
+)
+
+var defaultThresholds = []costapi.CostBudgetThreshold{
+	{Percent: 80},
+	{Percent: 100},
+	{Percent: 100, Forecast: true},
+}
+
+// Reporter computes cost reports with the configured cost backend, eg, the CostReport storage.
+type Reporter interface {
+	Report(ctx context.Context, req *costapi.CostReportRequest) (*costapi.CostReportResponse, error)
+}
+
+// CostBudgetReconciler reconciles a CostBudget object
+type CostBudgetReconciler struct {
+	client.Client
+	reporter Reporter
+	recorder record.EventRecorder
+	now      func() time.Time
+}
+
+func NewReconciler(kc client.Client, reporter Reporter, recorder record.EventRecorder) *CostBudgetReconciler {
+	return &CostBudgetReconciler{
+		Client:   kc,
+		reporter: reporter,
+		recorder: recorder,
+		now:      time.Now,
+	}
+}
+
+func (r *CostBudgetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	var b costapi.CostBudget
+	if err := r.Get(ctx, req.NamespacedName, &b); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	status, alerts, err := r.evaluate(ctx, &b, r.now())
+	if err != nil {
+		r.recorder.Event(&b, core.EventTypeWarning, EventReasonEvaluationFailed, err.Error())
+		status = b.Status.DeepCopy()
+		status.ObservedGeneration = b.Generation
+		status.Phase = costapi.CostBudgetPhaseFailed
+		status.Reason = err.Error()
+	}
+	for _, a := range alerts {
+		r.recorder.Event(&b, core.EventTypeWarning, EventReasonThresholdReached, alertMessage(&b, status, a))
+	}
+
+	vt, err := cu.PatchStatus(ctx, r.Client, &b, func(in client.Object) client.Object {
+		obj := in.(*costapi.CostBudget)
+		obj.Status = *status
+		return obj
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	log.Info(string(vt) + " CostBudget")
+
+	interval := defaultInterval
+	if b.Spec.Interval != nil && b.Spec.Interval.Duration > 0 {
+		interval = b.Spec.Interval.Duration
+	}
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// evaluate returns the status of the budget at the given time, along with the alerts
+// that were triggered since the last evaluation.
+func (r *CostBudgetReconciler) evaluate(ctx context.Context, b *costapi.CostBudget, now time.Time) (*costapi.CostBudgetStatus, []costapi.CostBudgetAlert, error) {
+	var scopes int
+	for _, set := range []bool{b.Spec.Namespace != "", b.Spec.Project != "", b.Spec.NamespaceSelector != nil} {
+		if set {
+			scopes++
+		}
+	}
+	if scopes != 1 {
+		return nil, nil, fmt.Errorf("exactly one of namespace, project and namespaceSelector must be set")
+	}
+
+	now = now.UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	resp, err := r.reporter.Report(ctx, &costapi.CostReportRequest{
+		Window:      "month",
+		Aggregate:   []string{"namespace"},
+		Accumulate:  true,
+		ProjectCost: b.Spec.Project != "",
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	spend, err := r.spendOf(ctx, b, resp)
+	if err != nil {
+		return nil, nil, err
+	}
+	var forecast float64
+	if elapsed := now.Sub(start); elapsed > 0 {
+		forecast = spend * float64(end.Sub(start)) / float64(elapsed)
+	}
+
+	status := costapi.CostBudgetStatus{
+		ObservedGeneration: b.Generation,
+		Phase:              costapi.CostBudgetPhaseUnderBudget,
+		Backend:            resp.Backend,
+		Currency:           resp.Currency,
+		Period: costapi.CostWindow{
+			Start: &metav1.Time{Time: start},
+			End:   &metav1.Time{Time: end},
+		},
+		Spend:              spend,
+		Forecast:           forecast,
+		LastEvaluationTime: &metav1.Time{Time: now},
+	}
+	switch {
+	case spend >= b.Spec.MonthlyAmount:
+		status.Phase = costapi.CostBudgetPhaseOverBudget
+	case forecast >= b.Spec.MonthlyAmount:
+		status.Phase = costapi.CostBudgetPhaseAtRisk
+	}
+
+	// alerts are kept until the end of the period, so that each threshold alerts once a month
+	if prev := b.Status.Period.Start; prev != nil && prev.Equal(status.Period.Start) {
+		status.Alerts = b.Status.Alerts
+	}
+	thresholds := b.Spec.Thresholds
+	if len(thresholds) == 0 {
+		thresholds = defaultThresholds
+	}
+	var alerts []costapi.CostBudgetAlert
+	for _, t := range thresholds {
+		value := spend
+		if t.Forecast {
+			value = forecast
+		}
+		if value < b.Spec.MonthlyAmount*float64(t.Percent)/100 || triggered(status.Alerts, t) {
+			continue
+		}
+		a := costapi.CostBudgetAlert{
+			CostBudgetThreshold: t,
+			TriggeredAt:         metav1.Time{Time: now},
+		}
+		status.Alerts = append(status.Alerts, a)
+		alerts = append(alerts, a)
+	}
+	return &status, alerts, nil
+}
+
+func (r *CostBudgetReconciler) spendOf(ctx context.Context, b *costapi.CostBudget, resp *costapi.CostReportResponse) (float64, error) {
+	if b.Spec.Project != "" {
+		for _, pc := range resp.Projects {
+			if pc.Project == b.Spec.Project {
+				return pc.Totals.TotalCost, nil
+			}
+		}
+		return 0, nil
+	}
+
+	namespaces := sets.NewString(b.Spec.Namespace)
+	if b.Spec.NamespaceSelector != nil {
+		sel, err := metav1.LabelSelectorAsSelector(b.Spec.NamespaceSelector)
+		if err != nil {
+			return 0, err
+		}
+		var list core.NamespaceList
+		if err := r.List(ctx, &list, client.MatchingLabelsSelector{Selector: sel}); err != nil {
+			return 0, err
+		}
+		namespaces = sets.NewString()
+		for _, ns := range list.Items {
+			namespaces.Insert(ns.Name)
+		}
+	}
+
+	var spend float64
+	for _, cs := range resp.Sets {
+		for _, a := range cs.Allocations {
+			if strings.Contains(a.Name, idleAllocation) {
+				continue
+			}
+			ns := a.Name
+			if a.Properties != nil && a.Properties.Namespace != "" {
+				ns = a.Properties.Namespace
+			}
+			if namespaces.Has(ns) {
+				spend += a.TotalCost
+			}
+		}
+	}
+	return spend, nil
+}
+
+func triggered(alerts []costapi.CostBudgetAlert, t costapi.CostBudgetThreshold) bool {
+	for _, a := range alerts {
+		if a.CostBudgetThreshold == t {
+			return true
+		}
+	}
+	return false
+}
+
+func alertMessage(b *costapi.CostBudget, status *costapi.CostBudgetStatus, a costapi.CostBudgetAlert) string {
+	kind, value := "Spend", status.Spend
+	if a.Forecast {
+		kind, value = "Forecasted spend", status.Forecast
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s %.2f reached %d%% of the monthly budget %.2f %s",
+		kind, value, a.Percent, b.Spec.MonthlyAmount, status.Currency))
+}
+
+// SetupWithManager sets up the controller with the Manager. Status updates don't trigger a
+// reconcile, the budget is re-evaluated at its interval.
+func (r *CostBudgetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&costapi.CostBudget{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package costbudget
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	costapi "kubeops.dev/ui-server/apis/cost/v1alpha1"
+
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type fakeReporter costapi.CostReportResponse
+
+func (r *fakeReporter) Report(_ context.Context, _ *costapi.CostReportRequest) (*costapi.CostReportResponse, error) {
+	return (*costapi.CostReportResponse)(r), nil
+}
+
+func TestEvaluate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	kc := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&core.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a-dev", Labels: map[string]string{"team": "a"}}},
+		&core.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a-prod", Labels: map[string]string{"team": "a"}}},
+		&core.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}},
+	).Build()
+
+	r := &CostBudgetReconciler{
+		Client: kc,
+		reporter: &fakeReporter{
+			Backend: costapi.CostBackendLocal,
+			Sets: []costapi.CostAllocationSet{
+				{
+					Allocations: []costapi.CostAllocation{
+						{Name: "team-a-dev", TotalCost: 100},
+						{Name: "team-a-prod", TotalCost: 200},
+						{Name: "team-b", TotalCost: 400},
+						{Name: "__idle__", TotalCost: 1000},
+					},
+				},
+			},
+		},
+	}
+
+	b := &costapi.CostBudget{
+		Spec: costapi.CostBudgetSpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+			MonthlyAmount:     1000,
+		},
+	}
+	// a third of April has passed
+	now := time.Date(2024, 4, 11, 0, 0, 0, 0, time.UTC)
+
+	status, alerts, err := r.evaluate(context.TODO(), b, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Spend != 300 || math.Abs(status.Forecast-900) > 1e-9 || status.Phase != costapi.CostBudgetPhaseUnderBudget {
+		t.Errorf("unexpected status %+v", status)
+	}
+	if len(alerts) != 0 {
+		t.Errorf("unexpected alerts %+v", alerts)
+	}
+
+	b.Spec.MonthlyAmount = 500
+	status, alerts, err = r.evaluate(context.TODO(), b, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Phase != costapi.CostBudgetPhaseAtRisk || len(alerts) != 1 || !alerts[0].Forecast {
+		t.Errorf("expected forecast alert, got %s %+v", status.Phase, alerts)
+	}
+
+	// alerts are raised once per period
+	b.Status = *status
+	if _, alerts, _ = r.evaluate(context.TODO(), b, now.Add(time.Hour)); len(alerts) != 0 {
+		t.Errorf("unexpected repeated alerts %+v", alerts)
+	}
+	if _, alerts, _ = r.evaluate(context.TODO(), b, now.AddDate(0, 1, 0)); len(alerts) != 1 {
+		t.Errorf("expected alert in the next period, got %+v", alerts)
+	}
+
+	b.Spec.Namespace = "team-b"
+	if _, _, err = r.evaluate(context.TODO(), b, now); err == nil {
+		t.Error("expected error for multiple scopes")
+	}
+}
@@ -266,9 +266,16 @@ func (r *ProjectQuotaReconciler) CalculateStatus(pj *v1alpha1.ProjectQuota) erro
 }
 
 func (r *ProjectQuotaReconciler) getNamespaceList(name string) (*core.NamespaceList, error) {
+	return NamespacesOfProjectQuota(context.TODO(), r.Client, name)
+}
+
+// NamespacesOfProjectQuota returns the namespaces governed by a ProjectQuota. For Rancher managed
+// clusters, these are the namespaces of the Rancher project with the same name. Otherwise, the
+// ProjectQuota applies to the namespace with the same name.
+func NamespacesOfProjectQuota(ctx context.Context, kc client.Client, name string) (*core.NamespaceList, error) {
 	var nsList core.NamespaceList
-	if clustermeta.IsRancherManaged(r.RESTMapper()) {
-		err := r.List(context.TODO(), &nsList, client.MatchingLabels{
+	if clustermeta.IsRancherManaged(kc.RESTMapper()) {
+		err := kc.List(ctx, &nsList, client.MatchingLabels{
 			clustermeta.LabelKeyRancherFieldProjectId: name,
 		})
 		if err != nil {
@@ -279,7 +286,7 @@ func (r *ProjectQuotaReconciler) getNamespaceList(name string) (*core.NamespaceL
 		}
 	}
 
-	err := r.List(context.TODO(), &nsList, client.MatchingLabels{
+	err := kc.List(ctx, &nsList, client.MatchingLabels{
 		core.LabelMetadataName: name,
 	})
 	if err != nil {
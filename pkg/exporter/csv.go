@@ -0,0 +1,51 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporter
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+var csvHeader = []string{"Rule", "Rule Name", "Level", "Severity", "Kind", "Location", "Message"}
+
+func renderCSV(w io.Writer, r *Report) error {
+	names := make(map[string]string, len(r.Rules))
+	for _, rule := range r.Rules {
+		names[rule.ID] = rule.Name
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, f := range r.Findings {
+		if err := cw.Write([]string{
+			f.RuleID,
+			names[f.RuleID],
+			string(f.Level),
+			f.Severity,
+			f.Location.Kind,
+			f.Location.FullyQualifiedName,
+			f.Message,
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
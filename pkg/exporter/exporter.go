@@ -0,0 +1,115 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+type Format string
+
+const (
+	FormatSARIF Format = "sarif"
+	FormatCSV   Format = "csv"
+	FormatHTML  Format = "html"
+)
+
+// Level follows the SARIF result levels.
+type Level string
+
+const (
+	LevelError   Level = "error"
+	LevelWarning Level = "warning"
+	LevelNote    Level = "note"
+)
+
+// Report is the format independent content of an exported policy or CVE report.
+type Report struct {
+	Title       string
+	Tool        string
+	ToolURI     string
+	Scope       string
+	GeneratedAt time.Time
+	Rules       []Rule
+	Findings    []Finding
+}
+
+type Rule struct {
+	ID      string
+	Name    string
+	HelpURI string
+	Level   Level
+}
+
+type Finding struct {
+	RuleID   string
+	Level    Level
+	Severity string
+	Message  string
+	Location Location
+}
+
+// Location identifies the Kubernetes object or image that a finding was reported for.
+type Location struct {
+	Kind string
+	Name string
+	// FullyQualifiedName uniquely identifies the location, eg, apps/v1/Deployment/demo/web
+	FullyQualifiedName string
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatSARIF:
+		return "application/sarif+json"
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatHTML:
+		return "text/html; charset=utf-8"
+	}
+	return "application/octet-stream"
+}
+
+func (f Format) Valid() bool {
+	switch f {
+	case FormatSARIF, FormatCSV, FormatHTML:
+		return true
+	}
+	return false
+}
+
+func Render(w io.Writer, f Format, r *Report) error {
+	switch f {
+	case FormatSARIF:
+		return renderSARIF(w, r)
+	case FormatCSV:
+		return renderCSV(w, r)
+	case FormatHTML:
+		return renderHTML(w, r)
+	}
+	return fmt.Errorf("unsupported export format %q", f)
+}
+
+// LevelCounts returns the number of findings at each level.
+func (r *Report) LevelCounts() map[Level]int {
+	counts := map[Level]int{}
+	for _, f := range r.Findings {
+		counts[f.Level]++
+	}
+	return counts
+}
@@ -0,0 +1,118 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	policyapi "kubeops.dev/ui-server/apis/policy/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func testReport() *Report {
+	return FromPolicyReport(&policyapi.PolicyReportResponse{
+		Constraints: []policyapi.Constraint{
+			{
+				Name: "must-have-owner",
+				GVR: schema.GroupVersionResource{
+					Group:    "constraints.gatekeeper.sh",
+					Version:  "v1beta1",
+					Resource: "k8srequiredlabels",
+				},
+				Violations: []policyapi.StatusViolation{
+					{
+						Group:             "apps",
+						Version:           "v1",
+						Kind:              "Deployment",
+						Namespace:         "demo",
+						Name:              "web",
+						Message:           `missing label "owner", <b>`,
+						EnforcementAction: "deny",
+					},
+					{
+						Version:           "v1",
+						Kind:              "Pod",
+						Namespace:         "demo",
+						Name:              "web-0",
+						Message:           "missing label",
+						EnforcementAction: "warn",
+					},
+				},
+			},
+		},
+	}, "namespace demo", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+}
+
+func TestFromPolicyReport(t *testing.T) {
+	r := testReport()
+	if len(r.Rules) != 1 || r.Rules[0].ID != "k8srequiredlabels/must-have-owner" || r.Rules[0].Level != LevelError {
+		t.Fatalf("unexpected rules %+v", r.Rules)
+	}
+	counts := r.LevelCounts()
+	if counts[LevelError] != 1 || counts[LevelWarning] != 1 {
+		t.Errorf("unexpected level counts %v", counts)
+	}
+	if got := r.Findings[0].Location.FullyQualifiedName; got != "apps/v1/Deployment/demo/web" {
+		t.Errorf("unexpected location %s", got)
+	}
+}
+
+func TestRender(t *testing.T) {
+	r := testReport()
+
+	var buf bytes.Buffer
+	if err := Render(&buf, FormatSARIF, r); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != sarifVersion || len(log.Runs) != 1 || len(log.Runs[0].Results) != 2 {
+		t.Errorf("unexpected sarif log %+v", log)
+	}
+
+	buf.Reset()
+	if err := Render(&buf, FormatCSV, r); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || rows[1][0] != "k8srequiredlabels/must-have-owner" || rows[1][2] != "error" {
+		t.Errorf("unexpected csv rows %v", rows)
+	}
+
+	buf.Reset()
+	if err := Render(&buf, FormatHTML, r); err != nil {
+		t.Fatal(err)
+	}
+	if html := buf.String(); !strings.Contains(html, "&lt;b&gt;") || !strings.Contains(html, "Error: 1") {
+		t.Errorf("unexpected html output")
+	}
+
+	if err := Render(&buf, Format("pdf"), r); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
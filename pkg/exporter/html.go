@@ -0,0 +1,95 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporter
+
+import (
+	"html/template"
+	"io"
+	"time"
+)
+
+// The html summary is self-contained, so that it can be attached to audit
+// tickets as is. No scripts, fonts or stylesheets are loaded from outside.
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292f; }
+h1 { font-size: 1.6em; margin-bottom: 0.2em; }
+.meta { color: #57606a; margin-bottom: 1.5em; }
+.summary span { display: inline-block; padding: 0.4em 0.8em; margin-right: 0.5em; border-radius: 4px; font-weight: 600; }
+.error { background: #ffebe9; color: #cf222e; }
+.warning { background: #fff8c5; color: #9a6700; }
+.note { background: #ddf4ff; color: #0969da; }
+table { border-collapse: collapse; width: 100%; margin-top: 1.5em; font-size: 0.9em; }
+th, td { border: 1px solid #d0d7de; padding: 0.4em 0.6em; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+</style>
+</head>
+<body>
+<h1>{{ .Title }}</h1>
+<div class="meta">Scope: {{ .Scope }} &middot; Generated by {{ .Tool }} at {{ .GeneratedAt }}</div>
+<div class="summary">
+<span class="error">Error: {{ index .Counts "error" }}</span>
+<span class="warning">Warning: {{ index .Counts "warning" }}</span>
+<span class="note">Note: {{ index .Counts "note" }}</span>
+</div>
+<table>
+<thead>
+<tr><th>Rule</th><th>Level</th><th>Severity</th><th>Kind</th><th>Location</th><th>Message</th></tr>
+</thead>
+<tbody>
+{{- range .Findings }}
+<tr>
+{{- $rule := index $.Rules .RuleID }}
+<td>{{ if $rule.HelpURI }}<a href="{{ $rule.HelpURI }}">{{ .RuleID }}</a>{{ else }}{{ .RuleID }}{{ end }}{{ if $rule.Name }}<br>{{ $rule.Name }}{{ end }}</td>
+<td class="{{ .Level }}">{{ .Level }}</td>
+<td>{{ .Severity }}</td>
+<td>{{ .Location.Kind }}</td>
+<td>{{ .Location.FullyQualifiedName }}</td>
+<td>{{ .Message }}</td>
+</tr>
+{{- else }}
+<tr><td colspan="6">No findings</td></tr>
+{{- end }}
+</tbody>
+</table>
+</body>
+</html>
+`))
+
+func renderHTML(w io.Writer, r *Report) error {
+	rules := make(map[string]Rule, len(r.Rules))
+	for _, rule := range r.Rules {
+		rules[rule.ID] = rule
+	}
+	counts := map[string]int{}
+	for level, n := range r.LevelCounts() {
+		counts[string(level)] = n
+	}
+	return htmlTemplate.Execute(w, map[string]any{
+		"Title":       r.Title,
+		"Tool":        r.Tool,
+		"Scope":       r.Scope,
+		"GeneratedAt": r.GeneratedAt.UTC().Format(time.RFC1123),
+		"Counts":      counts,
+		"Rules":       rules,
+		"Findings":    r.Findings,
+	})
+}
@@ -0,0 +1,184 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporter
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	reportsapi "kubeops.dev/scanner/apis/reports/v1alpha1"
+	policyapi "kubeops.dev/ui-server/apis/policy/v1alpha1"
+	"kubeops.dev/ui-server/pkg/shared"
+
+	kmapi "kmodules.xyz/client-go/api/v1"
+)
+
+// Scope describes the scope of a report request the same way the report storages interpret it.
+func Scope(oi *kmapi.ObjectInfo) string {
+	switch {
+	case shared.IsClusterRequest(oi):
+		return "cluster"
+	case shared.IsNamespaceRequest(oi):
+		return "namespace " + oi.Ref.Name
+	case shared.IsImageRequest(oi):
+		return "image " + oi.Ref.Name
+	case shared.IsCVERequest(oi):
+		if oi.Ref.Namespace != "" {
+			return fmt.Sprintf("%s in namespace %s", oi.Ref.Name, oi.Ref.Namespace)
+		}
+		return oi.Ref.Name
+	}
+	gk := oi.Resource.Kind
+	if gk == "" {
+		gk = oi.Resource.Name
+	}
+	if oi.Resource.Group != "" {
+		gk += "." + oi.Resource.Group
+	}
+	if oi.Ref.Namespace != "" {
+		return fmt.Sprintf("%s %s/%s", gk, oi.Ref.Namespace, oi.Ref.Name)
+	}
+	return fmt.Sprintf("%s %s", gk, oi.Ref.Name)
+}
+
+func FromPolicyReport(resp *policyapi.PolicyReportResponse, scope string, now time.Time) *Report {
+	r := Report{
+		Title:       "Policy Violation Report",
+		Tool:        "Gatekeeper",
+		ToolURI:     "https://open-policy-agent.github.io/gatekeeper",
+		Scope:       scope,
+		GeneratedAt: now,
+	}
+	if resp == nil {
+		return &r
+	}
+	for _, c := range resp.Constraints {
+		id := path.Join(c.GVR.Resource, c.Name)
+		rule := Rule{
+			ID:    id,
+			Name:  c.Name,
+			Level: LevelNote,
+		}
+		for _, v := range c.Violations {
+			level := enforcementLevel(v)
+			if levelRank(level) > levelRank(rule.Level) {
+				rule.Level = level
+			}
+			gv := path.Join(v.Group, v.Version)
+			r.Findings = append(r.Findings, Finding{
+				RuleID:   id,
+				Level:    level,
+				Severity: v.EnforcementAction,
+				Message:  v.Message,
+				Location: Location{
+					Kind:               v.Kind,
+					Name:               v.Name,
+					FullyQualifiedName: path.Join(gv, v.Kind, v.Namespace, v.Name),
+				},
+			})
+		}
+		r.Rules = append(r.Rules, rule)
+	}
+	return &r
+}
+
+func enforcementLevel(v policyapi.StatusViolation) Level {
+	actions := v.EnforcementActions
+	if len(actions) == 0 {
+		actions = []string{v.EnforcementAction}
+	}
+	level := LevelNote
+	for _, a := range actions {
+		var l Level
+		switch strings.ToLower(a) {
+		case "deny":
+			l = LevelError
+		case "warn":
+			l = LevelWarning
+		default: // dryrun
+			l = LevelNote
+		}
+		if levelRank(l) > levelRank(level) {
+			level = l
+		}
+	}
+	return level
+}
+
+func FromCVEReport(resp *reportsapi.CVEReportResponse, scope string, now time.Time) *Report {
+	r := Report{
+		Title:       "Image Vulnerability Report",
+		Tool:        "Trivy",
+		ToolURI:     "https://trivy.dev",
+		Scope:       scope,
+		GeneratedAt: now,
+	}
+	if resp == nil {
+		return &r
+	}
+	for _, cve := range resp.Vulnerabilities.CVEs {
+		level := severityLevel(cve.Severity)
+		r.Rules = append(r.Rules, Rule{
+			ID:      cve.VulnerabilityID,
+			Name:    cve.Title,
+			HelpURI: cve.PrimaryURL,
+			Level:   level,
+		})
+		for _, result := range cve.Results {
+			for _, tgt := range result.Targets {
+				msg := fmt.Sprintf("%s found in %s", cve.VulnerabilityID, tgt.Target)
+				if tgt.InstalledVersion != "" {
+					msg += fmt.Sprintf(", installed version %s", tgt.InstalledVersion)
+				}
+				r.Findings = append(r.Findings, Finding{
+					RuleID:   cve.VulnerabilityID,
+					Level:    level,
+					Severity: cve.Severity,
+					Message:  msg,
+					Location: Location{
+						Kind:               "Image",
+						Name:               result.Image,
+						FullyQualifiedName: fmt.Sprintf("%s//%s", result.Image, tgt.Target),
+					},
+				})
+			}
+		}
+	}
+	return &r
+}
+
+func severityLevel(severity string) Level {
+	switch strings.ToUpper(severity) {
+	case "CRITICAL", "HIGH":
+		return LevelError
+	case "MEDIUM":
+		return LevelWarning
+	}
+	return LevelNote
+}
+
+func levelRank(l Level) int {
+	switch l {
+	case LevelError:
+		return 2
+	case LevelWarning:
+		return 1
+	}
+	return 0
+}
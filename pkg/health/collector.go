@@ -0,0 +1,163 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	uicoreapi "kubeops.dev/ui-server/apis/core/v1alpha1"
+	"kubeops.dev/ui-server/pkg/graph"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	kmapi "kmodules.xyz/client-go/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CollectInterval is how often the Collector scores the resources.
+const CollectInterval = 5 * time.Minute
+
+// Target is a resource to score with the inputs that come from the resource itself.
+type Target struct {
+	kmapi.ObjectInfo
+	Inputs Inputs
+}
+
+// TargetLister lists the resources the Collector scores.
+type TargetLister func(ctx context.Context) ([]Target, error)
+
+// ResourceScore is the HealthScore of a resource.
+type ResourceScore struct {
+	schema.GroupKind
+	Namespace string
+	Name      string
+	Score     uicoreapi.HealthScore
+}
+
+// Collector scores the resources in the background, so that the API and the metric
+// serve the same scores without collecting CVEs and policy violations per request.
+type Collector struct {
+	kc        client.Client
+	namespace string
+	targets   TargetLister
+
+	mu     sync.RWMutex
+	scores map[kmapi.ObjectID]ResourceScore
+}
+
+// NewCollector returns a Collector that reads the HealthPolicy from the PolicyConfigMap
+// in namespace. SetTargets must be called before it is started.
+func NewCollector(kc client.Client, namespace string) *Collector {
+	return &Collector{
+		kc:        kc,
+		namespace: namespace,
+		scores:    map[kmapi.ObjectID]ResourceScore{},
+	}
+}
+
+// SetTargets sets the lister of the resources to score.
+func (c *Collector) SetTargets(targets TargetLister) {
+	c.targets = targets
+}
+
+// Start scores the resources every CollectInterval until ctx is canceled.
+// It implements manager.Runnable.
+func (c *Collector) Start(ctx context.Context) error {
+	return wait.PollUntilContextCancel(ctx, CollectInterval, true, func(ctx context.Context) (bool, error) {
+		c.collect(ctx)
+		return false, nil
+	})
+}
+
+// collect replaces the scores with the ones of the resources listed now, so deleted
+// resources are dropped. The scores are kept if the resources can't be listed.
+func (c *Collector) collect(ctx context.Context) {
+	targets, err := c.targets(ctx)
+	if err != nil {
+		klog.ErrorS(err, "failed to list resources to score")
+		return
+	}
+
+	policy := LoadPolicy(ctx, c.kc, c.namespace)
+	var constraints []constraintViolations
+	if graph.OPAInstalled.Load() && policy.PolicyViolationPenalty > 0 {
+		constraints, err = listConstraintViolations(ctx, c.kc)
+		if err != nil {
+			klog.ErrorS(err, "failed to list policy violations")
+		}
+	}
+
+	scores := make(map[kmapi.ObjectID]ResourceScore, len(targets))
+	for _, t := range targets {
+		scores[objectID(t.ObjectInfo)] = ResourceScore{
+			GroupKind: t.Resource.GroupKind(),
+			Namespace: t.Ref.Namespace,
+			Name:      t.Ref.Name,
+			Score:     evaluate(ctx, c.kc, policy, constraints, t.ObjectInfo, t.Inputs),
+		}
+	}
+
+	c.mu.Lock()
+	c.scores = scores
+	c.mu.Unlock()
+}
+
+// Score returns the last score of the resource in oi. It reports false until the
+// resource has been scored once.
+func (c *Collector) Score(oi kmapi.ObjectInfo) (uicoreapi.HealthScore, bool) {
+	if c == nil {
+		return uicoreapi.HealthScore{}, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	s, ok := c.scores[objectID(oi)]
+	return s.Score, ok
+}
+
+// Scores returns the last scores of all the resources sorted by kind, namespace and name.
+func (c *Collector) Scores() []ResourceScore {
+	c.mu.RLock()
+	out := make([]ResourceScore, 0, len(c.scores))
+	for _, s := range c.scores {
+		out = append(out, s)
+	}
+	c.mu.RUnlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].GroupKind != out[j].GroupKind {
+			return out[i].GroupKind.String() < out[j].GroupKind.String()
+		}
+		if out[i].Namespace != out[j].Namespace {
+			return out[i].Namespace < out[j].Namespace
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+func objectID(oi kmapi.ObjectInfo) kmapi.ObjectID {
+	return kmapi.ObjectID{
+		Group:     oi.Resource.Group,
+		Kind:      oi.Resource.Kind,
+		Namespace: oi.Ref.Namespace,
+		Name:      oi.Ref.Name,
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"testing"
+
+	policyapi "kubeops.dev/ui-server/apis/policy/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	kmapi "kmodules.xyz/client-go/api/v1"
+	mgmtapi "kmodules.xyz/resource-metadata/apis/management/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func target(kind, namespace, name, status string) Target {
+	return Target{
+		ObjectInfo: kmapi.ObjectInfo{
+			Resource: kmapi.ResourceID{Group: "kubedb.com", Kind: kind},
+			Ref:      kmapi.ObjectReference{Namespace: namespace, Name: name},
+		},
+		Inputs: Inputs{Status: status},
+	}
+}
+
+func TestCollect(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = mgmtapi.AddToScheme(scheme)
+	kc := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	targets := []Target{
+		target("Postgres", "demo", "pg", "Current"),
+		target("MongoDB", "demo", "mg", "Failed"),
+	}
+	c := NewCollector(kc, "")
+	c.SetTargets(func(ctx context.Context) ([]Target, error) {
+		return targets, nil
+	})
+
+	if _, ok := c.Score(targets[0].ObjectInfo); ok {
+		t.Fatal("expected no score before the first collection")
+	}
+
+	c.collect(context.TODO())
+	healthy, ok := c.Score(targets[0].ObjectInfo)
+	if !ok {
+		t.Fatal("expected a score for pg")
+	}
+	failed, _ := c.Score(targets[1].ObjectInfo)
+	if failed.Score >= healthy.Score {
+		t.Errorf("expected the failed resource to score lower, found %d >= %d", failed.Score, healthy.Score)
+	}
+	if scores := c.Scores(); len(scores) != 2 || scores[0].Kind != "MongoDB" {
+		t.Errorf("expected the scores sorted by kind, found %+v", scores)
+	}
+
+	targets = targets[:1]
+	c.collect(context.TODO())
+	if _, ok := c.Score(kmapi.ObjectInfo{
+		Resource: kmapi.ResourceID{Group: "kubedb.com", Kind: "MongoDB"},
+		Ref:      kmapi.ObjectReference{Namespace: "demo", Name: "mg"},
+	}); ok {
+		t.Error("expected the score of a deleted resource to be dropped")
+	}
+}
+
+func TestPolicyViolations(t *testing.T) {
+	kc := fake.NewClientBuilder().Build()
+	violation := func(ns string) policyapi.StatusViolation {
+		return policyapi.StatusViolation{Kind: "Pod", Namespace: ns, Name: "p"}
+	}
+	constraints := []constraintViolations{
+		{listed: []policyapi.StatusViolation{violation("a"), violation("a"), violation("b")}, unlisted: 5},
+		{listed: []policyapi.StatusViolation{violation("b")}},
+	}
+
+	cases := map[string]struct {
+		n            int
+		undercounted bool
+	}{
+		"a": {n: 2, undercounted: true},
+		"b": {n: 2, undercounted: true},
+		"c": {n: 0},
+	}
+	for ns, expected := range cases {
+		n, undercounted, err := policyViolations(kc, constraints, kmapi.ObjectInfo{
+			Resource: kmapi.ResourceID{Kind: "Namespace"},
+			Ref:      kmapi.ObjectReference{Name: ns},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != expected.n || undercounted != expected.undercounted {
+			t.Errorf("namespace %s: expected %d violations (undercounted %v), found %d (%v)", ns, expected.n, expected.undercounted, n, undercounted)
+		}
+	}
+}
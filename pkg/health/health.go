@@ -0,0 +1,219 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	uicoreapi "kubeops.dev/ui-server/apis/core/v1alpha1"
+
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+	rscoreapi "kmodules.xyz/resource-metadata/apis/core/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// PolicyConfigMap is the ConfigMap in the namespace of the server whose policy.yaml key
+	// holds a HealthPolicy. Its fields override the ones of the DefaultPolicy.
+	PolicyConfigMap = "ui-server-health-policy"
+	keyPolicy       = "policy.yaml"
+
+	maxScore = 100
+)
+
+// DefaultPolicy is used unless it is overridden by the PolicyConfigMap.
+var DefaultPolicy = uicoreapi.HealthPolicy{
+	StatusPenalties: map[string]int32{
+		"Failed":      50,
+		"Unknown":     20,
+		"InProgress":  10,
+		"Terminating": 10,
+	},
+	FacilityPenalties: map[string]uicoreapi.FacilityPenalty{
+		uicoreapi.FacilityBackup:     {Usage: string(rscoreapi.FacilityUnused), Penalty: 15},
+		uicoreapi.FacilityMonitoring: {Usage: string(rscoreapi.FacilityUnused), Penalty: 10},
+		uicoreapi.FacilityTLS:        {Usage: string(rscoreapi.FacilityUnused), Penalty: 10},
+		uicoreapi.FacilityExposed:    {Usage: string(rscoreapi.FacilityUsed), Penalty: 10},
+	},
+	CVEPenalties: map[string]int32{
+		"CRITICAL": 10,
+		"HIGH":     5,
+		"MEDIUM":   1,
+	},
+	MaxCVEPenalty:             40,
+	PolicyViolationPenalty:    5,
+	MaxPolicyViolationPenalty: 20,
+	Quota: uicoreapi.QuotaPenalty{
+		ThresholdPercent: 80,
+		Penalty:          5,
+		ExceededPenalty:  15,
+	},
+	Grades: []uicoreapi.HealthGradeThreshold{
+		{Grade: uicoreapi.HealthGradeA, MinScore: 90},
+		{Grade: uicoreapi.HealthGradeB, MinScore: 75},
+		{Grade: uicoreapi.HealthGradeC, MinScore: 60},
+		{Grade: uicoreapi.HealthGradeD, MinScore: 40},
+	},
+}
+
+// LoadPolicy returns the DefaultPolicy overridden by the PolicyConfigMap in namespace.
+// An invalid ConfigMap is logged and ignored.
+func LoadPolicy(ctx context.Context, kc client.Reader, namespace string) *uicoreapi.HealthPolicy {
+	policy := DefaultPolicy.DeepCopy()
+	if namespace == "" {
+		return policy
+	}
+
+	var cm core.ConfigMap
+	err := kc.Get(ctx, client.ObjectKey{Namespace: namespace, Name: PolicyConfigMap}, &cm)
+	if apierrors.IsNotFound(err) {
+		return policy
+	} else if err != nil {
+		klog.ErrorS(err, "failed to read health policy", "namespace", namespace, "name", PolicyConfigMap)
+		return policy
+	}
+	if err := yaml.Unmarshal([]byte(cm.Data[keyPolicy]), policy); err != nil {
+		klog.ErrorS(err, "failed to parse health policy", "namespace", namespace, "name", PolicyConfigMap)
+		return DefaultPolicy.DeepCopy()
+	}
+	return policy
+}
+
+// Inputs are the signals a HealthScore is computed from.
+type Inputs struct {
+	// Status is the computed status of the resource, eg, Current or Failed.
+	Status string
+	// Facilities are keyed by the facility name.
+	Facilities map[string]rscoreapi.FacilityUsage
+	// CVEs are the number of unique CVEs in the images of the resource keyed by severity.
+	CVEs map[string]int
+	// PolicyViolations is the number of violations of Gatekeeper constraints in the
+	// resource graph of the resource.
+	PolicyViolations int
+	// PolicyViolationsUndercounted is set if a constraint violated by the resource lists
+	// only part of its violations, so the resource may have more of them.
+	PolicyViolationsUndercounted bool
+	Quotas                       []QuotaUsage
+}
+
+// QuotaUsage is the usage of a resource limited by a ProjectQuota rule that applies to
+// the kind of the resource.
+type QuotaUsage struct {
+	Quota    string
+	Rule     string
+	Resource core.ResourceName
+	Used     resource.Quantity
+	Hard     resource.Quantity
+}
+
+// Score computes the HealthScore of the inputs according to policy.
+func Score(policy *uicoreapi.HealthPolicy, in Inputs) uicoreapi.HealthScore {
+	var reasons []uicoreapi.HealthReason
+	add := func(check uicoreapi.HealthCheck, penalty int32, format string, args ...any) {
+		if penalty > 0 {
+			reasons = append(reasons, uicoreapi.HealthReason{
+				Check:   check,
+				Penalty: penalty,
+				Message: fmt.Sprintf(format, args...),
+			})
+		}
+	}
+
+	add(uicoreapi.HealthCheckStatus, policy.StatusPenalties[in.Status], "status is %s", in.Status)
+
+	for _, name := range sortedKeys(in.Facilities) {
+		fp, ok := policy.FacilityPenalties[name]
+		if ok && string(in.Facilities[name]) == fp.Usage {
+			add(uicoreapi.HealthCheckFacility, fp.Penalty, "%s is %s", name, fp.Usage)
+		}
+	}
+
+	var cvePenalty int32
+	for _, severity := range sortedKeys(in.CVEs) {
+		n := in.CVEs[severity]
+		penalty := capPenalty(policy.CVEPenalties[severity]*int32(n), policy.MaxCVEPenalty-cvePenalty, policy.MaxCVEPenalty)
+		cvePenalty += penalty
+		add(uicoreapi.HealthCheckCVE, penalty, "%d %s CVE(s)", n, severity)
+	}
+
+	var undercounted string
+	if in.PolicyViolationsUndercounted {
+		undercounted = ", may be undercounted"
+	}
+	add(uicoreapi.HealthCheckPolicyViolation,
+		capPenalty(policy.PolicyViolationPenalty*int32(in.PolicyViolations), policy.MaxPolicyViolationPenalty, policy.MaxPolicyViolationPenalty),
+		"%d policy violation(s)%s", in.PolicyViolations, undercounted)
+
+	for _, q := range in.Quotas {
+		if q.Hard.IsZero() {
+			continue
+		}
+		percent := q.Used.AsApproximateFloat64() * 100 / q.Hard.AsApproximateFloat64()
+		switch {
+		case percent >= 100:
+			add(uicoreapi.HealthCheckQuota, policy.Quota.ExceededPenalty,
+				"ProjectQuota %q rule %q exhausted: %s used of %s %s", q.Quota, q.Rule, q.Used.String(), q.Hard.String(), q.Resource)
+		case policy.Quota.ThresholdPercent > 0 && percent >= float64(policy.Quota.ThresholdPercent):
+			add(uicoreapi.HealthCheckQuota, policy.Quota.Penalty,
+				"ProjectQuota %q rule %q at %.0f%%: %s used of %s %s", q.Quota, q.Rule, percent, q.Used.String(), q.Hard.String(), q.Resource)
+		}
+	}
+
+	score := int32(maxScore)
+	for _, r := range reasons {
+		score -= r.Penalty
+	}
+	score = max(score, 0)
+
+	return uicoreapi.HealthScore{
+		Score:   score,
+		Grade:   grade(policy.Grades, score),
+		Reasons: reasons,
+	}
+}
+
+// capPenalty limits penalty to the remaining budget if the limit is set.
+func capPenalty(penalty, remaining, limit int32) int32 {
+	if limit > 0 {
+		return min(penalty, max(remaining, 0))
+	}
+	return penalty
+}
+
+func grade(grades []uicoreapi.HealthGradeThreshold, score int32) uicoreapi.HealthGrade {
+	for _, g := range grades {
+		if score >= g.MinScore {
+			return g.Grade
+		}
+	}
+	return uicoreapi.HealthGradeF
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,112 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"testing"
+
+	uicoreapi "kubeops.dev/ui-server/apis/core/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	rscoreapi "kmodules.xyz/resource-metadata/apis/core/v1alpha1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestScore(t *testing.T) {
+	policy := DefaultPolicy.DeepCopy()
+
+	healthy := Score(policy, Inputs{
+		Status: "Current",
+		Facilities: map[string]rscoreapi.FacilityUsage{
+			uicoreapi.FacilityBackup:     rscoreapi.FacilityUsed,
+			uicoreapi.FacilityMonitoring: rscoreapi.FacilityUsed,
+			uicoreapi.FacilityTLS:        rscoreapi.FacilityUsed,
+			uicoreapi.FacilityExposed:    rscoreapi.FacilityUnused,
+		},
+	})
+	if healthy.Score != 100 || healthy.Grade != uicoreapi.HealthGradeA || len(healthy.Reasons) != 0 {
+		t.Errorf("expected a perfect score, got %+v", healthy)
+	}
+
+	hs := Score(policy, Inputs{
+		Status: "InProgress",
+		Facilities: map[string]rscoreapi.FacilityUsage{
+			uicoreapi.FacilityBackup:  rscoreapi.FacilityUnused,
+			uicoreapi.FacilityExposed: rscoreapi.FacilityUsed,
+		},
+		CVEs:             map[string]int{"CRITICAL": 3, "HIGH": 4, "LOW": 10},
+		PolicyViolations: 1,
+		Quotas: []QuotaUsage{
+			{Quota: "demo", Rule: "kubedb.com", Resource: "cpu", Used: resource.MustParse("900m"), Hard: resource.MustParse("1")},
+			{Quota: "demo", Rule: "kubedb.com", Resource: "memory", Used: resource.MustParse("1Gi"), Hard: resource.MustParse("4Gi")},
+		},
+	})
+	// status 10 + backup 15 + exposed 10 + CVEs 40 (capped) + violation 5 + quota 5
+	if hs.Score != 15 {
+		t.Errorf("expected score 15, got %d: %+v", hs.Score, hs.Reasons)
+	}
+	if hs.Grade != uicoreapi.HealthGradeF {
+		t.Errorf("expected grade F, got %s", hs.Grade)
+	}
+	if n := len(hs.Reasons); n != 7 {
+		t.Errorf("expected 7 reasons, got %d: %+v", n, hs.Reasons)
+	}
+
+	failed := Score(policy, Inputs{Status: "Failed", CVEs: map[string]int{"CRITICAL": 10}, PolicyViolations: 10})
+	if failed.Score != 0 {
+		t.Errorf("expected score to stop at 0, got %d", failed.Score)
+	}
+
+	undercounted := Score(policy, Inputs{PolicyViolations: 2, PolicyViolationsUndercounted: true})
+	if r := undercounted.Reasons; len(r) != 1 || r[0].Penalty != 10 || r[0].Message != "2 policy violation(s), may be undercounted" {
+		t.Errorf("unexpected reasons %+v", r)
+	}
+}
+
+func TestPolicyOverride(t *testing.T) {
+	policy := DefaultPolicy.DeepCopy()
+	err := yaml.Unmarshal([]byte(`
+statusPenalties:
+  InProgress: 0
+facilityPenalties:
+  networkPolicy:
+    usage: Unused
+    penalty: 5
+grades:
+- grade: A
+  minScore: 95
+`), policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if policy.StatusPenalties["Failed"] != 50 {
+		t.Error("expected default status penalties to be kept")
+	}
+	hs := Score(policy, Inputs{
+		Status: "InProgress",
+		Facilities: map[string]rscoreapi.FacilityUsage{
+			"networkPolicy": rscoreapi.FacilityUnused,
+		},
+	})
+	if hs.Score != 95 || hs.Grade != uicoreapi.HealthGradeA {
+		t.Errorf("expected score 95 with grade A, got %+v", hs)
+	}
+	if DefaultPolicy.StatusPenalties["InProgress"] != 10 {
+		t.Error("expected default policy to be unchanged")
+	}
+}
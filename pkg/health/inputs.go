@@ -0,0 +1,195 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"fmt"
+
+	uicoreapi "kubeops.dev/ui-server/apis/core/v1alpha1"
+	policyapi "kubeops.dev/ui-server/apis/policy/v1alpha1"
+	"kubeops.dev/ui-server/pkg/graph"
+	policyreports "kubeops.dev/ui-server/pkg/registry/policy/reports"
+	scannerreports "kubeops.dev/ui-server/pkg/registry/scanner/reports"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	kmapi "kmodules.xyz/client-go/api/v1"
+	clustermeta "kmodules.xyz/client-go/cluster"
+	mgmtapi "kmodules.xyz/resource-metadata/apis/management/v1alpha1"
+	reportsapi "kubeops.dev/scanner/apis/reports/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// evaluate collects the CVEs and the ProjectQuota pressure of the resource in oi, counts
+// its violations of the constraints and scores them along with its status and
+// facilities. Signals that can't be collected are logged and left out of the score.
+func evaluate(ctx context.Context, kc client.Client, policy *uicoreapi.HealthPolicy, constraints []constraintViolations, oi kmapi.ObjectInfo, in Inputs) uicoreapi.HealthScore {
+	if graph.ScannerInstalled.Load() && len(policy.CVEPenalties) > 0 {
+		cves, err := cveCounts(ctx, kc, oi)
+		if err != nil {
+			klog.ErrorS(err, "failed to collect CVEs", "resource", oi.Resource.GroupKind(), "namespace", oi.Ref.Namespace, "name", oi.Ref.Name)
+		}
+		in.CVEs = cves
+	}
+	if len(constraints) > 0 {
+		n, undercounted, err := policyViolations(kc, constraints, oi)
+		if err != nil {
+			klog.ErrorS(err, "failed to collect policy violations", "resource", oi.Resource.GroupKind(), "namespace", oi.Ref.Namespace, "name", oi.Ref.Name)
+		}
+		in.PolicyViolations = n
+		in.PolicyViolationsUndercounted = undercounted
+	}
+	if policy.Quota.Penalty > 0 || policy.Quota.ExceededPenalty > 0 {
+		quotas, err := quotaUsages(ctx, kc, oi)
+		if err != nil {
+			klog.ErrorS(err, "failed to collect quota usage", "resource", oi.Resource.GroupKind(), "namespace", oi.Ref.Namespace, "name", oi.Ref.Name)
+		}
+		in.Quotas = quotas
+	}
+	return Score(policy, in)
+}
+
+func cveCounts(ctx context.Context, kc client.Client, oi kmapi.ObjectInfo) (map[string]int, error) {
+	obj, err := scannerreports.NewStorage(kc).Create(ctx, &reportsapi.CVEReport{
+		Request: &reportsapi.CVEReportRequest{ObjectInfo: oi},
+	}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	rpt := obj.(*reportsapi.CVEReport)
+	if rpt.Response == nil {
+		return nil, nil
+	}
+	cves := map[string]int{}
+	for severity, stats := range rpt.Response.Vulnerabilities.Stats {
+		cves[severity] = stats.Count
+	}
+	return cves, nil
+}
+
+// constraintViolations are the violations of a Gatekeeper constraint. Gatekeeper lists
+// up to 20 of them by default, the rest are only counted in its totalViolations.
+type constraintViolations struct {
+	listed   []policyapi.StatusViolation
+	unlisted int
+}
+
+func listConstraintViolations(ctx context.Context, kc client.Client) ([]constraintViolations, error) {
+	templates, err := policyreports.ListTemplates(ctx, kc)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []constraintViolations
+	for _, template := range templates.Items {
+		kind, _, err := unstructured.NestedString(template.UnstructuredContent(), "spec", "crd", "spec", "names", "kind")
+		if err != nil {
+			return nil, err
+		}
+		constraints, err := policyreports.ListConstraints(ctx, kc, kind)
+		if err != nil {
+			return nil, err
+		}
+		for _, constraint := range constraints.Items {
+			listed, err := policyreports.GetViolationsOfConstraint(constraint)
+			if err != nil {
+				return nil, err
+			}
+			total, _, err := unstructured.NestedInt64(constraint.UnstructuredContent(), "status", "totalViolations")
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, constraintViolations{
+				listed:   listed,
+				unlisted: max(int(total)-len(listed), 0),
+			})
+		}
+	}
+	return result, nil
+}
+
+// policyViolations counts the listed violations of the constraints by the resource in oi.
+// The violations Gatekeeper left out of the lists can't be attributed, so the count is
+// reported as undercounted if a constraint that lists the resource has more of them.
+func policyViolations(kc client.Client, constraints []constraintViolations, oi kmapi.ObjectInfo) (int, bool, error) {
+	scp, err := policyreports.NewScope(kc, &oi)
+	if err != nil {
+		return 0, false, err
+	}
+
+	var n int
+	var undercounted bool
+	for _, c := range constraints {
+		if matched := len(scp.Filter(c.listed)); matched > 0 {
+			n += matched
+			undercounted = undercounted || c.unlisted > 0
+		}
+	}
+	return n, undercounted, nil
+}
+
+// quotaUsages returns the usage of the rules of the ProjectQuotas of the namespace of oi
+// that apply to its kind. The ProjectQuota of a namespace is named after the namespace,
+// or after its project in Rancher managed clusters.
+func quotaUsages(ctx context.Context, kc client.Client, oi kmapi.ObjectInfo) ([]QuotaUsage, error) {
+	names := []string{oi.Ref.Namespace}
+	if clustermeta.IsRancherManaged(kc.RESTMapper()) {
+		projectId, _, err := clustermeta.GetProjectId(kc, oi.Ref.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		names = append([]string{projectId}, names...)
+	}
+
+	var result []QuotaUsage
+	for _, name := range names {
+		var pq mgmtapi.ProjectQuota
+		err := kc.Get(ctx, client.ObjectKey{Name: name}, &pq)
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		} else if client.IgnoreNotFound(err) != nil {
+			return nil, err
+		} else if err != nil {
+			continue
+		}
+
+		for _, q := range pq.Status.Quotas {
+			if q.Result != mgmtapi.ResultSuccess || q.Group != oi.Resource.Group || (q.Kind != "" && q.Kind != oi.Resource.Kind) {
+				continue
+			}
+			for rk, hard := range q.Hard {
+				result = append(result, QuotaUsage{
+					Quota:    pq.Name,
+					Rule:     ruleName(q.ResourceQuotaSpec),
+					Resource: rk,
+					Used:     q.Used[rk],
+					Hard:     hard,
+				})
+			}
+		}
+	}
+	return result, nil
+}
+
+func ruleName(spec mgmtapi.ResourceQuotaSpec) string {
+	if spec.Kind != "" {
+		return fmt.Sprintf("%s/%s", spec.Group, spec.Kind)
+	}
+	return spec.Group
+}
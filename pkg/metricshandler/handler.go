@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"kubeops.dev/ui-server/pkg/graph"
+	"kubeops.dev/ui-server/pkg/health"
 	"kubeops.dev/ui-server/pkg/metricsstore"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -35,10 +36,11 @@ import (
 )
 
 const (
-	MetricsPath          = "/metrics"
-	scannerMetricPrefix  = "scanner_appscode_com_"
-	policyMetricPrefix   = "policy_appscode_com_"
-	MetricsRefreshPeriod = 2 * time.Second
+	MetricsPath            = "/metrics"
+	scannerMetricPrefix    = "scanner_appscode_com_"
+	policyMetricPrefix     = "policy_appscode_com_"
+	costBudgetMetricPrefix = "cost_budget_"
+	MetricsRefreshPeriod   = 2 * time.Second
 )
 
 var (
@@ -55,6 +57,7 @@ type Collector struct {
 	kc               client.Client
 	opaInstalled     bool
 	scannerInstalled bool
+	health           *health.Collector
 
 	generators []generator.FamilyGenerator
 	store      *metricsstore.MetricsStore
@@ -89,7 +92,7 @@ func (h *MetricsHandler) Install(c *mux.PathRecorderMux) {
 	c.Handle(MetricsPath, next)
 }
 
-func StartMetricsCollector(mgr manager.Manager) func(ctx context.Context) error {
+func StartMetricsCollector(mgr manager.Manager, scores *health.Collector) func(ctx context.Context) error {
 	return func(ctx context.Context) error {
 		klog.Infoln("Starts the Metrics Collector")
 		for {
@@ -97,6 +100,7 @@ func StartMetricsCollector(mgr manager.Manager) func(ctx context.Context) error
 				kc:               mgr.GetClient(),
 				opaInstalled:     graph.OPAInstalled.Load(),
 				scannerInstalled: graph.ScannerInstalled.Load(),
+				health:           scores,
 			}
 			collector.init()
 			err := collector.collectMetrics()
@@ -127,6 +131,14 @@ func (mc *Collector) collectMetrics() error {
 	}
 
 	offset := 1
+	if err := mc.collectCostBudgetMetrics(offset); err != nil {
+		return err
+	}
+	offset = offset + numCostBudgetMetrics
+
+	mc.collectHealthMetrics(offset)
+	offset++
+
 	if mc.scannerInstalled {
 		err := mc.collectScannerMetrics(offset)
 		if err != nil {
@@ -146,7 +158,7 @@ func (mc *Collector) collectMetrics() error {
 
 func (mc *Collector) initFamilyGenerators() {
 	fn := func(obj any) *metric.Family { return new(metric.Family) }
-	mc.generators = make([]generator.FamilyGenerator, 0, 14)
+	mc.generators = make([]generator.FamilyGenerator, 0, 19)
 
 	mc.generators = append(mc.generators, generator.FamilyGenerator{
 		Name:              "k8s_appscode_com_pod_ancestor",
@@ -156,6 +168,43 @@ func (mc *Collector) initFamilyGenerators() {
 		GenerateFunc:      fn,
 	})
 
+	mc.generators = append(mc.generators, generator.FamilyGenerator{
+		Name:              costBudgetMetricPrefix + "amount",
+		Help:              "Monthly amount of the cost budget",
+		Type:              metric.Gauge,
+		DeprecatedVersion: "",
+		GenerateFunc:      fn,
+	})
+	mc.generators = append(mc.generators, generator.FamilyGenerator{
+		Name:              costBudgetMetricPrefix + "spend",
+		Help:              "Spend of the current month counted against the cost budget",
+		Type:              metric.Gauge,
+		DeprecatedVersion: "",
+		GenerateFunc:      fn,
+	})
+	mc.generators = append(mc.generators, generator.FamilyGenerator{
+		Name:              costBudgetMetricPrefix + "forecast",
+		Help:              "Forecasted spend at the end of the current month",
+		Type:              metric.Gauge,
+		DeprecatedVersion: "",
+		GenerateFunc:      fn,
+	})
+	mc.generators = append(mc.generators, generator.FamilyGenerator{
+		Name:              costBudgetMetricPrefix + "utilization_ratio",
+		Help:              "Ratio of the spend to the monthly amount of the cost budget",
+		Type:              metric.Gauge,
+		DeprecatedVersion: "",
+		GenerateFunc:      fn,
+	})
+
+	mc.generators = append(mc.generators, generator.FamilyGenerator{
+		Name:              "k8s_appscode_com_resource_health_score",
+		Help:              "Health score of the GenericResourceServices",
+		Type:              metric.Gauge,
+		DeprecatedVersion: "",
+		GenerateFunc:      fn,
+	})
+
 	if mc.scannerInstalled {
 		mc.generators = append(mc.generators, generator.FamilyGenerator{
 			Name:              scannerMetricPrefix + "cluster_cve_occurrence",
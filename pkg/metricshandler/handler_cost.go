@@ -0,0 +1,76 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"context"
+
+	costapi "kubeops.dev/ui-server/apis/cost/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+const numCostBudgetMetrics = 4
+
+// collectCostBudgetMetrics exports the spend evaluated by the CostBudget controller.
+// Budgets are skipped if the CostBudget CRD is not installed.
+func (mc *Collector) collectCostBudgetMetrics(offset int) error {
+	var list costapi.CostBudgetList
+	if err := mc.kc.List(context.TODO(), &list); meta.IsNoMatchError(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	fAmount := mc.generators[offset].Generate(nil)
+	fSpend := mc.generators[offset+1].Generate(nil)
+	fForecast := mc.generators[offset+2].Generate(nil)
+	fUtilization := mc.generators[offset+3].Generate(nil)
+	for _, b := range list.Items {
+		if b.Status.LastEvaluationTime == nil {
+			continue
+		}
+		labelKeys := []string{"budget", "currency"}
+		labelValues := []string{b.Name, b.Status.Currency}
+
+		fAmount.Metrics = append(fAmount.Metrics, &metric.Metric{
+			LabelKeys:   labelKeys,
+			LabelValues: labelValues,
+			Value:       b.Spec.MonthlyAmount,
+		})
+		fSpend.Metrics = append(fSpend.Metrics, &metric.Metric{
+			LabelKeys:   labelKeys,
+			LabelValues: labelValues,
+			Value:       b.Status.Spend,
+		})
+		fForecast.Metrics = append(fForecast.Metrics, &metric.Metric{
+			LabelKeys:   labelKeys,
+			LabelValues: labelValues,
+			Value:       b.Status.Forecast,
+		})
+		if b.Spec.MonthlyAmount > 0 {
+			fUtilization.Metrics = append(fUtilization.Metrics, &metric.Metric{
+				LabelKeys:   []string{"budget"},
+				LabelValues: []string{b.Name},
+				Value:       b.Status.Spend / b.Spec.MonthlyAmount,
+			})
+		}
+	}
+	mc.store.Add(fAmount, fSpend, fForecast, fUtilization)
+	return nil
+}
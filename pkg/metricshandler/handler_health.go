@@ -0,0 +1,34 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+// collectHealthMetrics exports the last health scores of the GenericResourceServices.
+func (mc *Collector) collectHealthMetrics(offset int) {
+	family := mc.generators[offset].Generate(nil)
+	for _, r := range mc.health.Scores() {
+		family.Metrics = append(family.Metrics, &metric.Metric{
+			LabelKeys:   []string{"group", "kind", "namespace", "name", "grade"},
+			LabelValues: []string{r.Group, r.Kind, r.Namespace, r.Name, string(r.Score.Grade)},
+			Value:       float64(r.Score.Score),
+		})
+	}
+	mc.store.Add(family)
+}
@@ -0,0 +1,224 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opencost
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	core "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	PathAllocationCompute = "/model/allocation/compute"
+	PathHealthz           = "/healthz"
+
+	// maxResponseSize limits how much of an OpenCost response is read into memory.
+	maxResponseSize = 64 << 20
+)
+
+var selector = client.MatchingLabels{
+	"app.kubernetes.io/name": "opencost",
+}
+
+// ErrNotFound is returned when no OpenCost url is configured and no OpenCost service is running in the cluster.
+var ErrNotFound = errors.New("opencost service not found")
+
+type Config struct {
+	// URL of the OpenCost api. If empty, the OpenCost Service is discovered in the cluster.
+	URL             string
+	CACert          []byte
+	BearerToken     string
+	BearerTokenFile string
+	Timeout         time.Duration
+}
+
+// Client calls the OpenCost api. Unless a url is configured, the OpenCost Service
+// is looked up on every call, so that the client follows the Service if it is
+// recreated, moved to a different namespace or its port changes.
+type Client struct {
+	kc  client.Client
+	cfg Config
+	hc  *http.Client
+
+	mu      sync.Mutex
+	svcKey  string
+	baseURL *url.URL
+}
+
+func NewClient(kc client.Client, cfg Config) (*Client, error) {
+	c := &Client{
+		kc:  kc,
+		cfg: cfg,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if len(cfg.CACert) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(cfg.CACert) {
+			return nil, errors.New("failed to parse opencost CA certificate")
+		}
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+	c.hc = &http.Client{
+		Transport: transport,
+		Timeout:   cfg.Timeout,
+	}
+
+	if cfg.URL != "" {
+		u, err := url.Parse(cfg.URL)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid opencost url %s", cfg.URL)
+		}
+		c.baseURL = u
+	}
+	return c, nil
+}
+
+// Get calls the OpenCost api at the given path and returns the status code and body of the response.
+func (c *Client) Get(ctx context.Context, p string, query url.Values) (int, []byte, error) {
+	base, err := c.endpoint(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	u := *base
+	u.Path = path.Join(u.Path, p)
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "failed to create opencost service request")
+	}
+	token, err := c.bearerToken()
+	if err != nil {
+		return 0, nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "failed to call opencost service")
+	}
+	defer resp.Body.Close() // nolint:errcheck
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "failed to read opencost api response")
+	}
+	return resp.StatusCode, body, nil
+}
+
+// Health checks that the OpenCost api is reachable and ready.
+func (c *Client) Health(ctx context.Context) error {
+	code, body, err := c.Get(ctx, PathHealthz, nil)
+	if err != nil {
+		return err
+	}
+	if code != http.StatusOK {
+		msg := strings.TrimSpace(string(body))
+		if msg == "" {
+			msg = http.StatusText(code)
+		}
+		return fmt.Errorf("opencost is not healthy: %s", msg)
+	}
+	return nil
+}
+
+func (c *Client) bearerToken() (string, error) {
+	if c.cfg.BearerTokenFile == "" {
+		return c.cfg.BearerToken, nil
+	}
+	// read on every call, since projected tokens are rotated
+	data, err := os.ReadFile(c.cfg.BearerTokenFile)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read opencost bearer token file %s", c.cfg.BearerTokenFile)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func (c *Client) endpoint(ctx context.Context) (*url.URL, error) {
+	if c.cfg.URL != "" {
+		return c.baseURL, nil
+	}
+
+	var svcs core.ServiceList
+	if err := c.kc.List(ctx, &svcs, selector); err != nil {
+		return nil, err
+	}
+	if len(svcs.Items) == 0 {
+		return nil, ErrNotFound
+	}
+	sort.Slice(svcs.Items, func(i, j int) bool {
+		if svcs.Items[i].Namespace != svcs.Items[j].Namespace {
+			return svcs.Items[i].Namespace < svcs.Items[j].Namespace
+		}
+		return svcs.Items[i].Name < svcs.Items[j].Name
+	})
+	svc := svcs.Items[0]
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := fmt.Sprintf("%s/%s/%s", svc.UID, svc.Namespace, svc.ResourceVersion)
+	if key == c.svcKey && c.baseURL != nil {
+		return c.baseURL, nil
+	}
+
+	port, err := apiPort(svc)
+	if err != nil {
+		return nil, err
+	}
+	c.baseURL = &url.URL{
+		Scheme: "http",
+		Host:   fmt.Sprintf("%s.%s.svc:%d", svc.Name, svc.Namespace, port),
+	}
+	c.svcKey = key
+	klog.InfoS("discovered opencost service", "url", c.baseURL.String())
+	return c.baseURL, nil
+}
+
+func apiPort(svc core.Service) (int32, error) {
+	for _, p := range svc.Spec.Ports {
+		if p.Name == "http" {
+			return p.Port, nil
+		}
+	}
+	if len(svc.Spec.Ports) == 1 {
+		return svc.Spec.Ports[0].Port, nil
+	}
+	return 0, errors.Errorf("missing http port in opencost service %s/%s", svc.Namespace, svc.Name)
+}
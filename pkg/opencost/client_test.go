@@ -0,0 +1,165 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opencost
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testToken = "s3cr3t"
+
+// fakeOpenCost serves the OpenCost endpoints used by the ui-server.
+func fakeOpenCost(t *testing.T) http.Handler {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc(PathHealthz, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc(PathAllocationCompute, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+testToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("window") == "slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		_, _ = w.Write([]byte(`{"code":200,"status":"success","data":[{}]}`))
+	})
+	return mux
+}
+
+func TestClientExplicitURL(t *testing.T) {
+	srv := httptest.NewTLSServer(fakeOpenCost(t))
+	defer srv.Close()
+
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	c, err := NewClient(nil, Config{
+		URL:         srv.URL,
+		CACert:      ca,
+		BearerToken: testToken,
+		Timeout:     100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if err := c.Health(ctx); err != nil {
+		t.Fatalf("expected healthy backend, got %v", err)
+	}
+
+	code, body, err := c.Get(ctx, PathAllocationCompute, url.Values{"window": []string{"1d"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != http.StatusOK || len(body) == 0 {
+		t.Errorf("unexpected response %d %s", code, body)
+	}
+
+	if _, _, err := c.Get(ctx, PathAllocationCompute, url.Values{"window": []string{"slow"}}); err == nil {
+		t.Error("expected timeout error")
+	}
+}
+
+func TestClientUntrustedCA(t *testing.T) {
+	srv := httptest.NewTLSServer(fakeOpenCost(t))
+	defer srv.Close()
+
+	c, err := NewClient(nil, Config{URL: srv.URL, Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Health(context.Background()); err == nil {
+		t.Error("expected tls verification error")
+	}
+}
+
+func TestClientDiscovery(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	kc := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	c, err := NewClient(kc, Config{Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if _, err := c.endpoint(ctx); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	svc := &core.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "opencost",
+			Namespace: "opencost",
+			Labels: map[string]string{
+				"app.kubernetes.io/name": "opencost",
+			},
+		},
+		Spec: core.ServiceSpec{
+			Ports: []core.ServicePort{
+				{Name: "http-ui", Port: 9090},
+				{Name: "http", Port: 9003},
+			},
+		},
+	}
+	if err := kc.Create(ctx, svc); err != nil {
+		t.Fatal(err)
+	}
+	u, err := c.endpoint(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.String() != "http://opencost.opencost.svc:9003" {
+		t.Errorf("unexpected url %s", u)
+	}
+
+	// the service port is changed
+	svc.Spec.Ports[1].Port = 9004
+	if err := kc.Update(ctx, svc); err != nil {
+		t.Fatal(err)
+	}
+	u, err = c.endpoint(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.String() != "http://opencost.opencost.svc:9004" {
+		t.Errorf("expected rediscovered url, got %s", u)
+	}
+
+	// the service is removed
+	if err := kc.Delete(ctx, svc, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.endpoint(ctx); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opencost
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kmapi "kmodules.xyz/client-go/api/v1"
+)
+
+const (
+	// ConditionBackendAvailable reports whether the OpenCost api answered its last health check.
+	ConditionBackendAvailable kmapi.ConditionType = "CostBackendAvailable"
+
+	ReasonHealthy     = "Healthy"
+	ReasonUnavailable = "Unavailable"
+
+	healthInterval = 1 * time.Minute
+	healthTimeout  = 5 * time.Second
+)
+
+// HealthChecker checks the health of OpenCost in the background, so that API calls
+// report it without waiting on OpenCost.
+type HealthChecker struct {
+	c *Client
+
+	mu   sync.RWMutex
+	cond *kmapi.Condition
+}
+
+func NewHealthChecker(c *Client) *HealthChecker {
+	return &HealthChecker{c: c}
+}
+
+// Start checks the health of OpenCost until ctx is canceled. It implements manager.Runnable.
+func (h *HealthChecker) Start(ctx context.Context) error {
+	return wait.PollUntilContextCancel(ctx, healthInterval, true, func(ctx context.Context) (bool, error) {
+		h.check(ctx, time.Now())
+		return false, nil
+	})
+}
+
+func (h *HealthChecker) check(ctx context.Context, now time.Time) {
+	ctx, cancel := context.WithTimeout(ctx, healthTimeout)
+	defer cancel()
+
+	err := h.c.Health(ctx)
+	if errors.Is(err, ErrNotFound) {
+		h.set(nil, now)
+		return
+	}
+
+	cond := kmapi.Condition{
+		Type:   ConditionBackendAvailable,
+		Status: metav1.ConditionTrue,
+		Reason: ReasonHealthy,
+	}
+	if err != nil {
+		cond.Status = metav1.ConditionFalse
+		cond.Severity = kmapi.ConditionSeverityWarning
+		cond.Reason = ReasonUnavailable
+		cond.Message = err.Error()
+	}
+	h.set(&cond, now)
+}
+
+func (h *HealthChecker) set(cond *kmapi.Condition, now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if cond != nil {
+		cond.LastTransitionTime = metav1.NewTime(now)
+		if h.cond != nil && h.cond.Status == cond.Status {
+			cond.LastTransitionTime = h.cond.LastTransitionTime
+		}
+	}
+	h.cond = cond
+}
+
+// Condition returns the result of the last health check. It is nil before the first
+// check and while OpenCost is not running in the cluster.
+func (h *HealthChecker) Condition() *kmapi.Condition {
+	if h == nil {
+		return nil
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.cond == nil {
+		return nil
+	}
+	cond := *h.cond
+	return &cond
+}
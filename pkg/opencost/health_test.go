@@ -0,0 +1,73 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opencost
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestHealthChecker(t *testing.T) {
+	healthy := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy {
+			http.Error(w, "warming up", http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(nil, Config{URL: srv.URL, Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewHealthChecker(c)
+	if cond := h.Condition(); cond != nil {
+		t.Fatalf("expected no condition before the first check, found %+v", cond)
+	}
+
+	t0 := time.Now()
+	h.check(context.Background(), t0)
+	cond := h.Condition()
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Type != ConditionBackendAvailable {
+		t.Fatalf("expected a true condition, found %+v", cond)
+	}
+
+	h.check(context.Background(), t0.Add(time.Minute))
+	if cond := h.Condition(); !cond.LastTransitionTime.Time.Equal(t0) {
+		t.Errorf("expected the transition time to be kept while healthy, found %v", cond.LastTransitionTime)
+	}
+
+	healthy = false
+	h.check(context.Background(), t0.Add(2*time.Minute))
+	cond = h.Condition()
+	if cond.Status != metav1.ConditionFalse || cond.Reason != ReasonUnavailable || cond.Message == "" {
+		t.Errorf("expected a false condition with a message, found %+v", cond)
+	}
+	if !cond.LastTransitionTime.Time.Equal(t0.Add(2 * time.Minute)) {
+		t.Errorf("expected the transition time to be updated, found %v", cond.LastTransitionTime)
+	}
+
+	var nilChecker *HealthChecker
+	if nilChecker.Condition() != nil {
+		t.Error("expected no condition from a nil checker")
+	}
+}
@@ -0,0 +1,209 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package genericresource
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	kmapi "kmodules.xyz/client-go/api/v1"
+	rscoreapi "kmodules.xyz/resource-metadata/apis/core/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// listWorkers is the number of kinds listed in parallel when a List is not paginated.
+const listWorkers = 8
+
+// supportedFields are the fields of a GenericResource that can be used in a field selector.
+var supportedFields = []string{"metadata.name", "metadata.namespace", "spec.name", "spec.status.status"}
+
+func genericResourceFields(g *rscoreapi.GenericResource) fields.Set {
+	return fields.Set{
+		"metadata.name":      g.Name,
+		"metadata.namespace": g.Namespace,
+		"spec.name":          g.Spec.Name,
+		"spec.status.status": g.Spec.Status.Status,
+	}
+}
+
+// query holds the parameters of a List. The namespace and the object name required by the
+// field selector are passed on to the api server, the rest of the selector is applied to
+// the GenericResources.
+type query struct {
+	user   user.Info
+	ns     string
+	fields fields.Selector
+	cmeta  *kmapi.ClusterMetadata
+
+	// name is the required name of the objects, and gk their kind if set.
+	name string
+	gk   *schema.GroupKind
+	// empty is true if the selector can not match any object in ns.
+	empty bool
+}
+
+func newQuery(u user.Info, ns string, sel fields.Selector) (*query, error) {
+	if sel == nil {
+		sel = fields.Everything()
+	}
+	for _, req := range sel.Requirements() {
+		found := false
+		for _, f := range supportedFields {
+			if req.Field == f {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("field label not supported: %s", req.Field)
+		}
+	}
+
+	q := &query{
+		user:   u,
+		ns:     ns,
+		fields: sel,
+	}
+	if v, found := sel.RequiresExactMatch("metadata.namespace"); found {
+		if ns != "" && ns != v {
+			q.empty = true
+		}
+		q.ns = v
+	}
+	if v, found := sel.RequiresExactMatch("metadata.name"); found {
+		name, gk, err := rscoreapi.ParseGenericResourceName(v)
+		if err != nil {
+			return nil, err
+		}
+		q.name = name
+		q.gk = &gk
+	}
+	if v, found := sel.RequiresExactMatch("spec.name"); found {
+		if q.name != "" && q.name != v {
+			q.empty = true
+		}
+		q.name = v
+	}
+	return q, nil
+}
+
+// filter drops the kinds that can not match the query.
+func (q *query) filter(mappings []*meta.RESTMapping) []*meta.RESTMapping {
+	if q.gk == nil {
+		return mappings
+	}
+	for _, m := range mappings {
+		if m.GroupVersionKind.GroupKind() == *q.gk {
+			return []*meta.RESTMapping{m}
+		}
+	}
+	return nil
+}
+
+// listAll lists every kind in parallel, and returns the items in the order of the kinds.
+// The items of a kind keep the order of the api server, as in a paginated List, so that
+// the order does not depend on the limit.
+func (r *Storage) listAll(ctx context.Context, q *query, mappings []*meta.RESTMapping) ([]rscoreapi.GenericResource, error) {
+	results := make([][]rscoreapi.GenericResource, len(mappings))
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(listWorkers)
+	for i, mapping := range mappings {
+		g.Go(func() error {
+			items, _, err := r.listKind(ctx, q, mapping, 0, "")
+			if err != nil {
+				return err
+			}
+			results[i] = items
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	var n int
+	for _, items := range results {
+		n += len(items)
+	}
+	out := make([]rscoreapi.GenericResource, 0, n)
+	for _, items := range results {
+		out = append(out, items...)
+	}
+	return out, nil
+}
+
+// listKind returns the GenericResources of a kind that the user may get. It lists up to
+// limit objects from the api server, and returns the continue token of that list.
+func (r *Storage) listKind(ctx context.Context, q *query, mapping *meta.RESTMapping, limit int64, cont string) ([]rscoreapi.GenericResource, string, error) {
+	var list unstructured.UnstructuredList
+	list.SetGroupVersionKind(mapping.GroupVersionKind)
+	opts := client.ListOptions{
+		Namespace: q.ns,
+		Limit:     limit,
+		Continue:  cont,
+	}
+	if q.name != "" {
+		opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", q.name)
+	}
+	if err := r.kc.List(ctx, &list, &opts); err != nil {
+		if meta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
+			// the kind was removed since it was discovered
+			r.mapper.Reset()
+			return nil, "", nil
+		}
+		return nil, "", err
+	}
+
+	apiType := kmapi.NewResourceID(mapping)
+	attrs := authorizer.AttributesRecord{
+		User:            q.user,
+		Verb:            "get",
+		APIGroup:        mapping.Resource.Group,
+		Resource:        mapping.Resource.Resource,
+		ResourceRequest: true,
+	}
+	items := make([]rscoreapi.GenericResource, 0, len(list.Items))
+	for _, item := range list.Items {
+		attrs.Name = item.GetName()
+		attrs.Namespace = item.GetNamespace()
+		decision, _, err := r.a.Authorize(ctx, attrs)
+		if err != nil {
+			return nil, "", apierrors.NewInternalError(err)
+		}
+		if decision != authorizer.DecisionAllow {
+			continue
+		}
+
+		genres, err := rscoreapi.ToGenericResource(&item, apiType, q.cmeta)
+		if err != nil {
+			return nil, "", err
+		}
+		if !q.fields.Matches(genericResourceFields(genres)) {
+			continue
+		}
+		items = append(items, *genres)
+	}
+	return items, list.GetContinue(), nil
+}
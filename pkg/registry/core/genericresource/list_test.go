@@ -0,0 +1,74 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package genericresource
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	rscoreapi "kmodules.xyz/resource-metadata/apis/core/v1alpha1"
+)
+
+func TestNewQuery(t *testing.T) {
+	parse := func(s string) fields.Selector {
+		sel, err := fields.ParseSelector(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sel
+	}
+
+	if _, err := newQuery(nil, "", parse("spec.replicas=1")); err == nil {
+		t.Error("expected an error for an unsupported field")
+	}
+
+	q, err := newQuery(nil, "", parse("metadata.namespace=demo,metadata.name=pg~Postgres.kubedb.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.ns != "demo" || q.name != "pg" || q.gk == nil || *q.gk != (schema.GroupKind{Group: "kubedb.com", Kind: "Postgres"}) || q.empty {
+		t.Errorf("unexpected query %+v", q)
+	}
+	mappings := []*meta.RESTMapping{
+		{GroupVersionKind: schema.GroupVersionKind{Group: "kubedb.com", Version: "v1", Kind: "MongoDB"}},
+		{GroupVersionKind: schema.GroupVersionKind{Group: "kubedb.com", Version: "v1", Kind: "Postgres"}},
+	}
+	if got := q.filter(mappings); len(got) != 1 || got[0] != mappings[1] {
+		t.Errorf("filter() = %v, want the Postgres mapping", got)
+	}
+
+	// a client org member is restricted to their namespace
+	q, err = newQuery(nil, "org", parse("metadata.namespace=demo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !q.empty {
+		t.Error("expected an empty query for another namespace")
+	}
+
+	q, err = newQuery(nil, "", parse("spec.status.status=Current"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	current := &rscoreapi.GenericResource{Spec: rscoreapi.GenericResourceSpec{Status: rscoreapi.GenericResourceStatus{Status: "Current"}}}
+	failed := &rscoreapi.GenericResource{Spec: rscoreapi.GenericResourceSpec{Status: rscoreapi.GenericResourceStatus{Status: "Failed"}}}
+	if !q.fields.Matches(genericResourceFields(current)) || q.fields.Matches(genericResourceFields(failed)) {
+		t.Error("status field selector did not match as expected")
+	}
+}
@@ -18,14 +18,12 @@ package genericresource
 
 import (
 	"context"
-	"sort"
 	"strings"
 
 	"kubeops.dev/ui-server/pkg/shared"
 
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/internalversion"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -34,23 +32,22 @@ import (
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 	apirequest "k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/apiserver/pkg/registry/rest"
-	"k8s.io/client-go/discovery"
-	"k8s.io/client-go/discovery/cached/memory"
-	"k8s.io/client-go/restmapper"
-	"kmodules.xyz/apiversion"
 	kmapi "kmodules.xyz/client-go/api/v1"
 	clustermeta "kmodules.xyz/client-go/cluster"
 	rscoreapi "kmodules.xyz/resource-metadata/apis/core/v1alpha1"
-	"kmodules.xyz/resource-metrics/api"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type Storage struct {
 	kc        client.Client
-	dc        discovery.DiscoveryInterface
+	mapper    *shared.RESTMapper
 	clusterID string
 	a         authorizer.Authorizer
-	convertor rest.TableConvertor
+	informers cache.Informers
+	// watchAllKinds allows watches without a group-kind selector.
+	watchAllKinds bool
+	convertor     rest.TableConvertor
 }
 
 var (
@@ -59,15 +56,18 @@ var (
 	_ rest.Storage                  = &Storage{}
 	_ rest.Getter                   = &Storage{}
 	_ rest.Lister                   = &Storage{}
+	_ rest.Watcher                  = &Storage{}
 	_ rest.SingularNameProvider     = &Storage{}
 )
 
-func NewStorage(kc client.Client, dc discovery.DiscoveryInterface, clusterID string, a authorizer.Authorizer) *Storage {
+func NewStorage(kc client.Client, mapper *shared.RESTMapper, clusterID string, a authorizer.Authorizer, informers cache.Informers, watchAllKinds bool) *Storage {
 	return &Storage{
-		kc:        kc,
-		dc:        dc,
-		clusterID: clusterID,
-		a:         a,
+		kc:            kc,
+		mapper:        mapper,
+		clusterID:     clusterID,
+		a:             a,
+		informers:     informers,
+		watchAllKinds: watchAllKinds,
 		convertor: rest.NewDefaultTableConvertor(schema.GroupResource{
 			Group:    rscoreapi.GroupName,
 			Resource: rscoreapi.ResourceGenericResources,
@@ -112,13 +112,11 @@ func (r *Storage) Get(ctx context.Context, name string, options *metav1.GetOptio
 		return nil, apierrors.NewInternalError(err)
 	}
 
-	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(r.dc))
-
 	objName, gk, err := rscoreapi.ParseGenericResourceName(name)
 	if err != nil {
 		return nil, apierrors.NewBadRequest(err.Error())
 	}
-	mapping, err := mapper.RESTMapping(gk)
+	mapping, err := r.mapper.RESTMapping(gk)
 	if err != nil {
 		return nil, apierrors.NewInternalError(err)
 	}
@@ -172,97 +170,72 @@ func (r *Storage) List(ctx context.Context, options *internalversion.ListOptions
 			ns = result.Namespace.Name
 		}
 	}
+	if options == nil {
+		options = &internalversion.ListOptions{}
+	}
 
-	selector := shared.NewGroupKindSelector(options.LabelSelector)
-	cmeta, err := clustermeta.ClusterMetadata(r.kc)
+	q, err := newQuery(user, ns, options.FieldSelector)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	result := rscoreapi.GenericResourceList{
+		TypeMeta: metav1.TypeMeta{},
+		ListMeta: metav1.ListMeta{},
+		Items:    make([]rscoreapi.GenericResource, 0),
+	}
+	if q.empty {
+		return &result, nil
+	}
+
+	q.cmeta, err = clustermeta.ClusterMetadata(r.kc)
 	if err != nil {
 		return nil, apierrors.NewInternalError(err)
 	}
 
-	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(r.dc))
-	gvks := make(map[schema.GroupKind]string)
-	for _, gvk := range api.RegisteredTypes() {
-		if !selector.Matches(gvk.GroupKind()) {
-			continue
-		}
-		gk := gvk.GroupKind()
-		if v, exists := gvks[gk]; !exists || apiversion.MustCompare(v, gvk.Version) < 0 {
-			gvks[gk] = gvk.Version
-		}
+	mappings, err := shared.RegisteredMappings(r.mapper, shared.NewGroupKindSelector(options.LabelSelector))
+	if err != nil {
+		return nil, err
 	}
+	mappings = q.filter(mappings)
 
-	items := make([]rscoreapi.GenericResource, 0)
-	for gk, v := range gvks {
-		mapping, err := mapper.RESTMapping(gk, v)
-		if meta.IsNoMatchError(err) {
-			continue
-		} else if err != nil {
+	if options.Limit <= 0 {
+		result.Items, err = r.listAll(ctx, q, mappings)
+		if err != nil {
 			return nil, err
 		}
-		apiType := kmapi.NewResourceID(mapping)
+		return &result, nil
+	}
 
-		attrs := authorizer.AttributesRecord{
-			User:            user,
-			Verb:            "get",
-			Namespace:       ns,
-			APIGroup:        mapping.Resource.Group,
-			Resource:        mapping.Resource.Resource,
-			Name:            "",
-			ResourceRequest: true,
+	// Pages are filled from the kinds in order, resuming the list of a kind with the continue
+	// token returned by the api server. The continue token of the result records the position.
+	start, cont := 0, ""
+	if options.Continue != "" {
+		tok, err := shared.DecodeContinueToken(options.Continue)
+		if err != nil {
+			return nil, apierrors.NewBadRequest("invalid continue token")
 		}
-
-		var list unstructured.UnstructuredList
-		list.SetGroupVersionKind(schema.GroupVersionKind{
-			Group:   gk.Group,
-			Version: v,
-			Kind:    gk.Kind,
-		})
-		if err := r.kc.List(ctx, &list, client.InNamespace(ns)); err != nil {
+		start, cont = tok.ResumeIndex(mappings)
+	}
+	i := start
+	for i < len(mappings) && int64(len(result.Items)) < options.Limit {
+		items, next, err := r.listKind(ctx, q, mappings[i], options.Limit-int64(len(result.Items)), cont)
+		if err != nil {
 			return nil, err
 		}
-		for _, item := range list.Items {
-			attrs.Name = item.GetName()
-			attrs.Namespace = item.GetNamespace()
-			decision, _, err := r.a.Authorize(ctx, attrs)
-			if err != nil {
-				return nil, apierrors.NewInternalError(err)
-			}
-			if decision != authorizer.DecisionAllow {
-				continue
-			}
-
-			genres, err := rscoreapi.ToGenericResource(&item, apiType, cmeta)
-			if err != nil {
-				return nil, err
-			}
-			items = append(items, *genres)
+		result.Items = append(result.Items, items...)
+		if next != "" {
+			cont = next
+			continue
 		}
+		i++
+		cont = ""
 	}
-	sort.Slice(items, func(i, j int) bool {
-		gvk_i := items[i].GetObjectKind().GroupVersionKind()
-		gvk_j := items[j].GetObjectKind().GroupVersionKind()
-		if gvk_i.Group != gvk_j.Group {
-			return gvk_i.Group < gvk_j.Group
-		}
-		if gvk_i.Version != gvk_j.Version {
-			diff, _ := apiversion.Compare(gvk_i.Version, gvk_j.Version)
-			return diff < 0
-		}
-		if gvk_i.Kind != gvk_j.Kind {
-			return gvk_i.Kind < gvk_j.Kind
-		}
-		if items[i].Namespace != items[j].Namespace {
-			return items[i].Namespace < items[j].Namespace
-		}
-		return items[i].Name < items[j].Name
-	})
-
-	result := rscoreapi.GenericResourceList{
-		TypeMeta: metav1.TypeMeta{},
-		ListMeta: metav1.ListMeta{},
-		Items:    items,
+	if i < len(mappings) {
+		result.Continue = shared.ContinueToken{
+			GroupKind: mappings[i].GroupVersionKind.GroupKind().String(),
+			Continue:  cont,
+		}.Encode()
 	}
-
 	return &result, nil
 }
 
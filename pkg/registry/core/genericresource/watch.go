@@ -0,0 +1,130 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package genericresource
+
+import (
+	"context"
+
+	"kubeops.dev/ui-server/pkg/shared"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+	kmapi "kmodules.xyz/client-go/api/v1"
+	clustermeta "kmodules.xyz/client-go/cluster"
+	rscoreapi "kmodules.xyz/resource-metadata/apis/core/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Watch streams GenericResources for the events of the manager's informers of every kind
+// matched by the group-kind selector. Each event is checked against the caller's
+// permission to get the underlying object, and filtered by the field selector as in List.
+// A watch without a selector is only served with --watch-all-kinds, see
+// shared.WatchedKinds.
+func (r *Storage) Watch(ctx context.Context, options *internalversion.ListOptions) (watch.Interface, error) {
+	user, ok := apirequest.UserFrom(ctx)
+	if !ok {
+		return nil, apierrors.NewBadRequest("missing user info")
+	}
+
+	ns, ok := apirequest.NamespaceFrom(ctx)
+	if !ok {
+		return nil, apierrors.NewBadRequest("missing namespace")
+	}
+	// for client org user, show their own namespace only when all namespace objects is requested
+	if ns == "" {
+		result, err := clustermeta.IsClientOrgMember(r.kc, user)
+		if err != nil {
+			return nil, err
+		}
+
+		if result.IsClientOrg {
+			ns = result.Namespace.Name
+		}
+	}
+
+	q, err := newQuery(user, ns, options.FieldSelector)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	if q.empty {
+		return watch.NewEmptyWatch(), nil
+	}
+
+	// a metadata.name field selector names the kind, so a single informer is watched
+	selector := shared.NewGroupKindSelector(options.LabelSelector)
+	kinds, err := shared.WatchedKinds(selector, r.watchAllKinds || q.gk != nil)
+	if err != nil {
+		return nil, err
+	}
+	q.cmeta, err = clustermeta.ClusterMetadata(r.kc)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+
+	// The Watch context lasts until the client goes away, which also stops the watcher.
+	w := shared.NewInformerWatcher(ctx, options.ResourceVersion)
+	for gk, v := range kinds {
+		if q.gk != nil && gk != *q.gk {
+			continue
+		}
+		mapping, err := r.mapper.RESTMapping(gk, v)
+		if meta.IsNoMatchError(err) {
+			continue
+		} else if err != nil {
+			w.Stop()
+			return nil, err
+		}
+		rid := kmapi.NewResourceID(mapping)
+		gr := mapping.Resource.GroupResource()
+		gvk := mapping.GroupVersionKind
+
+		inf, err := shared.GetInformer(ctx, r.informers, r.kc.Scheme(), gvk)
+		if err != nil {
+			w.Stop()
+			return nil, err
+		}
+		err = w.AddInformer(inf, func(_ watch.EventType, obj client.Object) runtime.Object {
+			if (q.ns != "" && obj.GetNamespace() != q.ns) || (q.name != "" && obj.GetName() != q.name) || !shared.CanGet(ctx, r.a, user, gr, obj) {
+				return nil
+			}
+			item, err := shared.AsUnstructured(obj, gvk)
+			if err != nil {
+				klog.ErrorS(err, "failed to convert watch event", "resource", gr, "namespace", obj.GetNamespace(), "name", obj.GetName())
+				return nil
+			}
+			genres, err := rscoreapi.ToGenericResource(item, rid, q.cmeta)
+			if err != nil {
+				klog.ErrorS(err, "failed to convert watch event", "resource", gr, "namespace", item.GetNamespace(), "name", item.GetName())
+				return nil
+			}
+			if !q.fields.Matches(genericResourceFields(genres)) {
+				return nil
+			}
+			return genres
+		})
+		if err != nil {
+			w.Stop()
+			return nil, err
+		}
+	}
+	return w, nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podview
+
+import (
+	uicoreapi "kubeops.dev/ui-server/apis/core/v1alpha1"
+
+	core "k8s.io/api/core/v1"
+)
+
+const reasonOOMKilled = "OOMKilled"
+
+// containerStatuses returns the status of every container of the pod, in the order used
+// for the ContainerViews: containers, init containers and ephemeral containers.
+func containerStatuses(pod *core.Pod) []uicoreapi.ContainerViewStatus {
+	statuses := make([]uicoreapi.ContainerViewStatus, 0, len(pod.Spec.Containers)+len(pod.Spec.InitContainers)+len(pod.Spec.EphemeralContainers))
+	for _, c := range pod.Spec.Containers {
+		statuses = append(statuses, containerStatus(c.Name, uicoreapi.ContainerTypeContainer, pod.Status.ContainerStatuses))
+	}
+	for _, c := range pod.Spec.InitContainers {
+		t := uicoreapi.ContainerTypeInit
+		if c.RestartPolicy != nil && *c.RestartPolicy == core.ContainerRestartPolicyAlways {
+			t = uicoreapi.ContainerTypeSidecar
+		}
+		statuses = append(statuses, containerStatus(c.Name, t, pod.Status.InitContainerStatuses))
+	}
+	for _, c := range pod.Spec.EphemeralContainers {
+		statuses = append(statuses, containerStatus(c.Name, uicoreapi.ContainerTypeEphemeral, pod.Status.EphemeralContainerStatuses))
+	}
+	return statuses
+}
+
+func containerStatus(name string, t uicoreapi.ContainerType, statuses []core.ContainerStatus) uicoreapi.ContainerViewStatus {
+	result := uicoreapi.ContainerViewStatus{
+		Name: name,
+		Type: t,
+	}
+	var cs *core.ContainerStatus
+	for i := range statuses {
+		if statuses[i].Name == name {
+			cs = &statuses[i]
+			break
+		}
+	}
+	if cs == nil {
+		return result
+	}
+
+	result.Ready = cs.Ready
+	result.RestartCount = cs.RestartCount
+	switch {
+	case cs.State.Waiting != nil:
+		result.State = "Waiting"
+		result.Reason = cs.State.Waiting.Reason
+	case cs.State.Running != nil:
+		result.State = "Running"
+	case cs.State.Terminated != nil:
+		result.State = "Terminated"
+		result.Reason = cs.State.Terminated.Reason
+	}
+	if last := cs.LastTerminationState.Terminated; last != nil {
+		result.LastTermination = &uicoreapi.ContainerTermination{
+			Reason:     last.Reason,
+			ExitCode:   last.ExitCode,
+			FinishedAt: last.FinishedAt,
+		}
+		result.OOMKilled = last.Reason == reasonOOMKilled
+	}
+	if cs.State.Terminated != nil && cs.State.Terminated.Reason == reasonOOMKilled {
+		result.OOMKilled = true
+	}
+	return result
+}
@@ -0,0 +1,158 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podview
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	uicoreapi "kubeops.dev/ui-server/apis/core/v1alpha1"
+
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestContainerStatuses(t *testing.T) {
+	always := core.ContainerRestartPolicyAlways
+	pod := &core.Pod{
+		Spec: core.PodSpec{
+			Containers: []core.Container{{Name: "app"}, {Name: "pending"}},
+			InitContainers: []core.Container{
+				{Name: "migrate"},
+				{Name: "proxy", RestartPolicy: &always},
+			},
+			EphemeralContainers: []core.EphemeralContainer{
+				{EphemeralContainerCommon: core.EphemeralContainerCommon{Name: "debugger"}},
+			},
+		},
+		Status: core.PodStatus{
+			ContainerStatuses: []core.ContainerStatus{
+				{
+					Name:         "app",
+					Ready:        true,
+					RestartCount: 3,
+					State:        core.ContainerState{Running: &core.ContainerStateRunning{}},
+					LastTerminationState: core.ContainerState{Terminated: &core.ContainerStateTerminated{
+						Reason:   "OOMKilled",
+						ExitCode: 137,
+					}},
+				},
+			},
+			InitContainerStatuses: []core.ContainerStatus{
+				{
+					Name:  "migrate",
+					State: core.ContainerState{Terminated: &core.ContainerStateTerminated{Reason: "Completed"}},
+				},
+				{
+					Name:    "proxy",
+					Ready:   true,
+					Started: ptr.To(true),
+					State:   core.ContainerState{Running: &core.ContainerStateRunning{}},
+				},
+			},
+			EphemeralContainerStatuses: []core.ContainerStatus{
+				{
+					Name:  "debugger",
+					State: core.ContainerState{Waiting: &core.ContainerStateWaiting{Reason: "ImagePullBackOff"}},
+				},
+			},
+		},
+	}
+
+	got := containerStatuses(pod)
+	want := []uicoreapi.ContainerViewStatus{
+		{
+			Name:            "app",
+			Type:            uicoreapi.ContainerTypeContainer,
+			Ready:           true,
+			State:           "Running",
+			RestartCount:    3,
+			LastTermination: &uicoreapi.ContainerTermination{Reason: "OOMKilled", ExitCode: 137},
+			OOMKilled:       true,
+		},
+		{Name: "pending", Type: uicoreapi.ContainerTypeContainer},
+		{Name: "migrate", Type: uicoreapi.ContainerTypeInit, State: "Terminated", Reason: "Completed"},
+		{Name: "proxy", Type: uicoreapi.ContainerTypeSidecar, Ready: true, State: "Running"},
+		{Name: "debugger", Type: uicoreapi.ContainerTypeEphemeral, State: "Waiting", Reason: "ImagePullBackOff"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d statuses, found %d", len(want), len(got))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("status %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestWarningEvents(t *testing.T) {
+	now := time.Now()
+	at := func(d time.Duration) metav1.Time {
+		return metav1.NewTime(now.Add(-d).Truncate(time.Second))
+	}
+	events := []core.Event{
+		{
+			Type:           core.EventTypeWarning,
+			Reason:         "BackOff",
+			InvolvedObject: core.ObjectReference{UID: "pod-1"},
+			Count:          5,
+			Source:         core.EventSource{Component: "kubelet"},
+			FirstTimestamp: at(time.Hour),
+			LastTimestamp:  at(time.Minute),
+		},
+		{
+			Type:           core.EventTypeNormal,
+			Reason:         "Pulled",
+			InvolvedObject: core.ObjectReference{UID: "pod-1"},
+			LastTimestamp:  at(0),
+		},
+		{
+			Type:           core.EventTypeWarning,
+			Reason:         "FailedMount",
+			InvolvedObject: core.ObjectReference{UID: "pod-0"},
+			LastTimestamp:  at(0),
+		},
+		{
+			Type:                core.EventTypeWarning,
+			Reason:              "Unhealthy",
+			InvolvedObject:      core.ObjectReference{UID: "pod-1"},
+			ReportingController: "kubelet",
+			EventTime:           metav1.NewMicroTime(at(10 * time.Minute).Time),
+			Series: &core.EventSeries{
+				Count:            7,
+				LastObservedTime: metav1.NewMicroTime(at(10 * time.Second).Time),
+			},
+		},
+	}
+
+	got := warningEvents(events, "pod-1", 10)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 events, found %+v", got)
+	}
+	if got[0].Reason != "Unhealthy" || got[0].Count != 7 || got[0].Source != "kubelet" || !got[0].FirstTimestamp.Equal(ptr.To(at(10*time.Minute))) {
+		t.Errorf("unexpected first event %+v", got[0])
+	}
+	if got[1].Reason != "BackOff" || got[1].Count != 5 {
+		t.Errorf("unexpected second event %+v", got[1])
+	}
+
+	if got := warningEvents(events, "pod-1", 1); len(got) != 1 || got[0].Reason != "Unhealthy" {
+		t.Errorf("expected only the latest event, found %+v", got)
+	}
+}
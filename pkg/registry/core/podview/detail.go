@@ -0,0 +1,142 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podview
+
+import (
+	"context"
+	"strings"
+
+	uicoreapi "kubeops.dev/ui-server/apis/core/v1alpha1"
+
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/klog/v2"
+)
+
+// DetailStorage serves the PodDetails of pods. It shares the authorization and the
+// conversion of the PodView storage.
+type DetailStorage struct {
+	r         *Storage
+	convertor rest.TableConvertor
+}
+
+var (
+	_ rest.GroupVersionKindProvider = &DetailStorage{}
+	_ rest.Scoper                   = &DetailStorage{}
+	_ rest.Storage                  = &DetailStorage{}
+	_ rest.Getter                   = &DetailStorage{}
+	_ rest.SingularNameProvider     = &DetailStorage{}
+)
+
+func NewDetailStorage(r *Storage) *DetailStorage {
+	return &DetailStorage{
+		r: r,
+		convertor: rest.NewDefaultTableConvertor(schema.GroupResource{
+			Group:    uicoreapi.GroupName,
+			Resource: uicoreapi.ResourcePodDetails,
+		}),
+	}
+}
+
+func (d *DetailStorage) GroupVersionKind(_ schema.GroupVersion) schema.GroupVersionKind {
+	return uicoreapi.SchemeGroupVersion.WithKind(uicoreapi.ResourceKindPodDetail)
+}
+
+func (d *DetailStorage) NamespaceScoped() bool {
+	return true
+}
+
+func (d *DetailStorage) GetSingularName() string {
+	return strings.ToLower(uicoreapi.ResourceKindPodDetail)
+}
+
+func (d *DetailStorage) New() runtime.Object {
+	return &uicoreapi.PodDetail{}
+}
+
+func (d *DetailStorage) Destroy() {}
+
+func (d *DetailStorage) Get(ctx context.Context, name string, _ *metav1.GetOptions) (runtime.Object, error) {
+	pod, err := d.r.getPod(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	nsUsage := collectUsage(ctx, d.r.prometheusClient(), []string{pod.Namespace}, name)[pod.Namespace]
+	view := d.r.toPodView(ctx, pod, nsUsage)
+
+	result := uicoreapi.PodDetail{
+		ObjectMeta: view.ObjectMeta,
+		Spec: uicoreapi.PodDetailSpec{
+			PodViewSpec: view.Spec,
+		},
+		Status: uicoreapi.PodDetailStatus{
+			PodStatus:      view.Status,
+			ContainerViews: containerStatuses(pod),
+		},
+	}
+	for _, c := range pod.Spec.InitContainers {
+		result.Spec.InitContainers = append(result.Spec.InitContainers, toContainerView(c, nsUsage.containerUsage(pod.Name, c.Name)))
+	}
+	for _, c := range pod.Spec.EphemeralContainers {
+		result.Spec.EphemeralContainers = append(result.Spec.EphemeralContainers, toContainerView(core.Container(c.EphemeralContainerCommon), nsUsage.containerUsage(pod.Name, c.Name)))
+	}
+
+	// events are informative, so the detail is still returned without them, also if the
+	// caller may not list the events of the namespace
+	allowed, err := d.canListEvents(ctx, pod.Namespace)
+	if err != nil {
+		klog.ErrorS(err, "failed to authorize pod events", "namespace", pod.Namespace, "name", name)
+	}
+	if allowed {
+		if events, err := recentEvents(ctx, d.r.clientset, pod); err != nil {
+			klog.ErrorS(err, "failed to list pod events", "namespace", pod.Namespace, "name", name)
+		} else {
+			result.Status.RecentEvents = events
+		}
+	}
+	return &result, nil
+}
+
+// canListEvents reports whether the caller may list the events of ns, as they are listed
+// with the server's credentials.
+func (d *DetailStorage) canListEvents(ctx context.Context, ns string) (bool, error) {
+	u, ok := apirequest.UserFrom(ctx)
+	if !ok {
+		return false, nil
+	}
+	decision, _, err := d.r.a.Authorize(ctx, authorizer.AttributesRecord{
+		User:            u,
+		Verb:            "list",
+		Namespace:       ns,
+		APIGroup:        core.GroupName,
+		Resource:        "events",
+		ResourceRequest: true,
+	})
+	if err != nil {
+		return false, err
+	}
+	return decision == authorizer.DecisionAllow, nil
+}
+
+func (d *DetailStorage) ConvertToTable(ctx context.Context, object runtime.Object, tableOptions runtime.Object) (*metav1.Table, error) {
+	return d.convertor.ConvertToTable(ctx, object, tableOptions)
+}
@@ -0,0 +1,131 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podview
+
+import (
+	"context"
+	"testing"
+
+	uicoreapi "kubeops.dev/ui-server/apis/core/v1alpha1"
+
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	kfake "k8s.io/client-go/kubernetes/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	promclient "kmodules.xyz/monitoring-agent-api/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type allowAll struct{}
+
+func (allowAll) Authorize(_ context.Context, _ authorizer.Attributes) (authorizer.Decision, string, error) {
+	return authorizer.DecisionAllow, "", nil
+}
+
+// denyEvents allows everything but the events.
+type denyEvents struct{}
+
+func (denyEvents) Authorize(_ context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+	if a.GetResource() == "events" {
+		return authorizer.DecisionDeny, "", nil
+	}
+	return authorizer.DecisionAllow, "", nil
+}
+
+func TestDetailStorageGet(t *testing.T) {
+	pod := &core.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "demo", UID: "web-0-uid"},
+		Spec: core.PodSpec{
+			Containers:     []core.Container{{Name: "app"}},
+			InitContainers: []core.Container{{Name: "migrate"}},
+			EphemeralContainers: []core.EphemeralContainer{
+				{EphemeralContainerCommon: core.EphemeralContainerCommon{Name: "debugger"}},
+			},
+		},
+	}
+	event := &core.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "web-0.oom", Namespace: "demo"},
+		InvolvedObject: core.ObjectReference{Kind: "Pod", Namespace: "demo", Name: "web-0", UID: pod.UID},
+		Type:           core.EventTypeWarning,
+		Reason:         "BackOff",
+	}
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	kc := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pod).Build()
+	r := NewStorage(kc, kfake.NewSimpleClientset(event), allowAll{}, &promclient.ClientBuilder{}, nil)
+
+	ctx := apirequest.WithNamespace(context.Background(), "demo")
+	ctx = apirequest.WithUser(ctx, &user.DefaultInfo{Name: "alice"})
+	obj, err := NewDetailStorage(r).Get(ctx, "web-0", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	detail := obj.(*uicoreapi.PodDetail)
+	if len(detail.Spec.Containers) != 1 || detail.Spec.Containers[0].Name != "app" {
+		t.Errorf("unexpected containers %+v", detail.Spec.Containers)
+	}
+	if len(detail.Spec.InitContainers) != 1 || len(detail.Spec.EphemeralContainers) != 1 {
+		t.Errorf("unexpected init containers %+v and ephemeral containers %+v", detail.Spec.InitContainers, detail.Spec.EphemeralContainers)
+	}
+	if len(detail.Status.ContainerViews) != 3 || detail.Status.ContainerViews[2].Type != uicoreapi.ContainerTypeEphemeral {
+		t.Errorf("unexpected container views %+v", detail.Status.ContainerViews)
+	}
+	if len(detail.Status.RecentEvents) != 1 || detail.Status.RecentEvents[0].Reason != "BackOff" {
+		t.Errorf("unexpected events %+v", detail.Status.RecentEvents)
+	}
+
+	view, err := r.Get(ctx, "web-0", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if annotations := view.(metav1.Object).GetAnnotations(); len(annotations) != 0 {
+		t.Errorf("unexpected PodView annotations %v", annotations)
+	}
+}
+
+func TestDetailStorageGetEventsForbidden(t *testing.T) {
+	pod := &core.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "demo", UID: "web-0-uid"},
+		Spec:       core.PodSpec{Containers: []core.Container{{Name: "app"}}},
+	}
+	event := &core.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "web-0.oom", Namespace: "demo"},
+		InvolvedObject: core.ObjectReference{Kind: "Pod", Namespace: "demo", Name: "web-0", UID: pod.UID},
+		Type:           core.EventTypeWarning,
+		Reason:         "BackOff",
+	}
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	kc := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pod).Build()
+	r := NewStorage(kc, kfake.NewSimpleClientset(event), denyEvents{}, &promclient.ClientBuilder{}, nil)
+
+	ctx := apirequest.WithNamespace(context.Background(), "demo")
+	ctx = apirequest.WithUser(ctx, &user.DefaultInfo{Name: "alice"})
+	obj, err := NewDetailStorage(r).Get(ctx, "web-0", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if events := obj.(*uicoreapi.PodDetail).Status.RecentEvents; len(events) != 0 {
+		t.Errorf("expected no events, found %+v", events)
+	}
+}
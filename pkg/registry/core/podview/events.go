@@ -0,0 +1,105 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podview
+
+import (
+	"context"
+	"sort"
+
+	uicoreapi "kubeops.dev/ui-server/apis/core/v1alpha1"
+
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// maxRecentEvents is the number of warning Events returned with a PodView.
+const maxRecentEvents = 10
+
+// recentEvents returns the latest warning Events of the pod. Events are listed from the
+// api server with a field selector, since they are not kept in the manager's cache.
+func recentEvents(ctx context.Context, kc kubernetes.Interface, pod *core.Pod) ([]uicoreapi.PodViewEvent, error) {
+	sel := fields.Set{
+		"involvedObject.kind":      "Pod",
+		"involvedObject.name":      pod.Name,
+		"involvedObject.namespace": pod.Namespace,
+		"type":                     core.EventTypeWarning,
+	}.AsSelector().String()
+	list, err := kc.CoreV1().Events(pod.Namespace).List(ctx, metav1.ListOptions{FieldSelector: sel})
+	if err != nil {
+		return nil, err
+	}
+	return warningEvents(list.Items, pod.UID, maxRecentEvents), nil
+}
+
+// warningEvents returns up to limit warning Events of the pod with the given uid, newest first.
+func warningEvents(events []core.Event, uid types.UID, limit int) []uicoreapi.PodViewEvent {
+	result := make([]uicoreapi.PodViewEvent, 0, len(events))
+	for _, e := range events {
+		if e.Type != core.EventTypeWarning || (e.InvolvedObject.UID != "" && e.InvolvedObject.UID != uid) {
+			continue
+		}
+		first, last := eventTimes(e)
+		source := e.Source.Component
+		if source == "" {
+			source = e.ReportingController
+		}
+		count := e.Count
+		if e.Series != nil {
+			count = e.Series.Count
+		}
+		result = append(result, uicoreapi.PodViewEvent{
+			Type:           e.Type,
+			Reason:         e.Reason,
+			Message:        e.Message,
+			Count:          count,
+			Source:         source,
+			FirstTimestamp: first,
+			LastTimestamp:  last,
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[j].LastTimestamp.Before(&result[i].LastTimestamp)
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// eventTimes returns when an Event was first and last seen. Events created with the
+// events.k8s.io API only set eventTime and the series.
+func eventTimes(e core.Event) (metav1.Time, metav1.Time) {
+	first := e.FirstTimestamp
+	if first.IsZero() {
+		if !e.EventTime.IsZero() {
+			first = metav1.NewTime(e.EventTime.Time)
+		} else {
+			first = e.CreationTimestamp
+		}
+	}
+	last := e.LastTimestamp
+	if last.IsZero() && e.Series != nil {
+		last = metav1.NewTime(e.Series.LastObservedTime.Time)
+	}
+	if last.IsZero() {
+		last = first
+	}
+	return first, last
+}
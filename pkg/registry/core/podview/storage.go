@@ -28,22 +28,27 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 	apirequest "k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 	clustermeta "kmodules.xyz/client-go/cluster"
 	mu "kmodules.xyz/client-go/meta"
 	promclient "kmodules.xyz/monitoring-agent-api/client"
 	rscoreapi "kmodules.xyz/resource-metadata/apis/core/v1alpha1"
 	rmapi "kmodules.xyz/resource-metrics/api"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type Storage struct {
 	kc        client.Client
+	clientset kubernetes.Interface
 	a         authorizer.Authorizer
 	builder   *promclient.ClientBuilder
+	informers cache.Informers
 	gr        schema.GroupResource
 	convertor rest.TableConvertor
 }
@@ -54,14 +59,17 @@ var (
 	_ rest.Storage                  = &Storage{}
 	_ rest.Lister                   = &Storage{}
 	_ rest.Getter                   = &Storage{}
+	_ rest.Watcher                  = &Storage{}
 	_ rest.SingularNameProvider     = &Storage{}
 )
 
-func NewStorage(kc client.Client, a authorizer.Authorizer, builder *promclient.ClientBuilder) *Storage {
+func NewStorage(kc client.Client, clientset kubernetes.Interface, a authorizer.Authorizer, builder *promclient.ClientBuilder, informers cache.Informers) *Storage {
 	s := &Storage{
-		kc:      kc,
-		a:       a,
-		builder: builder,
+		kc:        kc,
+		clientset: clientset,
+		a:         a,
+		builder:   builder,
+		informers: informers,
 		gr: schema.GroupResource{
 			Group:    "",
 			Resource: "pods",
@@ -93,6 +101,16 @@ func (r *Storage) New() runtime.Object {
 func (r *Storage) Destroy() {}
 
 func (r *Storage) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	pod, err := r.getPod(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	usage := collectUsage(ctx, r.prometheusClient(), []string{pod.Namespace}, name)
+	return r.toPodView(ctx, pod, usage[pod.Namespace]), nil
+}
+
+// getPod returns the pod, if the user is allowed to get it.
+func (r *Storage) getPod(ctx context.Context, name string) (*core.Pod, error) {
 	ns, ok := apirequest.NamespaceFrom(ctx)
 	if !ok {
 		return nil, apierrors.NewBadRequest("missing namespace")
@@ -124,11 +142,25 @@ func (r *Storage) Get(ctx context.Context, name string, options *metav1.GetOptio
 	if err != nil {
 		return nil, err
 	}
+	return &pod, nil
+}
 
-	return r.toPodView(&pod), nil
+// prometheusClient returns nil when Prometheus is not configured, in which
+// case PodViews are returned without usage.
+func (r *Storage) prometheusClient() querier {
+	return prometheusClient(r.builder)
 }
 
-func (r *Storage) toPodView(pod *core.Pod) *rscoreapi.PodView {
+func prometheusClient(builder *promclient.ClientBuilder) querier {
+	pc, err := builder.GetPrometheusClient()
+	if err != nil {
+		klog.ErrorS(err, "failed to create Prometheus client")
+		return nil
+	}
+	return pc
+}
+
+func (r *Storage) toPodView(ctx context.Context, pod *core.Pod, nsUsage *namespaceUsage) *rscoreapi.PodView {
 	result := rscoreapi.PodView{
 		// TypeMeta:   metav1.TypeMeta{},
 		ObjectMeta: *pod.ObjectMeta.DeepCopy(),
@@ -148,56 +180,23 @@ func (r *Storage) toPodView(pod *core.Pod) *rscoreapi.PodView {
 	result.Finalizers = nil
 	delete(result.Annotations, mu.LastAppliedConfigAnnotation)
 
-	var limits, requests core.ResourceList
+	var limits, requests, usage core.ResourceList
 
 	result.Spec.Containers = make([]rscoreapi.ContainerView, 0, len(pod.Spec.Containers))
 	for _, c := range pod.Spec.Containers {
 		limits = rmapi.AddResourceList(limits, c.Resources.Limits)
 		requests = rmapi.AddResourceList(requests, c.Resources.Requests)
-
-		result.Spec.Containers = append(result.Spec.Containers, rscoreapi.ContainerView{
-			Name:       c.Name,
-			Image:      c.Image,
-			Command:    c.Command,
-			Args:       c.Args,
-			WorkingDir: c.WorkingDir,
-			Ports:      c.Ports,
-			EnvFrom:    c.EnvFrom,
-			Env:        c.Env,
-			Resources: rscoreapi.ResourceView{
-				Limits:   rscoreapi.ConvertToStringQuantity(c.Resources.Limits),
-				Requests: rscoreapi.ConvertToStringQuantity(c.Resources.Requests),
-				Usage:    nil,
-			},
-			VolumeMounts:             c.VolumeMounts,
-			VolumeDevices:            c.VolumeDevices,
-			LivenessProbe:            c.LivenessProbe,
-			ReadinessProbe:           c.ReadinessProbe,
-			StartupProbe:             c.StartupProbe,
-			Lifecycle:                c.Lifecycle,
-			TerminationMessagePath:   c.TerminationMessagePath,
-			TerminationMessagePolicy: c.TerminationMessagePolicy,
-			ImagePullPolicy:          c.ImagePullPolicy,
-			SecurityContext:          c.SecurityContext,
-			Stdin:                    c.Stdin,
-			StdinOnce:                c.StdinOnce,
-			TTY:                      c.TTY,
-		})
+		cu := nsUsage.containerUsage(pod.Name, c.Name)
+		if cu != nil {
+			usage = rmapi.AddResourceList(usage, cu)
+		}
+		result.Spec.Containers = append(result.Spec.Containers, toContainerView(c, cu))
 	}
 	for _, c := range pod.Spec.InitContainers {
 		limits = rmapi.MaxResourceList(limits, c.Resources.Limits)
 		requests = rmapi.MaxResourceList(requests, c.Resources.Requests)
 	}
 
-	pc, err := r.builder.GetPrometheusClient()
-	if err != nil {
-		klog.ErrorS(err, "failed to create Prometheus client")
-	}
-
-	var usage core.ResourceList
-	if pc != nil {
-		usage = promclient.GetPodResourceUsage(pc, pod.ObjectMeta)
-	}
 	{
 		// storage
 		storageReq := resource.Quantity{Format: resource.BinarySI}
@@ -205,12 +204,15 @@ func (r *Storage) toPodView(pod *core.Pod) *rscoreapi.PodView {
 		for _, vol := range pod.Spec.Volumes {
 			if vol.PersistentVolumeClaim != nil {
 				var pvc core.PersistentVolumeClaim
-				if err := r.kc.Get(context.TODO(), client.ObjectKey{Namespace: pod.Namespace, Name: vol.PersistentVolumeClaim.ClaimName}, &pvc); err == nil {
+				if err := r.kc.Get(ctx, client.ObjectKey{Namespace: pod.Namespace, Name: vol.PersistentVolumeClaim.ClaimName}, &pvc); err == nil {
 					storageReq.Add(*pvc.Spec.Resources.Requests.Storage())
 					storageCap.Add(*pvc.Status.Capacity.Storage())
-					if pc != nil {
+					if used, ok := nsUsage.pvcUsage(pvc.Name); ok {
+						if usage == nil {
+							usage = core.ResourceList{}
+						}
 						tmp := usage[core.ResourceStorage]
-						tmp.Add(promclient.GetPVCUsage(pc, pvc.ObjectMeta))
+						tmp.Add(used)
 						usage[core.ResourceStorage] = tmp
 					}
 				}
@@ -230,6 +232,37 @@ func (r *Storage) toPodView(pod *core.Pod) *rscoreapi.PodView {
 	return &result
 }
 
+func toContainerView(c core.Container, usage core.ResourceList) rscoreapi.ContainerView {
+	return rscoreapi.ContainerView{
+		Name:       c.Name,
+		Image:      c.Image,
+		Command:    c.Command,
+		Args:       c.Args,
+		WorkingDir: c.WorkingDir,
+		Ports:      c.Ports,
+		EnvFrom:    c.EnvFrom,
+		Env:        c.Env,
+		Resources: rscoreapi.ResourceView{
+			Limits:   rscoreapi.ConvertToStringQuantity(c.Resources.Limits),
+			Requests: rscoreapi.ConvertToStringQuantity(c.Resources.Requests),
+			Usage:    rscoreapi.ConvertToStringQuantity(usage),
+		},
+		VolumeMounts:             c.VolumeMounts,
+		VolumeDevices:            c.VolumeDevices,
+		LivenessProbe:            c.LivenessProbe,
+		ReadinessProbe:           c.ReadinessProbe,
+		StartupProbe:             c.StartupProbe,
+		Lifecycle:                c.Lifecycle,
+		TerminationMessagePath:   c.TerminationMessagePath,
+		TerminationMessagePolicy: c.TerminationMessagePolicy,
+		ImagePullPolicy:          c.ImagePullPolicy,
+		SecurityContext:          c.SecurityContext,
+		Stdin:                    c.Stdin,
+		StdinOnce:                c.StdinOnce,
+		TTY:                      c.TTY,
+	}
+}
+
 func (r *Storage) NewList() runtime.Object {
 	return &rscoreapi.PodViewList{}
 }
@@ -279,16 +312,19 @@ func (r *Storage) List(ctx context.Context, options *internalversion.ListOptions
 	}
 
 	var podList core.PodList
-	err := r.kc.List(context.TODO(), &podList, &opts)
+	err := r.kc.List(ctx, &podList, &opts)
 	if err != nil {
 		return nil, err
 	}
 
-	podviews := make([]rscoreapi.PodView, 0, len(podList.Items))
-	for _, pod := range podList.Items {
+	pods := make([]*core.Pod, 0, len(podList.Items))
+	var namespaces []string
+	seen := sets.New[string]()
+	for i := range podList.Items {
+		pod := &podList.Items[i]
 		attrs.Name = pod.Name
 		attrs.Namespace = pod.Namespace
-		decision, _, err := r.a.Authorize(context.TODO(), attrs)
+		decision, _, err := r.a.Authorize(ctx, attrs)
 		if err != nil {
 			return nil, apierrors.NewInternalError(err)
 		}
@@ -296,8 +332,23 @@ func (r *Storage) List(ctx context.Context, options *internalversion.ListOptions
 			continue
 		}
 
-		podView := r.toPodView(&pod)
-		podviews = append(podviews, *podView)
+		pods = append(pods, pod)
+		if !seen.Has(pod.Namespace) {
+			seen.Insert(pod.Namespace)
+			namespaces = append(namespaces, pod.Namespace)
+		}
+	}
+
+	// Usage is fetched with a few queries per namespace and joined in memory,
+	// instead of querying Prometheus for each pod.
+	var usage map[string]*namespaceUsage
+	if len(pods) > 0 {
+		usage = collectUsage(ctx, r.prometheusClient(), namespaces, "")
+	}
+
+	podviews := make([]rscoreapi.PodView, 0, len(pods))
+	for _, pod := range pods {
+		podviews = append(podviews, *r.toPodView(ctx, pod, usage[pod.Namespace]))
 	}
 
 	result := rscoreapi.PodViewList{
@@ -0,0 +1,266 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podview
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"golang.org/x/sync/errgroup"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	promclient "kmodules.xyz/monitoring-agent-api/client"
+)
+
+const (
+	// usageTimeout bounds the time spent waiting on Prometheus for a single
+	// Get or List call. Pods whose usage is not available by then are
+	// returned without usage.
+	usageTimeout = 5 * time.Second
+	// usageWorkers is the number of namespaces queried in parallel.
+	usageWorkers = 4
+)
+
+// querier is the subset of promv1.API used to collect usage.
+type querier interface {
+	Query(ctx context.Context, query string, ts time.Time, opts ...promv1.Option) (model.Value, promv1.Warnings, error)
+}
+
+type containerKey struct {
+	pod       string
+	container string
+}
+
+// namespaceUsage holds the usage of every container and PVC in a namespace
+// as reported by Prometheus. A nil map means the corresponding query failed.
+type namespaceUsage struct {
+	cpu     map[containerKey]float64
+	memory  map[containerKey]float64
+	storage map[string]float64
+}
+
+// containerUsage returns the cpu and memory usage of a container, or nil if
+// Prometheus has no data for it.
+func (u *namespaceUsage) containerUsage(pod, container string) core.ResourceList {
+	if u == nil {
+		return nil
+	}
+	key := containerKey{pod: pod, container: container}
+	var rl core.ResourceList
+	if v, ok := u.cpu[key]; ok {
+		if rl == nil {
+			rl = core.ResourceList{}
+		}
+		rl[core.ResourceCPU] = cpuQuantity(v)
+	}
+	if v, ok := u.memory[key]; ok {
+		if rl == nil {
+			rl = core.ResourceList{}
+		}
+		rl[core.ResourceMemory] = bytesQuantity(v)
+	}
+	return rl
+}
+
+// pvcUsage returns the used bytes of a PVC and whether Prometheus reported it.
+func (u *namespaceUsage) pvcUsage(claim string) (resource.Quantity, bool) {
+	if u == nil {
+		return resource.Quantity{}, false
+	}
+	v, ok := u.storage[claim]
+	if !ok {
+		return resource.Quantity{}, false
+	}
+	return bytesQuantity(v), true
+}
+
+// collectUsage queries the usage of the pods in the given namespaces. When pod
+// is not empty, the container queries are restricted to that pod. It issues
+// three queries per namespace and never returns an error: namespaces whose
+// queries fail or time out are logged and left without usage.
+func collectUsage(ctx context.Context, pc querier, namespaces []string, pod string) map[string]*namespaceUsage {
+	result := make(map[string]*namespaceUsage, len(namespaces))
+	if pc == nil || len(namespaces) == 0 {
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, usageTimeout)
+	defer cancel()
+
+	usages := make([]*namespaceUsage, len(namespaces))
+	var g errgroup.Group
+	g.SetLimit(usageWorkers)
+	for i, ns := range namespaces {
+		g.Go(func() error {
+			usages[i] = queryNamespaceUsage(ctx, pc, ns, pod)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	for i, ns := range namespaces {
+		result[ns] = usages[i]
+	}
+	return result
+}
+
+// Usage returns the cpu and memory used by the containers of pods and the storage used by
+// claims, as reported by Prometheus. It returns nil when Prometheus is not configured.
+func Usage(ctx context.Context, builder *promclient.ClientBuilder, pods []core.Pod, claims []core.PersistentVolumeClaim) core.ResourceList {
+	namespaces := sets.New[string]()
+	for _, pod := range pods {
+		namespaces.Insert(pod.Namespace)
+	}
+	for _, pvc := range claims {
+		namespaces.Insert(pvc.Namespace)
+	}
+	if namespaces.Len() == 0 {
+		return nil
+	}
+	pc := prometheusClient(builder)
+	if pc == nil {
+		return nil
+	}
+	return totalUsage(collectUsage(ctx, pc, sets.List(namespaces), ""), pods, claims)
+}
+
+// totalUsage sums the usage of the containers of pods and of claims. Resources that
+// Prometheus reported for none of them are left out.
+func totalUsage(usage map[string]*namespaceUsage, pods []core.Pod, claims []core.PersistentVolumeClaim) core.ResourceList {
+	var cpu, memory, storage float64
+	var hasCPU, hasMemory, hasStorage bool
+	for _, pod := range pods {
+		u := usage[pod.Namespace]
+		if u == nil {
+			continue
+		}
+		for key, v := range u.cpu {
+			if key.pod == pod.Name {
+				cpu += v
+				hasCPU = true
+			}
+		}
+		for key, v := range u.memory {
+			if key.pod == pod.Name {
+				memory += v
+				hasMemory = true
+			}
+		}
+	}
+	for _, pvc := range claims {
+		u := usage[pvc.Namespace]
+		if u == nil {
+			continue
+		}
+		if v, ok := u.storage[pvc.Name]; ok {
+			storage += v
+			hasStorage = true
+		}
+	}
+
+	rl := core.ResourceList{}
+	if hasCPU {
+		rl[core.ResourceCPU] = cpuQuantity(cpu)
+	}
+	if hasMemory {
+		rl[core.ResourceMemory] = bytesQuantity(memory)
+	}
+	if hasStorage {
+		rl[core.ResourceStorage] = bytesQuantity(storage)
+	}
+	return rl
+}
+
+func queryNamespaceUsage(ctx context.Context, pc querier, ns, pod string) *namespaceUsage {
+	sel := fmt.Sprintf(`namespace=%q`, ns)
+	if pod != "" {
+		sel += fmt.Sprintf(`,pod=%q`, pod)
+	}
+
+	cpuQuery := fmt.Sprintf(`sum by (pod, container) (irate(container_cpu_usage_seconds_total{%s,container!="",image!="",metrics_path="/metrics/cadvisor"}[5m]))`, sel)
+	memoryQuery := fmt.Sprintf(`sum by (pod, container) (container_memory_working_set_bytes{%s,container!="",image!=""})`, sel)
+	storageQuery := fmt.Sprintf(`sum by (persistentvolumeclaim) (kubelet_volume_stats_used_bytes{namespace=%q})`, ns)
+
+	now := time.Now()
+	var u namespaceUsage
+	if v, err := queryVector(ctx, pc, cpuQuery, now); err != nil {
+		klog.ErrorS(err, "failed to query container cpu usage", "namespace", ns)
+	} else {
+		u.cpu = containerValues(v)
+	}
+	if v, err := queryVector(ctx, pc, memoryQuery, now); err != nil {
+		klog.ErrorS(err, "failed to query container memory usage", "namespace", ns)
+	} else {
+		u.memory = containerValues(v)
+	}
+	if v, err := queryVector(ctx, pc, storageQuery, now); err != nil {
+		klog.ErrorS(err, "failed to query pvc usage", "namespace", ns)
+	} else {
+		u.storage = make(map[string]float64, len(v))
+		for _, s := range v {
+			if f := float64(s.Value); !math.IsNaN(f) && !math.IsInf(f, 0) {
+				u.storage[string(s.Metric["persistentvolumeclaim"])] += f
+			}
+		}
+	}
+	return &u
+}
+
+func queryVector(ctx context.Context, pc querier, query string, ts time.Time) (model.Vector, error) {
+	val, warn, err := pc.Query(ctx, query, ts)
+	if err != nil {
+		return nil, err
+	}
+	if len(warn) > 0 {
+		klog.V(3).InfoS("prometheus returned warnings", "query", query, "warnings", warn)
+	}
+	v, ok := val.(model.Vector)
+	if !ok {
+		return nil, fmt.Errorf("unexpected result type %s for query %s", val.Type(), query)
+	}
+	return v, nil
+}
+
+func containerValues(v model.Vector) map[containerKey]float64 {
+	out := make(map[containerKey]float64, len(v))
+	for _, s := range v {
+		f := float64(s.Value)
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			continue
+		}
+		key := containerKey{
+			pod:       string(s.Metric["pod"]),
+			container: string(s.Metric["container"]),
+		}
+		out[key] += f
+	}
+	return out
+}
+
+func cpuQuantity(cores float64) resource.Quantity {
+	return *resource.NewMilliQuantity(int64(math.Round(cores*1000)), resource.DecimalSI)
+}
+
+func bytesQuantity(b float64) resource.Quantity {
+	return *resource.NewQuantity(int64(math.Round(b)), resource.BinarySI)
+}
@@ -0,0 +1,171 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podview
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeQuerier struct {
+	mu      sync.Mutex
+	queries []string
+	result  func(query string) (model.Value, error)
+}
+
+func (f *fakeQuerier) Query(_ context.Context, query string, _ time.Time, _ ...promv1.Option) (model.Value, promv1.Warnings, error) {
+	f.mu.Lock()
+	f.queries = append(f.queries, query)
+	f.mu.Unlock()
+	v, err := f.result(query)
+	return v, nil, err
+}
+
+func sample(value float64, labels ...string) *model.Sample {
+	m := model.Metric{}
+	for i := 0; i+1 < len(labels); i += 2 {
+		m[model.LabelName(labels[i])] = model.LabelValue(labels[i+1])
+	}
+	return &model.Sample{Metric: m, Value: model.SampleValue(value)}
+}
+
+func TestCollectUsage(t *testing.T) {
+	pc := &fakeQuerier{
+		result: func(query string) (model.Value, error) {
+			switch {
+			case strings.Contains(query, `namespace="broken"`):
+				return nil, errors.New("connection refused")
+			case strings.Contains(query, "container_cpu_usage_seconds_total"):
+				return model.Vector{
+					sample(0.25, "pod", "web-0", "container", "app"),
+					sample(0.0104, "pod", "web-0", "container", "sidecar"),
+				}, nil
+			case strings.Contains(query, "container_memory_working_set_bytes"):
+				return model.Vector{
+					sample(64<<20, "pod", "web-0", "container", "app"),
+				}, nil
+			case strings.Contains(query, "kubelet_volume_stats_used_bytes"):
+				return model.Vector{
+					sample(1<<30, "persistentvolumeclaim", "data-web-0"),
+				}, nil
+			}
+			return model.Vector{}, nil
+		},
+	}
+
+	usage := collectUsage(context.Background(), pc, []string{"demo", "broken"}, "")
+	if got := len(pc.queries); got != 6 {
+		t.Fatalf("expected 3 queries per namespace, found %d", got)
+	}
+
+	demo := usage["demo"]
+	app := demo.containerUsage("web-0", "app")
+	if cpu := app[core.ResourceCPU]; cpu.String() != "250m" {
+		t.Errorf("app cpu = %s, want 250m", cpu.String())
+	}
+	if mem := app[core.ResourceMemory]; mem.String() != "64Mi" {
+		t.Errorf("app memory = %s, want 64Mi", mem.String())
+	}
+	sidecar := demo.containerUsage("web-0", "sidecar")
+	if cpu := sidecar[core.ResourceCPU]; cpu.String() != "10m" {
+		t.Errorf("sidecar cpu = %s, want 10m", cpu.String())
+	}
+	if _, ok := sidecar[core.ResourceMemory]; ok {
+		t.Errorf("sidecar memory should be missing")
+	}
+	if rl := demo.containerUsage("web-1", "app"); rl != nil {
+		t.Errorf("expected no usage for unknown pod, found %v", rl)
+	}
+	if q, ok := demo.pvcUsage("data-web-0"); !ok || q.String() != "1Gi" {
+		t.Errorf("pvc usage = %s, %v, want 1Gi", q.String(), ok)
+	}
+
+	broken := usage["broken"]
+	if rl := broken.containerUsage("web-0", "app"); rl != nil {
+		t.Errorf("expected no usage when queries fail, found %v", rl)
+	}
+	if _, ok := broken.pvcUsage("data-web-0"); ok {
+		t.Errorf("expected no pvc usage when queries fail")
+	}
+}
+
+func TestCollectUsageSinglePod(t *testing.T) {
+	pc := &fakeQuerier{
+		result: func(string) (model.Value, error) { return model.Vector{}, nil },
+	}
+	collectUsage(context.Background(), pc, []string{"demo"}, "web-0")
+	for _, q := range pc.queries {
+		if strings.Contains(q, "container_") && !strings.Contains(q, `pod="web-0"`) {
+			t.Errorf("container query is not restricted to the pod: %s", q)
+		}
+	}
+}
+
+func TestCollectUsageWithoutPrometheus(t *testing.T) {
+	usage := collectUsage(context.Background(), nil, []string{"demo"}, "")
+	if rl := usage["demo"].containerUsage("web-0", "app"); rl != nil {
+		t.Errorf("expected no usage without Prometheus, found %v", rl)
+	}
+}
+
+func TestTotalUsage(t *testing.T) {
+	usage := map[string]*namespaceUsage{
+		"demo": {
+			cpu: map[containerKey]float64{
+				{pod: "web-0", container: "app"}:     0.25,
+				{pod: "web-0", container: "sidecar"}: 0.05,
+				{pod: "other", container: "app"}:     1,
+			},
+			memory: map[containerKey]float64{
+				{pod: "web-0", container: "app"}: 64 << 20,
+			},
+			storage: map[string]float64{"data-web-0": 1 << 30},
+		},
+		"broken": {},
+	}
+	pods := []core.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "demo", Name: "web-0"}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "broken", Name: "web-0"}},
+	}
+	claims := []core.PersistentVolumeClaim{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "demo", Name: "data-web-0"}},
+	}
+
+	rl := totalUsage(usage, pods, claims)
+	if q := rl[core.ResourceCPU]; q.MilliValue() != 300 {
+		t.Errorf("cpu = %s, want 300m", q.String())
+	}
+	if q := rl[core.ResourceMemory]; q.Value() != 64<<20 {
+		t.Errorf("memory = %s, want 64Mi", q.String())
+	}
+	if q := rl[core.ResourceStorage]; q.Value() != 1<<30 {
+		t.Errorf("storage = %s, want 1Gi", q.String())
+	}
+
+	if rl := totalUsage(usage, pods[1:], nil); len(rl) != 0 {
+		t.Errorf("expected no usage when Prometheus has no data, found %v", rl)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podview
+
+import (
+	"context"
+
+	"kubeops.dev/ui-server/pkg/shared"
+
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	clustermeta "kmodules.xyz/client-go/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Watch streams PodViews for the pod events of the manager's informer. Each event is
+// checked against the caller's permission to get the pod. The PodViews of watch events
+// have no usage, as that would query Prometheus for every event; List reports it.
+func (r *Storage) Watch(ctx context.Context, options *internalversion.ListOptions) (watch.Interface, error) {
+	u, ok := apirequest.UserFrom(ctx)
+	if !ok {
+		return nil, apierrors.NewBadRequest("missing user info")
+	}
+
+	ns, ok := apirequest.NamespaceFrom(ctx)
+	if !ok {
+		return nil, apierrors.NewBadRequest("missing namespace")
+	}
+	// for client org user, show their own namespace only when all namespace objects is requested
+	if ns == "" {
+		result, err := clustermeta.IsClientOrgMember(r.kc, u)
+		if err != nil {
+			return nil, err
+		}
+
+		if result.IsClientOrg {
+			ns = result.Namespace.Name
+		}
+	}
+
+	inf, err := r.informers.GetInformer(ctx, &core.Pod{})
+	if err != nil {
+		return nil, err
+	}
+
+	var rv string
+	if options != nil {
+		rv = options.ResourceVersion
+	}
+	// The Watch context lasts until the client goes away, which also stops the watcher.
+	w := shared.NewInformerWatcher(ctx, rv)
+	err = w.AddInformer(inf, func(_ watch.EventType, obj client.Object) runtime.Object {
+		pod, ok := obj.(*core.Pod)
+		if !ok || !podMatches(pod, ns, options) || !shared.CanGet(ctx, r.a, u, r.gr, pod) {
+			return nil
+		}
+		return r.toPodView(ctx, pod, nil)
+	})
+	if err != nil {
+		w.Stop()
+		return nil, err
+	}
+	return w, nil
+}
+
+// podMatches applies the namespace, label and field selectors of a List to a pod.
+func podMatches(pod *core.Pod, ns string, options *internalversion.ListOptions) bool {
+	if ns != "" && pod.Namespace != ns {
+		return false
+	}
+	if options == nil {
+		return true
+	}
+	if options.LabelSelector != nil && !options.LabelSelector.Matches(labels.Set(pod.Labels)) {
+		return false
+	}
+	if options.FieldSelector != nil && !options.FieldSelector.Matches(podFields(pod)) {
+		return false
+	}
+	return true
+}
+
+func podFields(pod *core.Pod) fields.Set {
+	return fields.Set{
+		"metadata.name":      pod.Name,
+		"metadata.namespace": pod.Namespace,
+		"spec.nodeName":      pod.Spec.NodeName,
+		"status.phase":       string(pod.Status.Phase),
+	}
+}
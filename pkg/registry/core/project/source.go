@@ -0,0 +1,812 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	projectapi "kubeops.dev/ui-server/apis/management/v1alpha1"
+	projectquotacontroller "kubeops.dev/ui-server/pkg/controllers/projectquota"
+
+	"github.com/google/uuid"
+	"gomodules.xyz/sets"
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+	cu "kmodules.xyz/client-go/client"
+	clustermeta "kmodules.xyz/client-go/cluster"
+	rscoreapi "kmodules.xyz/resource-metadata/apis/core/v1alpha1"
+	mgmtapi "kmodules.xyz/resource-metadata/apis/management/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SourceType is where the projects of a cluster come from.
+type SourceType string
+
+const (
+	// SourceAuto uses the Rancher projects on Rancher managed clusters and the Label source otherwise.
+	SourceAuto SourceType = "Auto"
+	// SourceRancher groups namespaces by the field.cattle.io/projectId label. Rancher projects
+	// are read only.
+	SourceRancher SourceType = "Rancher"
+	// SourceLabel groups namespaces by the value of a label.
+	SourceLabel SourceType = "Label"
+	// SourceProjectQuota makes a project of every ProjectQuota. Its namespaces are the ones
+	// governed by the ProjectQuota and the ones labeled like the Label source.
+	SourceProjectQuota SourceType = "ProjectQuota"
+	// SourceCRD reads the projects from management.k8s.appscode.com Project objects.
+	SourceCRD SourceType = "CRD"
+)
+
+// DefaultLabelKey is the namespace label that names the project of the namespace.
+const DefaultLabelKey = "management.k8s.appscode.com/project"
+
+var SourceTypes = []SourceType{SourceAuto, SourceRancher, SourceLabel, SourceProjectQuota, SourceCRD}
+
+// Options configures the project source.
+type Options struct {
+	Source SourceType
+	// LabelKey is used by the Label and ProjectQuota sources.
+	LabelKey string
+}
+
+// Source lists and manages the projects of a cluster.
+type Source interface {
+	// Type is the type of the source. The Auto source returns the type it picks.
+	Type() SourceType
+	List(ctx context.Context) ([]rscoreapi.Project, error)
+	Create(ctx context.Context, in *rscoreapi.Project) (*rscoreapi.Project, error)
+	Update(ctx context.Context, in *rscoreapi.Project) (*rscoreapi.Project, error)
+	Delete(ctx context.Context, name string) error
+	// NamespaceProjects maps the namespaces that belong to a project to the project name.
+	NamespaceProjects(ctx context.Context) (map[string]string, error)
+}
+
+// NewSource returns the source configured by opts. The sources change the cluster with
+// the credentials of the server, so the authorizer checks that the caller may make the
+// same changes.
+func NewSource(kc client.Client, a authorizer.Authorizer, opts Options) Source {
+	if opts.LabelKey == "" {
+		opts.LabelKey = DefaultLabelKey
+	}
+	lbl := &labelSource{kc: kc, a: a, key: opts.LabelKey}
+	switch opts.Source {
+	case SourceRancher:
+		return &rancherSource{kc: kc}
+	case SourceLabel:
+		return lbl
+	case SourceProjectQuota:
+		return &projectQuotaSource{labelSource: lbl}
+	case SourceCRD:
+		return &crdSource{kc: kc, a: a}
+	default:
+		return &autoSource{kc: kc, rancher: &rancherSource{kc: kc}, label: lbl}
+	}
+}
+
+// GetProject returns the project with the given name.
+func GetProject(ctx context.Context, src Source, name string) (*rscoreapi.Project, error) {
+	projects, err := src.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, prj := range projects {
+		if prj.Name == name {
+			return &prj, nil
+		}
+	}
+	return nil, apierrors.NewNotFound(gr, name)
+}
+
+var (
+	namespaceGR    = core.Resource("namespaces")
+	projectQuotaGR = mgmtapi.SchemeGroupVersion.WithResource(mgmtapi.ResourceProjectQuotas).GroupResource()
+	projectGR      = projectapi.SchemeGroupVersion.WithResource(projectapi.ResourceProjects).GroupResource()
+)
+
+// authorize checks that the caller can perform verb on the named cluster scoped object.
+func authorize(ctx context.Context, a authorizer.Authorizer, verb string, gr schema.GroupResource, name string) error {
+	user, ok := apirequest.UserFrom(ctx)
+	if !ok {
+		return apierrors.NewBadRequest("missing user info")
+	}
+	attrs := authorizer.AttributesRecord{
+		User:            user,
+		Verb:            verb,
+		APIGroup:        gr.Group,
+		Resource:        gr.Resource,
+		Name:            name,
+		ResourceRequest: true,
+	}
+	decision, why, err := a.Authorize(ctx, attrs)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	if decision != authorizer.DecisionAllow {
+		return apierrors.NewForbidden(gr, name, errors.New(why))
+	}
+	return nil
+}
+
+type autoSource struct {
+	kc      client.Client
+	rancher Source
+	label   Source
+}
+
+var _ Source = &autoSource{}
+
+func (s *autoSource) pick() Source {
+	if clustermeta.IsRancherManaged(s.kc.RESTMapper()) {
+		return s.rancher
+	}
+	return s.label
+}
+
+func (s *autoSource) Type() SourceType {
+	return s.pick().Type()
+}
+
+func (s *autoSource) List(ctx context.Context) ([]rscoreapi.Project, error) {
+	return s.pick().List(ctx)
+}
+
+func (s *autoSource) Create(ctx context.Context, in *rscoreapi.Project) (*rscoreapi.Project, error) {
+	return s.pick().Create(ctx, in)
+}
+
+func (s *autoSource) Update(ctx context.Context, in *rscoreapi.Project) (*rscoreapi.Project, error) {
+	return s.pick().Update(ctx, in)
+}
+
+func (s *autoSource) Delete(ctx context.Context, name string) error {
+	return s.pick().Delete(ctx, name)
+}
+
+func (s *autoSource) NamespaceProjects(ctx context.Context) (map[string]string, error) {
+	return s.pick().NamespaceProjects(ctx)
+}
+
+type rancherSource struct {
+	kc client.Client
+}
+
+var _ Source = &rancherSource{}
+
+func (s *rancherSource) Type() SourceType {
+	return SourceRancher
+}
+
+func (s *rancherSource) List(_ context.Context) ([]rscoreapi.Project, error) {
+	return ListRancherProjects(s.kc)
+}
+
+func (s *rancherSource) Create(_ context.Context, _ *rscoreapi.Project) (*rscoreapi.Project, error) {
+	return nil, apierrors.NewMethodNotSupported(gr, "create")
+}
+
+func (s *rancherSource) Update(_ context.Context, _ *rscoreapi.Project) (*rscoreapi.Project, error) {
+	return nil, apierrors.NewMethodNotSupported(gr, "update")
+}
+
+func (s *rancherSource) Delete(_ context.Context, _ string) error {
+	return apierrors.NewMethodNotSupported(gr, "delete")
+}
+
+// NamespaceProjects puts the namespaces without a Rancher project in the fake project.
+func (s *rancherSource) NamespaceProjects(ctx context.Context) (map[string]string, error) {
+	var list core.NamespaceList
+	if err := s.kc.List(ctx, &list); err != nil {
+		return nil, err
+	}
+	result := make(map[string]string, len(list.Items))
+	for _, ns := range list.Items {
+		projectId, exists := ns.Labels[clustermeta.LabelKeyRancherFieldProjectId]
+		if !exists {
+			projectId = clustermeta.FakeRancherProjectId
+		}
+		result[ns.Name] = projectId
+	}
+	return result, nil
+}
+
+type labelSource struct {
+	kc  client.Client
+	a   authorizer.Authorizer
+	key string
+}
+
+var _ Source = &labelSource{}
+
+func (s *labelSource) Type() SourceType {
+	return SourceLabel
+}
+
+func (s *labelSource) NamespaceProjects(ctx context.Context) (map[string]string, error) {
+	var list core.NamespaceList
+	if err := s.kc.List(ctx, &list, client.HasLabels{s.key}); err != nil {
+		return nil, err
+	}
+	result := make(map[string]string, len(list.Items))
+	for _, ns := range list.Items {
+		if name := ns.Labels[s.key]; name != "" {
+			result[ns.Name] = name
+		}
+	}
+	return result, nil
+}
+
+func (s *labelSource) List(ctx context.Context) ([]rscoreapi.Project, error) {
+	var list core.NamespaceList
+	if err := s.kc.List(ctx, &list, client.HasLabels{s.key}); err != nil {
+		return nil, err
+	}
+	members := map[string][]core.Namespace{}
+	for _, ns := range list.Items {
+		if name := ns.Labels[s.key]; name != "" {
+			members[name] = append(members[name], ns)
+		}
+	}
+
+	result := make([]rscoreapi.Project, 0, len(members))
+	for name, namespaces := range members {
+		prj := newProject(name, namespaces)
+		prj.Spec.NamespaceSelector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{s.key: name},
+		}
+		presets, err := collectPresets(s.kc, prj)
+		if err != nil {
+			return nil, err
+		}
+		prj.Spec.Presets = presets
+		result = append(result, prj)
+	}
+	return result, nil
+}
+
+func (s *labelSource) Create(ctx context.Context, in *rscoreapi.Project) (*rscoreapi.Project, error) {
+	current, err := s.members(ctx, in.Name)
+	if err != nil {
+		return nil, err
+	}
+	if current.Len() > 0 {
+		return nil, apierrors.NewAlreadyExists(gr, in.Name)
+	}
+	desired, err := s.selectNamespaces(ctx, in.Spec)
+	if err != nil {
+		return nil, err
+	}
+	if desired.Len() == 0 {
+		return nil, apierrors.NewBadRequest("spec.namespaces or spec.namespaceSelector must select a namespace")
+	}
+	if err := s.relabel(ctx, in.Name, current, desired); err != nil {
+		return nil, err
+	}
+	return GetProject(ctx, s, in.Name)
+}
+
+func (s *labelSource) Update(ctx context.Context, in *rscoreapi.Project) (*rscoreapi.Project, error) {
+	current, err := s.members(ctx, in.Name)
+	if err != nil {
+		return nil, err
+	}
+	if current.Len() == 0 {
+		return nil, apierrors.NewNotFound(gr, in.Name)
+	}
+	desired, err := s.selectNamespaces(ctx, in.Spec)
+	if err != nil {
+		return nil, err
+	}
+	if desired.Len() == 0 {
+		return nil, apierrors.NewBadRequest("spec.namespaces or spec.namespaceSelector must select a namespace")
+	}
+	if err := s.relabel(ctx, in.Name, current, desired); err != nil {
+		return nil, err
+	}
+	return GetProject(ctx, s, in.Name)
+}
+
+func (s *labelSource) Delete(ctx context.Context, name string) error {
+	current, err := s.members(ctx, name)
+	if err != nil {
+		return err
+	}
+	if current.Len() == 0 {
+		return apierrors.NewNotFound(gr, name)
+	}
+	return s.relabel(ctx, name, current, sets.NewString())
+}
+
+// members returns the namespaces labeled with the project name.
+func (s *labelSource) members(ctx context.Context, name string) (sets.String, error) {
+	var list core.NamespaceList
+	if err := s.kc.List(ctx, &list, client.MatchingLabels{s.key: name}); err != nil {
+		return nil, err
+	}
+	return sets.NewString(clustermeta.Names(list.Items)...), nil
+}
+
+// selectNamespaces returns the namespaces listed in the spec and the ones selected by
+// its namespace selector, other than the one of the label of the source.
+func (s *labelSource) selectNamespaces(ctx context.Context, spec rscoreapi.ProjectSpec) (sets.String, error) {
+	result := sets.NewString()
+	for _, name := range spec.Namespaces {
+		var ns core.Namespace
+		err := s.kc.Get(ctx, client.ObjectKey{Name: name}, &ns)
+		if apierrors.IsNotFound(err) {
+			return nil, apierrors.NewBadRequest(fmt.Sprintf("namespace %s not found", name))
+		} else if err != nil {
+			return nil, err
+		}
+		result.Insert(name)
+	}
+
+	sel := spec.NamespaceSelector
+	if sel == nil || (len(sel.MatchExpressions) == 0 && len(sel.MatchLabels) == 1 && sel.MatchLabels[s.key] != "") {
+		return result, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(sel)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	if selector.Empty() {
+		return nil, apierrors.NewBadRequest("spec.namespaceSelector must not select every namespace")
+	}
+	var list core.NamespaceList
+	if err := s.kc.List(ctx, &list, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+	result.Insert(clustermeta.Names(list.Items)...)
+	return result, nil
+}
+
+// authorizeRelabel checks that the caller can patch the namespaces relabel changes.
+func (s *labelSource) authorizeRelabel(ctx context.Context, current, desired sets.String) error {
+	changed := current.Difference(desired).Insert(desired.Difference(current).UnsortedList()...)
+	for _, nsName := range changed.List() {
+		if err := authorize(ctx, s.a, "patch", namespaceGR, nsName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRelabel checks that relabel can move the label of the project, ie, the caller can
+// patch the changed namespaces and none of the added ones belongs to another project.
+func (s *labelSource) checkRelabel(ctx context.Context, name string, current, desired sets.String) error {
+	if err := s.authorizeRelabel(ctx, current, desired); err != nil {
+		return err
+	}
+	for _, nsName := range desired.Difference(current).List() {
+		var ns core.Namespace
+		if err := s.kc.Get(ctx, client.ObjectKey{Name: nsName}, &ns); err != nil {
+			return err
+		}
+		if other := ns.Labels[s.key]; other != "" && other != name {
+			return apierrors.NewConflict(gr, name, fmt.Errorf("namespace %s belongs to project %s", nsName, other))
+		}
+	}
+	return nil
+}
+
+// relabel moves the label of the project from the current to the desired namespaces.
+// Namespaces of other projects are not taken over.
+func (s *labelSource) relabel(ctx context.Context, name string, current, desired sets.String) error {
+	if err := s.checkRelabel(ctx, name, current, desired); err != nil {
+		return err
+	}
+	for _, nsName := range desired.Difference(current).List() {
+		if err := s.patchLabel(ctx, nsName, name); err != nil {
+			return err
+		}
+	}
+	for _, nsName := range current.Difference(desired).List() {
+		if err := s.patchLabel(ctx, nsName, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// patchLabel sets the label of the source on a namespace, or removes it if value is empty.
+func (s *labelSource) patchLabel(ctx context.Context, nsName, value string) error {
+	ns := core.Namespace{ObjectMeta: metav1.ObjectMeta{Name: nsName}}
+	if err := s.kc.Get(ctx, client.ObjectKeyFromObject(&ns), &ns); err != nil {
+		return err
+	}
+	_, err := cu.Patch(ctx, s.kc, &ns, func(obj client.Object) client.Object {
+		in := obj.(*core.Namespace)
+		if value == "" {
+			delete(in.Labels, s.key)
+		} else {
+			if in.Labels == nil {
+				in.Labels = map[string]string{}
+			}
+			in.Labels[s.key] = value
+		}
+		return in
+	})
+	return err
+}
+
+type projectQuotaSource struct {
+	*labelSource
+}
+
+var _ Source = &projectQuotaSource{}
+
+func (s *projectQuotaSource) Type() SourceType {
+	return SourceProjectQuota
+}
+
+// projectMembers returns the namespaces of every ProjectQuota.
+func (s *projectQuotaSource) projectMembers(ctx context.Context) (map[string][]core.Namespace, []mgmtapi.ProjectQuota, error) {
+	var quotas mgmtapi.ProjectQuotaList
+	if err := s.kc.List(ctx, &quotas); meta.IsNoMatchError(err) {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	var labeled core.NamespaceList
+	if err := s.kc.List(ctx, &labeled, client.HasLabels{s.key}); err != nil {
+		return nil, nil, err
+	}
+
+	members := map[string][]core.Namespace{}
+	for _, pq := range quotas.Items {
+		governed, err := projectquotacontroller.NamespacesOfProjectQuota(ctx, s.kc, pq.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+		seen := sets.NewString()
+		for _, ns := range governed.Items {
+			seen.Insert(ns.Name)
+			members[pq.Name] = append(members[pq.Name], ns)
+		}
+		for _, ns := range labeled.Items {
+			if ns.Labels[s.key] == pq.Name && !seen.Has(ns.Name) {
+				members[pq.Name] = append(members[pq.Name], ns)
+			}
+		}
+	}
+	return members, quotas.Items, nil
+}
+
+func (s *projectQuotaSource) List(ctx context.Context) ([]rscoreapi.Project, error) {
+	members, quotas, err := s.projectMembers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]rscoreapi.Project, 0, len(quotas))
+	for _, pq := range quotas {
+		prj := newProject(pq.Name, members[pq.Name])
+		prj.UID = pq.UID
+		prj.CreationTimestamp = pq.CreationTimestamp
+		prj.Spec.NamespaceSelector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{s.key: pq.Name},
+		}
+		presets, err := collectPresets(s.kc, prj)
+		if err != nil {
+			return nil, err
+		}
+		prj.Spec.Presets = presets
+		result = append(result, prj)
+	}
+	return result, nil
+}
+
+func (s *projectQuotaSource) NamespaceProjects(ctx context.Context) (map[string]string, error) {
+	members, _, err := s.projectMembers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]string{}
+	for name, namespaces := range members {
+		for _, ns := range namespaces {
+			if _, found := result[ns.Name]; !found {
+				result[ns.Name] = name
+			}
+		}
+	}
+	return result, nil
+}
+
+// Create creates a ProjectQuota without quotas for the project and labels its namespaces.
+func (s *projectQuotaSource) Create(ctx context.Context, in *rscoreapi.Project) (*rscoreapi.Project, error) {
+	if err := authorize(ctx, s.a, "create", projectQuotaGR, in.Name); err != nil {
+		return nil, err
+	}
+	desired, err := s.selectNamespaces(ctx, in.Spec)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.members(ctx, in.Name)
+	if err != nil {
+		return nil, err
+	}
+	// checked before the ProjectQuota is created, so that a rejected project leaves nothing behind
+	if err := s.checkRelabel(ctx, in.Name, current, desired); err != nil {
+		return nil, err
+	}
+
+	pq := mgmtapi.ProjectQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        in.Name,
+			Labels:      in.Labels,
+			Annotations: in.Annotations,
+		},
+		Spec: mgmtapi.ProjectQuotaSpec{
+			Quotas: []mgmtapi.ResourceQuotaSpec{},
+		},
+	}
+	if err := s.kc.Create(ctx, &pq); apierrors.IsAlreadyExists(err) {
+		return nil, apierrors.NewAlreadyExists(gr, in.Name)
+	} else if err != nil {
+		return nil, err
+	}
+	if err := s.relabel(ctx, in.Name, current, desired); err != nil {
+		// the namespaces labeled so far keep the label, and are members of a retried project
+		if derr := s.kc.Delete(ctx, &pq); client.IgnoreNotFound(derr) != nil {
+			klog.ErrorS(derr, "failed to delete ProjectQuota of a project that was not created", "name", in.Name)
+		}
+		return nil, err
+	}
+	return GetProject(ctx, s, in.Name)
+}
+
+func (s *projectQuotaSource) Update(ctx context.Context, in *rscoreapi.Project) (*rscoreapi.Project, error) {
+	if err := s.getQuota(ctx, in.Name); err != nil {
+		return nil, err
+	}
+	desired, err := s.selectNamespaces(ctx, in.Spec)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.members(ctx, in.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.relabel(ctx, in.Name, current, desired); err != nil {
+		return nil, err
+	}
+	return GetProject(ctx, s, in.Name)
+}
+
+// Delete removes the label of the project from its namespaces and deletes its ProjectQuota.
+func (s *projectQuotaSource) Delete(ctx context.Context, name string) error {
+	if err := s.getQuota(ctx, name); err != nil {
+		return err
+	}
+	if err := authorize(ctx, s.a, "delete", projectQuotaGR, name); err != nil {
+		return err
+	}
+	current, err := s.members(ctx, name)
+	if err != nil {
+		return err
+	}
+	if err := s.relabel(ctx, name, current, sets.NewString()); err != nil {
+		return err
+	}
+	return client.IgnoreNotFound(s.kc.Delete(ctx, &mgmtapi.ProjectQuota{ObjectMeta: metav1.ObjectMeta{Name: name}}))
+}
+
+func (s *projectQuotaSource) getQuota(ctx context.Context, name string) error {
+	var pq mgmtapi.ProjectQuota
+	err := s.kc.Get(ctx, client.ObjectKey{Name: name}, &pq)
+	if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return apierrors.NewNotFound(gr, name)
+	}
+	return err
+}
+
+type crdSource struct {
+	kc client.Client
+	a  authorizer.Authorizer
+}
+
+var _ Source = &crdSource{}
+
+func (s *crdSource) Type() SourceType {
+	return SourceCRD
+}
+
+func (s *crdSource) List(ctx context.Context) ([]rscoreapi.Project, error) {
+	var list projectapi.ProjectList
+	if err := s.kc.List(ctx, &list); meta.IsNoMatchError(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	result := make([]rscoreapi.Project, 0, len(list.Items))
+	for _, in := range list.Items {
+		prj, err := s.toProject(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *prj)
+	}
+	return result, nil
+}
+
+func (s *crdSource) NamespaceProjects(ctx context.Context) (map[string]string, error) {
+	projects, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].Name < projects[j].Name
+	})
+	result := map[string]string{}
+	for _, prj := range projects {
+		for _, ns := range prj.Spec.Namespaces {
+			if _, found := result[ns]; !found {
+				result[ns] = prj.Name
+			}
+		}
+	}
+	return result, nil
+}
+
+func (s *crdSource) Create(ctx context.Context, in *rscoreapi.Project) (*rscoreapi.Project, error) {
+	if err := authorize(ctx, s.a, "create", projectGR, in.Name); err != nil {
+		return nil, err
+	}
+	obj := projectapi.Project{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        in.Name,
+			Labels:      in.Labels,
+			Annotations: in.Annotations,
+		},
+		Spec: crdSpec(in.Spec),
+	}
+	if err := s.kc.Create(ctx, &obj); apierrors.IsAlreadyExists(err) {
+		return nil, apierrors.NewAlreadyExists(gr, in.Name)
+	} else if err != nil {
+		return nil, err
+	}
+	return s.toProject(ctx, obj)
+}
+
+func (s *crdSource) Update(ctx context.Context, in *rscoreapi.Project) (*rscoreapi.Project, error) {
+	if err := authorize(ctx, s.a, "update", projectGR, in.Name); err != nil {
+		return nil, err
+	}
+	var obj projectapi.Project
+	if err := s.kc.Get(ctx, client.ObjectKey{Name: in.Name}, &obj); apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return nil, apierrors.NewNotFound(gr, in.Name)
+	} else if err != nil {
+		return nil, err
+	}
+	if in.ResourceVersion != "" {
+		obj.ResourceVersion = in.ResourceVersion
+	}
+	obj.Labels = in.Labels
+	obj.Annotations = in.Annotations
+	obj.Spec = crdSpec(in.Spec)
+	if err := s.kc.Update(ctx, &obj); apierrors.IsConflict(err) {
+		return nil, apierrors.NewConflict(gr, in.Name, err)
+	} else if err != nil {
+		return nil, err
+	}
+	return s.toProject(ctx, obj)
+}
+
+func (s *crdSource) Delete(ctx context.Context, name string) error {
+	if err := authorize(ctx, s.a, "delete", projectGR, name); err != nil {
+		return err
+	}
+	err := s.kc.Delete(ctx, &projectapi.Project{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return apierrors.NewNotFound(gr, name)
+	}
+	return err
+}
+
+// toProject resolves the namespace selector of a Project object into the namespaces of
+// the project.
+func (s *crdSource) toProject(ctx context.Context, in projectapi.Project) (*rscoreapi.Project, error) {
+	namespaces := sets.NewString(in.Spec.Namespaces...)
+	if in.Spec.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(in.Spec.NamespaceSelector)
+		if err != nil {
+			return nil, err
+		}
+		if !selector.Empty() {
+			var list core.NamespaceList
+			if err := s.kc.List(ctx, &list, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+				return nil, err
+			}
+			namespaces.Insert(clustermeta.Names(list.Items)...)
+		}
+	}
+
+	prj := rscoreapi.Project{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              in.Name,
+			UID:               in.UID,
+			ResourceVersion:   in.ResourceVersion,
+			CreationTimestamp: in.CreationTimestamp,
+			Labels:            in.Labels,
+			Annotations:       in.Annotations,
+		},
+		Spec: rscoreapi.ProjectSpec{
+			Type:              in.Spec.Type,
+			Namespaces:        namespaces.List(),
+			NamespaceSelector: in.Spec.NamespaceSelector,
+			Monitoring:        in.Spec.Monitoring,
+		},
+	}
+	if prj.Spec.Type == "" {
+		prj.Spec.Type = rscoreapi.ProjectUser
+	}
+	presets, err := collectPresets(s.kc, prj)
+	if err != nil {
+		return nil, err
+	}
+	prj.Spec.Presets = presets
+	return &prj, nil
+}
+
+func crdSpec(in rscoreapi.ProjectSpec) projectapi.ProjectSpec {
+	return projectapi.ProjectSpec{
+		Type:              in.Type,
+		Namespaces:        in.Namespaces,
+		NamespaceSelector: in.NamespaceSelector,
+		Monitoring:        in.Monitoring,
+	}
+}
+
+// newProject returns a project of the namespaces. Its type is Default or System if it
+// contains the default or the kube-system namespace.
+func newProject(name string, namespaces []core.Namespace) rscoreapi.Project {
+	prj := rscoreapi.Project{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(time.Now()),
+			UID:               types.UID(uuid.NewSHA1(uuid.NameSpaceOID, []byte(name)).String()),
+		},
+		Spec: rscoreapi.ProjectSpec{
+			Type: rscoreapi.ProjectUser,
+		},
+	}
+	for _, ns := range namespaces {
+		if ns.CreationTimestamp.Before(&prj.CreationTimestamp) {
+			prj.CreationTimestamp = ns.CreationTimestamp
+		}
+		switch ns.Name {
+		case metav1.NamespaceDefault:
+			prj.Spec.Type = rscoreapi.ProjectDefault
+		case metav1.NamespaceSystem:
+			prj.Spec.Type = rscoreapi.ProjectSystem
+		}
+		prj.Spec.Namespaces = append(prj.Spec.Namespaces, ns.Name)
+	}
+	sort.Strings(prj.Spec.Namespaces)
+	return prj
+}
@@ -0,0 +1,219 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package project
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	rscoreapi "kmodules.xyz/resource-metadata/apis/core/v1alpha1"
+	mgmtapi "kmodules.xyz/resource-metadata/apis/management/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	chartsapi "x-helm.dev/apimachinery/apis/charts/v1alpha1"
+)
+
+// denyNamespaces forbids patching the listed namespaces.
+type denyNamespaces []string
+
+func (d denyNamespaces) Authorize(_ context.Context, attrs authorizer.Attributes) (authorizer.Decision, string, error) {
+	if attrs.GetResource() == "namespaces" && slices.Contains(d, attrs.GetName()) {
+		return authorizer.DecisionDeny, "not allowed", nil
+	}
+	return authorizer.DecisionAllow, "", nil
+}
+
+func TestLabelSource(t *testing.T) {
+	ns := func(name string, labels map[string]string) client.Object {
+		return &core.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(chartsapi.AddToScheme(scheme))
+	kc := crfake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		ns("default", nil),
+		ns("team-a", map[string]string{"team": "a"}),
+		ns("team-a-dev", map[string]string{"team": "a"}),
+		ns("team-b", map[string]string{DefaultLabelKey: "b"}),
+		ns("private", nil),
+	).Build()
+	src := NewSource(kc, denyNamespaces{"private"}, Options{Source: SourceLabel})
+	ctx := apirequest.WithUser(context.TODO(), &user.DefaultInfo{Name: "admin"})
+
+	namespacesOf := func(name string) []string {
+		t.Helper()
+		prj, err := GetProject(ctx, src, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return prj.Spec.Namespaces
+	}
+
+	if src.Type() != SourceLabel {
+		t.Errorf("expected a Label source, got %s", src.Type())
+	}
+	if got := namespacesOf("b"); !reflect.DeepEqual(got, []string{"team-b"}) {
+		t.Errorf("expected project b to contain team-b, got %v", got)
+	}
+
+	_, err := src.Create(ctx, &rscoreapi.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: "a"},
+		Spec: rscoreapi.ProjectSpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := namespacesOf("a"); !reflect.DeepEqual(got, []string{"team-a", "team-a-dev"}) {
+		t.Errorf("expected project a to contain the team a namespaces, got %v", got)
+	}
+
+	_, err = src.Create(ctx, &rscoreapi.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: "a"},
+		Spec:       rscoreapi.ProjectSpec{Namespaces: []string{"default"}},
+	})
+	if !apierrors.IsAlreadyExists(err) {
+		t.Errorf("expected an already exists error, got %v", err)
+	}
+
+	_, err = src.Update(ctx, &rscoreapi.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: "a"},
+		Spec:       rscoreapi.ProjectSpec{Namespaces: []string{"team-a", "team-b"}},
+	})
+	if !apierrors.IsConflict(err) {
+		t.Errorf("expected a conflict for a namespace of another project, got %v", err)
+	}
+
+	prj, err := src.Update(ctx, &rscoreapi.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: "a"},
+		Spec:       rscoreapi.ProjectSpec{Namespaces: []string{"team-a", "default"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(prj.Spec.Namespaces, []string{"default", "team-a"}) || prj.Spec.Type != rscoreapi.ProjectDefault {
+		t.Errorf("unexpected project after update %+v", prj.Spec)
+	}
+
+	_, err = src.Update(ctx, &rscoreapi.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: "a"},
+		Spec:       rscoreapi.ProjectSpec{Namespaces: []string{"team-a", "default", "private"}},
+	})
+	if !apierrors.IsForbidden(err) {
+		t.Errorf("expected a forbidden error for a namespace the caller can't patch, got %v", err)
+	}
+
+	got, err := src.NamespaceProjects(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{"default": "a", "team-a": "a", "team-b": "b"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected namespace projects %v, got %v", expected, got)
+	}
+
+	if err := src.Delete(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetProject(ctx, src, "a"); !apierrors.IsNotFound(err) {
+		t.Errorf("expected project a to be deleted, got %v", err)
+	}
+	var dflt core.Namespace
+	if err := kc.Get(ctx, client.ObjectKey{Name: "default"}, &dflt); err != nil {
+		t.Fatal(err)
+	}
+	if _, found := dflt.Labels[DefaultLabelKey]; found {
+		t.Error("expected the project label to be removed from the default namespace")
+	}
+}
+
+func TestProjectQuotaSourceCreate(t *testing.T) {
+	ns := func(name string, labels map[string]string) client.Object {
+		return &core.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(chartsapi.AddToScheme(scheme))
+	utilruntime.Must(mgmtapi.AddToScheme(scheme))
+	failPatch := true
+	kc := crfake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		ns("team-a", nil),
+		ns("team-b", map[string]string{DefaultLabelKey: "b"}),
+	).WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if failPatch {
+				return apierrors.NewServiceUnavailable("unavailable")
+			}
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+	}).Build()
+	src := NewSource(kc, denyNamespaces{}, Options{Source: SourceProjectQuota})
+	ctx := apirequest.WithUser(context.TODO(), &user.DefaultInfo{Name: "admin"})
+
+	quotaExists := func() bool {
+		t.Helper()
+		err := kc.Get(ctx, client.ObjectKey{Name: "a"}, &mgmtapi.ProjectQuota{})
+		if client.IgnoreNotFound(err) != nil {
+			t.Fatal(err)
+		}
+		return err == nil
+	}
+
+	_, err := src.Create(ctx, &rscoreapi.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: "a"},
+		Spec:       rscoreapi.ProjectSpec{Namespaces: []string{"team-b"}},
+	})
+	if !apierrors.IsConflict(err) {
+		t.Errorf("expected a conflict for a namespace of another project, got %v", err)
+	}
+	if quotaExists() {
+		t.Error("expected no ProjectQuota for a conflicting project")
+	}
+
+	in := &rscoreapi.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: "a"},
+		Spec:       rscoreapi.ProjectSpec{Namespaces: []string{"team-a"}},
+	}
+	if _, err := src.Create(ctx, in); !apierrors.IsServiceUnavailable(err) {
+		t.Errorf("expected the relabel error, got %v", err)
+	}
+	if quotaExists() {
+		t.Error("expected the ProjectQuota to be deleted when relabel fails")
+	}
+
+	failPatch = false
+	prj, err := src.Create(ctx, in)
+	if err != nil {
+		t.Fatalf("expected the retry to succeed, got %v", err)
+	}
+	if !reflect.DeepEqual(prj.Spec.Namespaces, []string{"team-a"}) {
+		t.Errorf("unexpected namespaces %v", prj.Spec.Namespaces)
+	}
+}
@@ -45,6 +45,7 @@ import (
 
 type Storage struct {
 	kc        client.Client
+	src       Source
 	convertor rest.TableConvertor
 }
 
@@ -54,6 +55,9 @@ var (
 	_ rest.Storage                  = &Storage{}
 	_ rest.Lister                   = &Storage{}
 	_ rest.Getter                   = &Storage{}
+	_ rest.Creater                  = &Storage{}
+	_ rest.Updater                  = &Storage{}
+	_ rest.GracefulDeleter          = &Storage{}
 	_ rest.SingularNameProvider     = &Storage{}
 
 	gr = schema.GroupResource{
@@ -62,9 +66,10 @@ var (
 	}
 )
 
-func NewStorage(kc client.Client) *Storage {
+func NewStorage(kc client.Client, src Source) *Storage {
 	s := &Storage{
 		kc:        kc,
+		src:       src,
 		convertor: NewDefaultTableConvertor(gr),
 	}
 	return s
@@ -89,10 +94,7 @@ func (r *Storage) New() runtime.Object {
 func (r *Storage) Destroy() {}
 
 func (r *Storage) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
-	if clustermeta.IsRancherManaged(r.kc.RESTMapper()) {
-		return GetRancherProject(r.kc, name)
-	}
-	return nil, apierrors.NewNotFound(gr, name)
+	return GetProject(ctx, r.src, name)
 }
 
 func (r *Storage) NewList() runtime.Object {
@@ -100,14 +102,13 @@ func (r *Storage) NewList() runtime.Object {
 }
 
 func (r *Storage) List(ctx context.Context, options *internalversion.ListOptions) (runtime.Object, error) {
-	var projects []rscoreapi.Project
-	var err error
-	if clustermeta.IsRancherManaged(r.kc.RESTMapper()) {
-		projects, err = ListRancherProjects(r.kc)
-		if err != nil {
-			return nil, err
-		}
+	projects, err := r.src.List(ctx)
+	if err != nil {
+		return nil, err
 	}
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].Name < projects[j].Name
+	})
 
 	result := rscoreapi.ProjectList{
 		TypeMeta: metav1.TypeMeta{},
@@ -115,7 +116,57 @@ func (r *Storage) List(ctx context.Context, options *internalversion.ListOptions
 		Items: projects,
 	}
 
-	return &result, err
+	return &result, nil
+}
+
+func (r *Storage) Create(ctx context.Context, obj runtime.Object, createValidation rest.ValidateObjectFunc, _ *metav1.CreateOptions) (runtime.Object, error) {
+	in := obj.(*rscoreapi.Project)
+	if in.Name == "" {
+		return nil, apierrors.NewBadRequest("metadata.name is required")
+	}
+	if createValidation != nil {
+		if err := createValidation(ctx, obj); err != nil {
+			return nil, err
+		}
+	}
+	return r.src.Create(ctx, in)
+}
+
+func (r *Storage) Update(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo, _ rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc, _ bool, _ *metav1.UpdateOptions) (runtime.Object, bool, error) {
+	oldObj, err := GetProject(ctx, r.src, name)
+	if err != nil {
+		return nil, false, err
+	}
+	newObj, err := objInfo.UpdatedObject(ctx, oldObj)
+	if err != nil {
+		return nil, false, err
+	}
+	if updateValidation != nil {
+		if err := updateValidation(ctx, newObj, oldObj); err != nil {
+			return nil, false, err
+		}
+	}
+
+	in := newObj.(*rscoreapi.Project)
+	in.Name = name
+	result, err := r.src.Update(ctx, in)
+	return result, false, err
+}
+
+func (r *Storage) Delete(ctx context.Context, name string, deleteValidation rest.ValidateObjectFunc, _ *metav1.DeleteOptions) (runtime.Object, bool, error) {
+	obj, err := GetProject(ctx, r.src, name)
+	if err != nil {
+		return nil, false, err
+	}
+	if deleteValidation != nil {
+		if err := deleteValidation(ctx, obj); err != nil {
+			return nil, false, err
+		}
+	}
+	if err := r.src.Delete(ctx, name); err != nil {
+		return nil, false, err
+	}
+	return obj, true, nil
 }
 
 func (r *Storage) ConvertToTable(ctx context.Context, object runtime.Object, tableOptions runtime.Object) (*metav1.Table, error) {
@@ -179,52 +230,10 @@ func ListRancherProjects(kc client.Client) ([]rscoreapi.Project, error) {
 
 	for projectId, prj := range projects {
 		var hasUseNs bool
-		presets := prj.Spec.Presets
 		for _, ns := range prj.Spec.Namespaces {
 			if !strings.HasPrefix(ns, "cattle-project-p-") {
 				hasUseNs = true
 			}
-
-			if prj.Spec.Type == rscoreapi.ProjectSystem {
-				if ns == metav1.NamespaceSystem {
-					var ccps chartsapi.ClusterChartPresetList
-					err := kc.List(context.TODO(), &ccps)
-					if err != nil && !meta.IsNoMatchError(err) {
-						return nil, err
-					}
-					for _, x := range ccps.Items {
-						presets = append(presets, shared.SourceLocator{
-							Resource: kmapi.ResourceID{
-								Group:   chartsapi.GroupVersion.Group,
-								Version: chartsapi.GroupVersion.Version,
-								Kind:    chartsapi.ResourceKindClusterChartPreset,
-							},
-							Ref: kmapi.ObjectReference{
-								Name: x.Name,
-							},
-						})
-					}
-				}
-			} else {
-				var cps chartsapi.ChartPresetList
-				err := kc.List(context.TODO(), &cps, client.InNamespace(ns))
-				if err != nil && !meta.IsNoMatchError(err) {
-					return nil, err
-				}
-				for _, x := range cps.Items {
-					presets = append(presets, shared.SourceLocator{
-						Resource: kmapi.ResourceID{
-							Group:   chartsapi.GroupVersion.Group,
-							Version: chartsapi.GroupVersion.Version,
-							Kind:    chartsapi.ResourceKindChartPreset,
-						},
-						Ref: kmapi.ObjectReference{
-							Name:      x.Name,
-							Namespace: x.Namespace,
-						},
-					})
-				}
-			}
 		}
 
 		// drop projects where all namespaces start with cattle-project-p
@@ -233,13 +242,10 @@ func ListRancherProjects(kc client.Client) ([]rscoreapi.Project, error) {
 			continue
 		}
 
-		sort.Slice(presets, func(i, j int) bool {
-			if presets[i].Ref.Namespace != presets[j].Ref.Namespace {
-				return presets[i].Ref.Namespace < presets[j].Ref.Namespace
-			}
-			return presets[i].Ref.Name < presets[j].Ref.Name
-		})
-
+		presets, err := collectPresets(kc, prj)
+		if err != nil {
+			return nil, err
+		}
 		prj.Spec.Presets = presets
 		projects[projectId] = prj
 	}
@@ -312,6 +318,62 @@ func ListRancherProjects(kc client.Client) ([]rscoreapi.Project, error) {
 	return result, nil
 }
 
+// collectPresets returns the chart presets of the namespaces of a project, sorted by namespace
+// and name. The ClusterChartPresets belong to the system project.
+func collectPresets(kc client.Client, prj rscoreapi.Project) ([]shared.SourceLocator, error) {
+	presets := prj.Spec.Presets
+	for _, ns := range prj.Spec.Namespaces {
+		if prj.Spec.Type == rscoreapi.ProjectSystem {
+			if ns == metav1.NamespaceSystem {
+				var ccps chartsapi.ClusterChartPresetList
+				err := kc.List(context.TODO(), &ccps)
+				if err != nil && !meta.IsNoMatchError(err) {
+					return nil, err
+				}
+				for _, x := range ccps.Items {
+					presets = append(presets, shared.SourceLocator{
+						Resource: kmapi.ResourceID{
+							Group:   chartsapi.GroupVersion.Group,
+							Version: chartsapi.GroupVersion.Version,
+							Kind:    chartsapi.ResourceKindClusterChartPreset,
+						},
+						Ref: kmapi.ObjectReference{
+							Name: x.Name,
+						},
+					})
+				}
+			}
+		} else {
+			var cps chartsapi.ChartPresetList
+			err := kc.List(context.TODO(), &cps, client.InNamespace(ns))
+			if err != nil && !meta.IsNoMatchError(err) {
+				return nil, err
+			}
+			for _, x := range cps.Items {
+				presets = append(presets, shared.SourceLocator{
+					Resource: kmapi.ResourceID{
+						Group:   chartsapi.GroupVersion.Group,
+						Version: chartsapi.GroupVersion.Version,
+						Kind:    chartsapi.ResourceKindChartPreset,
+					},
+					Ref: kmapi.ObjectReference{
+						Name:      x.Name,
+						Namespace: x.Namespace,
+					},
+				})
+			}
+		}
+	}
+
+	sort.Slice(presets, func(i, j int) bool {
+		if presets[i].Ref.Namespace != presets[j].Ref.Namespace {
+			return presets[i].Ref.Namespace < presets[j].Ref.Namespace
+		}
+		return presets[i].Ref.Name < presets[j].Ref.Name
+	})
+	return presets, nil
+}
+
 func FindSiblingAlertManagerForPrometheus(kc client.Client, key types.NamespacedName) (*monitoringv1.Alertmanager, error) {
 	var list monitoringv1.AlertmanagerList
 	err := kc.List(context.TODO(), &list, client.InNamespace(key.Namespace))
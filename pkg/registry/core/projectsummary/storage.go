@@ -0,0 +1,222 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package projectsummary
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	uicoreapi "kubeops.dev/ui-server/apis/core/v1alpha1"
+	"kubeops.dev/ui-server/pkg/registry/core/project"
+	"kubeops.dev/ui-server/pkg/shared"
+
+	"github.com/google/uuid"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	kmapi "kmodules.xyz/client-go/api/v1"
+	clustermeta "kmodules.xyz/client-go/cluster"
+	promclient "kmodules.xyz/monitoring-agent-api/client"
+	rscoreapi "kmodules.xyz/resource-metadata/apis/core/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Storage serves the ProjectSummary of each project of the project source. The objects of
+// the registered kinds, the PersistentVolumeClaims and the Gatekeeper constraints are read
+// from the manager's cache, whose informers are started by the first summary and shared
+// with the watches of the other storages.
+type Storage struct {
+	kc        client.Client
+	mapper    *shared.RESTMapper
+	clusterID string
+	a         authorizer.Authorizer
+	cache     client.Reader
+	projects  project.Source
+	builder   *promclient.ClientBuilder
+	convertor rest.TableConvertor
+}
+
+var (
+	_ rest.GroupVersionKindProvider = &Storage{}
+	_ rest.Scoper                   = &Storage{}
+	_ rest.Storage                  = &Storage{}
+	_ rest.Getter                   = &Storage{}
+	_ rest.Lister                   = &Storage{}
+	_ rest.SingularNameProvider     = &Storage{}
+)
+
+func NewStorage(kc client.Client, mapper *shared.RESTMapper, clusterID string, a authorizer.Authorizer, c client.Reader, projects project.Source, builder *promclient.ClientBuilder) *Storage {
+	return &Storage{
+		kc:        kc,
+		mapper:    mapper,
+		clusterID: clusterID,
+		a:         a,
+		cache:     c,
+		projects:  projects,
+		builder:   builder,
+		convertor: rest.NewDefaultTableConvertor(schema.GroupResource{
+			Group:    uicoreapi.GroupName,
+			Resource: uicoreapi.ResourceProjectSummaries,
+		}),
+	}
+}
+
+func (r *Storage) GroupVersionKind(_ schema.GroupVersion) schema.GroupVersionKind {
+	return uicoreapi.SchemeGroupVersion.WithKind(uicoreapi.ResourceKindProjectSummary)
+}
+
+func (r *Storage) NamespaceScoped() bool {
+	return false
+}
+
+func (r *Storage) GetSingularName() string {
+	return strings.ToLower(uicoreapi.ResourceKindProjectSummary)
+}
+
+func (r *Storage) New() runtime.Object {
+	return &uicoreapi.ProjectSummary{}
+}
+
+func (r *Storage) Destroy() {}
+
+func (r *Storage) Get(ctx context.Context, name string, _ *metav1.GetOptions) (runtime.Object, error) {
+	user, ok := apirequest.UserFrom(ctx)
+	if !ok {
+		return nil, apierrors.NewBadRequest("missing user info")
+	}
+
+	prj, err := project.GetProject(ctx, r.projects, name)
+	if err != nil {
+		return nil, err
+	}
+	clientOrgNs, err := r.clientOrgNamespace(user)
+	if err != nil {
+		return nil, err
+	}
+	cmeta, err := clustermeta.ClusterMetadata(r.kc)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	return r.summarize(ctx, user, prj, clientOrgNs, cmeta, r.listViolations(ctx), time.Now())
+}
+
+func (r *Storage) NewList() runtime.Object {
+	return &uicoreapi.ProjectSummaryList{}
+}
+
+func (r *Storage) List(ctx context.Context, options *internalversion.ListOptions) (runtime.Object, error) {
+	user, ok := apirequest.UserFrom(ctx)
+	if !ok {
+		return nil, apierrors.NewBadRequest("missing user info")
+	}
+	if options == nil {
+		options = &internalversion.ListOptions{}
+	}
+
+	lsel := options.LabelSelector
+	if lsel == nil {
+		lsel = labels.Everything()
+	}
+	fsel := options.FieldSelector
+	if fsel == nil {
+		fsel = fields.Everything()
+	}
+	for _, req := range fsel.Requirements() {
+		if req.Field != "metadata.name" {
+			return nil, apierrors.NewBadRequest("field label not supported: " + req.Field)
+		}
+	}
+
+	projects, err := r.projects.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].Name < projects[j].Name
+	})
+
+	clientOrgNs, err := r.clientOrgNamespace(user)
+	if err != nil {
+		return nil, err
+	}
+	cmeta, err := clustermeta.ClusterMetadata(r.kc)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+
+	violations := r.listViolations(ctx)
+	now := time.Now()
+	result := uicoreapi.ProjectSummaryList{
+		Items: make([]uicoreapi.ProjectSummary, 0, len(projects)),
+	}
+	for i := range projects {
+		prj := &projects[i]
+		if !lsel.Matches(labels.Set(prj.Labels)) || !fsel.Matches(fields.Set{"metadata.name": prj.Name}) {
+			continue
+		}
+		summary, err := r.summarize(ctx, user, prj, clientOrgNs, cmeta, violations, now)
+		if err != nil {
+			return nil, err
+		}
+		result.Items = append(result.Items, *summary)
+	}
+	return &result, nil
+}
+
+// clientOrgNamespace returns the namespace of the client organization of u, if any.
+// Summaries of client org users only include that namespace.
+func (r *Storage) clientOrgNamespace(u user.Info) (string, error) {
+	result, err := clustermeta.IsClientOrgMember(r.kc, u)
+	if err != nil {
+		return "", err
+	}
+	if result.IsClientOrg {
+		return result.Namespace.Name, nil
+	}
+	return "", nil
+}
+
+func (r *Storage) newSummary(prj *rscoreapi.Project, namespaces []string, cmeta *kmapi.ClusterMetadata, now time.Time) *uicoreapi.ProjectSummary {
+	return &uicoreapi.ProjectSummary{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              prj.Name,
+			Labels:            prj.Labels,
+			CreationTimestamp: metav1.NewTime(now),
+			// stable across calls, so that the summary of a project is always the same object
+			UID: types.UID(uuid.NewSHA1(uuid.NameSpaceOID, []byte(r.clusterID+"/projectsummary/"+prj.Name)).String()),
+		},
+		Spec: uicoreapi.ProjectSummarySpec{
+			Cluster:    *cmeta,
+			Namespaces: namespaces,
+		},
+	}
+}
+
+func (r *Storage) ConvertToTable(ctx context.Context, object runtime.Object, tableOptions runtime.Object) (*metav1.Table, error) {
+	return r.convertor.ConvertToTable(ctx, object, tableOptions)
+}
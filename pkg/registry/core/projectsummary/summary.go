@@ -0,0 +1,278 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package projectsummary
+
+import (
+	"context"
+	"time"
+
+	uicoreapi "kubeops.dev/ui-server/apis/core/v1alpha1"
+	policyapi "kubeops.dev/ui-server/apis/policy/v1alpha1"
+	"kubeops.dev/ui-server/pkg/graph"
+	"kubeops.dev/ui-server/pkg/registry/core/podview"
+	resourcesummary "kubeops.dev/ui-server/pkg/registry/core/resourcesummary"
+	policyreports "kubeops.dev/ui-server/pkg/registry/policy/reports"
+	scannerreports "kubeops.dev/ui-server/pkg/registry/scanner/reports"
+	"kubeops.dev/ui-server/pkg/shared"
+
+	"golang.org/x/sync/errgroup"
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/klog/v2"
+	kmapi "kmodules.xyz/client-go/api/v1"
+	rscoreapi "kmodules.xyz/resource-metadata/apis/core/v1alpha1"
+	"kmodules.xyz/resource-metrics/api"
+	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// summaryWorkers is the number of kinds listed in parallel.
+const summaryWorkers = 8
+
+var (
+	podGK = schema.GroupKind{Kind: "Pod"}
+	pvcGR = schema.GroupResource{Resource: "persistentvolumeclaims"}
+)
+
+// summarize rolls up the objects in the namespaces of prj that u is allowed to get. When
+// clientOrgNs is set, only that namespace of the project is included. The policy
+// violations are the ones returned by listViolations.
+func (r *Storage) summarize(ctx context.Context, u user.Info, prj *rscoreapi.Project, clientOrgNs string, cmeta *kmapi.ClusterMetadata, violations []policyapi.StatusViolation, now time.Time) (*uicoreapi.ProjectSummary, error) {
+	namespaces := projectNamespaces(prj, clientOrgNs)
+	summary := r.newSummary(prj, namespaces, cmeta, now)
+	if len(namespaces) == 0 {
+		return summary, nil
+	}
+	nsSet := sets.New(namespaces...)
+
+	// the kinds registered with resource-metrics are counted, as in ResourceSummary
+	mappings, err := shared.RegisteredMappings(r.mapper, shared.NewGroupKindSelector(nil))
+	if err != nil {
+		return nil, err
+	}
+	kinds := make([][]unstructured.Unstructured, len(mappings))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(summaryWorkers)
+	for i, mapping := range mappings {
+		if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+			continue
+		}
+		g.Go(func() error {
+			items, err := r.allowed(gctx, u, mapping, nsSet)
+			if err != nil {
+				return err
+			}
+			kinds[i] = items
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	var pods []unstructured.Unstructured
+	for i, items := range kinds {
+		if len(items) == 0 {
+			continue
+		}
+		failing := countFailing(items)
+		summary.Spec.Resources = append(summary.Spec.Resources, uicoreapi.ProjectResourceCount{
+			APIType: *kmapi.NewResourceID(mappings[i]),
+			Count:   len(items),
+			Failing: failing,
+		})
+		summary.Spec.Failing += failing
+		if mappings[i].GroupVersionKind.GroupKind() == podGK {
+			pods = items
+		}
+	}
+
+	typedPods := make([]core.Pod, len(pods))
+	for i := range pods {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(pods[i].UnstructuredContent(), &typedPods[i]); err != nil {
+			return nil, err
+		}
+	}
+	claims, err := r.claims(ctx, u, nsSet)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := resourcesummary.Aggregate(pods)
+	if err != nil {
+		return nil, err
+	}
+	summary.Spec.TotalResource.Requests = rscoreapi.ConvertToStringQuantity(api.AddResourceList(totals.TotalRequests, storageRequests(claims)))
+	summary.Spec.TotalResource.Limits = rscoreapi.ConvertToStringQuantity(totals.TotalLimits)
+	summary.Spec.Usage = rscoreapi.ConvertToStringQuantity(podview.Usage(ctx, r.builder, typedPods, claims))
+
+	// CVEs and policy violations are left out of the summary when they can't be collected
+	if graph.ScannerInstalled.Load() && len(typedPods) > 0 {
+		if rpt, err := scannerreports.PodsReport(ctx, r.kc, typedPods); err != nil {
+			klog.ErrorS(err, "failed to collect CVEs", "project", prj.Name)
+		} else {
+			summary.Spec.CVEs = map[string]int{}
+			for severity, stats := range rpt.Vulnerabilities.Stats {
+				summary.Spec.CVEs[severity] = stats.Count
+			}
+		}
+	}
+	summary.Spec.PolicyViolations = r.policyViolations(ctx, u, nsSet, violations)
+	return summary, nil
+}
+
+// projectNamespaces returns the sorted namespaces of prj, restricted to clientOrgNs if set.
+func projectNamespaces(prj *rscoreapi.Project, clientOrgNs string) []string {
+	namespaces := sets.New(prj.Spec.Namespaces...)
+	if clientOrgNs != "" {
+		namespaces = namespaces.Intersection(sets.New(clientOrgNs))
+	}
+	return sets.List(namespaces)
+}
+
+// allowed returns the objects of a kind in the namespaces that u is allowed to get. They
+// are read from the cache one namespace at a time, using its namespace index.
+func (r *Storage) allowed(ctx context.Context, u user.Info, mapping *meta.RESTMapping, namespaces sets.Set[string]) ([]unstructured.Unstructured, error) {
+	var items []unstructured.Unstructured
+	for _, ns := range sets.List(namespaces) {
+		list, err := shared.ListUnstructured(ctx, r.cache, r.kc.Scheme(), mapping.GroupVersionKind, client.InNamespace(ns))
+		if err != nil {
+			if !meta.IsNoMatchError(err) && !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+			// the kind was removed since it was discovered
+			r.mapper.Reset()
+			return nil, nil
+		}
+		items = append(items, list...)
+	}
+
+	return resourcesummary.Allowed(ctx, r.a, authorizer.AttributesRecord{
+		User:            u,
+		Verb:            "get",
+		APIGroup:        mapping.Resource.Group,
+		Resource:        mapping.Resource.Resource,
+		ResourceRequest: true,
+	}, items)
+}
+
+// claims returns the PersistentVolumeClaims in the namespaces that u is allowed to get.
+func (r *Storage) claims(ctx context.Context, u user.Info, namespaces sets.Set[string]) ([]core.PersistentVolumeClaim, error) {
+	var list core.PersistentVolumeClaimList
+	if err := r.cache.List(ctx, &list); err != nil {
+		return nil, err
+	}
+	claims := make([]core.PersistentVolumeClaim, 0, len(list.Items))
+	for _, pvc := range list.Items {
+		if namespaces.Has(pvc.Namespace) && shared.CanGet(ctx, r.a, u, pvcGR, &pvc) {
+			claims = append(claims, pvc)
+		}
+	}
+	return claims, nil
+}
+
+// listViolations returns the violations of all the Gatekeeper constraints. It is called
+// once per request and shared by the summaries of the projects. Violations are left out
+// of the summaries when they can't be collected.
+func (r *Storage) listViolations(ctx context.Context) []policyapi.StatusViolation {
+	if !graph.OPAInstalled.Load() {
+		return nil
+	}
+	templates, err := policyreports.ListTemplates(ctx, r.cache)
+	if err != nil {
+		klog.ErrorS(err, "failed to collect policy violations")
+		return nil
+	}
+	var result []policyapi.StatusViolation
+	for _, template := range templates.Items {
+		kind, _, err := unstructured.NestedString(template.UnstructuredContent(), "spec", "crd", "spec", "names", "kind")
+		if err != nil {
+			klog.ErrorS(err, "failed to collect policy violations")
+			return nil
+		}
+		constraints, err := policyreports.ListConstraints(ctx, r.cache, kind)
+		if err != nil {
+			klog.ErrorS(err, "failed to collect policy violations")
+			return nil
+		}
+		for _, constraint := range constraints.Items {
+			violations, err := policyreports.GetViolationsOfConstraint(constraint)
+			if err != nil {
+				klog.ErrorS(err, "failed to collect policy violations")
+				return nil
+			}
+			result = append(result, violations...)
+		}
+	}
+	return result
+}
+
+// policyViolations counts the violations of the objects in the namespaces that u is
+// allowed to get.
+func (r *Storage) policyViolations(ctx context.Context, u user.Info, namespaces sets.Set[string], violations []policyapi.StatusViolation) int {
+	var n int
+	for _, v := range violations {
+		if !namespaces.Has(v.Namespace) {
+			continue
+		}
+		mapping, err := r.mapper.RESTMapping(schema.GroupKind{Group: v.Group, Kind: v.Kind}, v.Version)
+		if err != nil {
+			continue
+		}
+		if shared.CanGet(ctx, r.a, u, mapping.Resource.GroupResource(), &metav1.ObjectMeta{Namespace: v.Namespace, Name: v.Name}) {
+			n++
+		}
+	}
+	return n
+}
+
+// countFailing returns the number of items whose kstatus is Failed.
+func countFailing(items []unstructured.Unstructured) int {
+	var n int
+	for i := range items {
+		s, err := status.Compute(&items[i])
+		if err == nil && s.Status == status.FailedStatus {
+			n++
+		}
+	}
+	return n
+}
+
+// storageRequests returns the total storage requested by claims.
+func storageRequests(claims []core.PersistentVolumeClaim) core.ResourceList {
+	total := *resource.NewQuantity(0, resource.BinarySI)
+	var found bool
+	for _, pvc := range claims {
+		if q, ok := pvc.Spec.Resources.Requests[core.ResourceStorage]; ok {
+			total.Add(q)
+			found = true
+		}
+	}
+	if !found {
+		return nil
+	}
+	return core.ResourceList{core.ResourceStorage: total}
+}
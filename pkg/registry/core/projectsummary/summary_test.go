@@ -0,0 +1,131 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package projectsummary
+
+import (
+	"context"
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	rscoreapi "kmodules.xyz/resource-metadata/apis/core/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestProjectNamespaces(t *testing.T) {
+	prj := &rscoreapi.Project{
+		Spec: rscoreapi.ProjectSpec{Namespaces: []string{"web", "db", "web"}},
+	}
+	if got := projectNamespaces(prj, ""); len(got) != 2 || got[0] != "db" || got[1] != "web" {
+		t.Errorf("projectNamespaces() = %v, want [db web]", got)
+	}
+	if got := projectNamespaces(prj, "db"); len(got) != 1 || got[0] != "db" {
+		t.Errorf("projectNamespaces() for client org = %v, want [db]", got)
+	}
+	if got := projectNamespaces(prj, "other"); len(got) != 0 {
+		t.Errorf("projectNamespaces() for client org outside the project = %v, want none", got)
+	}
+}
+
+func pod(ns, name string, crashLooping bool) unstructured.Unstructured {
+	reason := "ContainerCreating"
+	if crashLooping {
+		reason = "CrashLoopBackOff"
+	}
+	return unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]any{
+			"namespace":  ns,
+			"name":       name,
+			"generation": int64(1),
+		},
+		"status": map[string]any{
+			"phase": "Running",
+			"containerStatuses": []any{
+				map[string]any{
+					"name":  "app",
+					"state": map[string]any{"waiting": map[string]any{"reason": reason}},
+				},
+			},
+		},
+	}}
+}
+
+type allowAll struct{}
+
+func (allowAll) Authorize(_ context.Context, _ authorizer.Attributes) (authorizer.Decision, string, error) {
+	return authorizer.DecisionAllow, "", nil
+}
+
+func TestCountFailing(t *testing.T) {
+	var objs []client.Object
+	for _, p := range []unstructured.Unstructured{
+		pod("web", "starting", false),
+		pod("web", "crashing", true),
+		pod("other", "crashing", true),
+	} {
+		objs = append(objs, &p)
+	}
+	kc := fake.NewClientBuilder().WithObjects(objs...).Build()
+	r := &Storage{
+		kc:    kc,
+		a:     allowAll{},
+		cache: kc,
+	}
+	mapping := &meta.RESTMapping{
+		Resource:         core.SchemeGroupVersion.WithResource("pods"),
+		GroupVersionKind: core.SchemeGroupVersion.WithKind("Pod"),
+		Scope:            meta.RESTScopeNamespace,
+	}
+
+	items, err := r.allowed(context.TODO(), &user.DefaultInfo{Name: "admin"}, mapping, sets.New("web"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("allowed() returned %d items, want 2", len(items))
+	}
+	if n := countFailing(items); n != 1 {
+		t.Errorf("countFailing() = %d, want 1", n)
+	}
+}
+
+func TestStorageRequests(t *testing.T) {
+	claim := func(size string) core.PersistentVolumeClaim {
+		pvc := core.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "db", Name: "data"}}
+		if size != "" {
+			pvc.Spec.Resources.Requests = core.ResourceList{core.ResourceStorage: resource.MustParse(size)}
+		}
+		return pvc
+	}
+
+	rl := storageRequests([]core.PersistentVolumeClaim{claim("1Gi"), claim("512Mi"), claim("")})
+	if q := rl[core.ResourceStorage]; q.Cmp(resource.MustParse("1536Mi")) != 0 {
+		t.Errorf("storage = %s, want 1536Mi", q.String())
+	}
+	if rl := storageRequests(nil); rl != nil {
+		t.Errorf("expected no storage requests, found %v", rl)
+	}
+}
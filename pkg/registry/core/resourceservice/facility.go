@@ -0,0 +1,432 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceservice
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	uicoreapi "kubeops.dev/ui-server/apis/core/v1alpha1"
+	"kubeops.dev/ui-server/pkg/graph"
+	"kubeops.dev/ui-server/pkg/shared"
+
+	"github.com/pkg/errors"
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	kmapi "kmodules.xyz/client-go/api/v1"
+	rscoreapi "kmodules.xyz/resource-metadata/apis/core/v1alpha1"
+	sharedapi "kmodules.xyz/resource-metadata/apis/shared"
+	resourcemetrics "kmodules.xyz/resource-metrics"
+	"kmodules.xyz/resource-metrics/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// FacilityDetectorsConfigMap is the ConfigMap in the namespace of the server whose
+	// detectors.yaml key holds a list of FacilityDetectors. They are merged by name with
+	// the built-in detectors.
+	FacilityDetectorsConfigMap = "ui-server-facility-detectors"
+	keyFacilityDetectors       = "detectors.yaml"
+)
+
+const monitoredByQuery = `query Find($src: String!, $targetGroup: String!, $targetKind: String!) {
+  find(oid: $src) {
+    exposed_by(group: "", kind: "Service") {
+      refs: monitored_by(group: $targetGroup, kind: $targetKind) {
+        namespace
+        name
+      }
+    }
+  }
+}`
+
+func graphLocator(group, kind string, label kmapi.EdgeLabel) uicoreapi.FacilityLocator {
+	return uicoreapi.FacilityLocator{
+		ResourceLocator: sharedapi.ResourceLocator{
+			Ref:   metav1.GroupKind{Group: group, Kind: kind},
+			Query: sharedapi.ResourceQuery{Type: sharedapi.GraphQLQuery, ByLabel: label},
+		},
+	}
+}
+
+func rawLocator(group, kind, query string) uicoreapi.FacilityLocator {
+	return uicoreapi.FacilityLocator{
+		ResourceLocator: sharedapi.ResourceLocator{
+			Ref:   metav1.GroupKind{Group: group, Kind: kind},
+			Query: sharedapi.ResourceQuery{Type: sharedapi.GraphQLQuery, Raw: query},
+		},
+	}
+}
+
+func selectorLocator(group, kind, path string) uicoreapi.FacilityLocator {
+	return uicoreapi.FacilityLocator{
+		ResourceLocator: sharedapi.ResourceLocator{
+			Ref: metav1.GroupKind{Group: group, Kind: kind},
+		},
+		SelectorPath: path,
+	}
+}
+
+// builtinFacilityDetectors are the detectors used unless they are overridden by the
+// FacilityDetectorsConfigMap.
+var builtinFacilityDetectors = []uicoreapi.FacilityDetector{
+	{
+		Name: uicoreapi.FacilityExposed,
+		Locators: []uicoreapi.FacilityLocator{
+			func() uicoreapi.FacilityLocator {
+				l := graphLocator("", "Service", kmapi.EdgeLabelExposedBy)
+				l.Condition = `{{ has .spec.type (list "LoadBalancer" "NodePort" "ExternalName") }}`
+				return l
+			}(),
+		},
+	},
+	{
+		Name:      uicoreapi.FacilityTLS,
+		Evaluator: evaluatorUsesTLS,
+	},
+	{
+		Name: uicoreapi.FacilityBackup,
+		Locators: []uicoreapi.FacilityLocator{
+			rawLocator("stash.appscode.com", "BackupSession", `query Find($src: String!, $targetGroup: String!, $targetKind: String!) {
+  find(oid: $src) {
+    backup_via(group: "stash.appscode.com", kind: "BackupConfiguration") {
+      refs: offshoot(group: $targetGroup, kind: $targetKind) {
+        namespace
+        name
+      }
+    }
+  }
+}`),
+			graphLocator("core.kubestash.com", "BackupConfiguration", kmapi.EdgeLabelBackupVia),
+		},
+	},
+	{
+		Name: uicoreapi.FacilityMonitoring,
+		Locators: []uicoreapi.FacilityLocator{
+			rawLocator("monitoring.coreos.com", "ServiceMonitor", monitoredByQuery),
+			rawLocator("monitoring.coreos.com", "PodMonitor", monitoredByQuery),
+		},
+	},
+	{
+		Name: "networkPolicy",
+		Locators: []uicoreapi.FacilityLocator{
+			selectorLocator("networking.k8s.io", "NetworkPolicy", "spec.podSelector"),
+		},
+	},
+	{
+		Name: "podDisruptionBudget",
+		Locators: []uicoreapi.FacilityLocator{
+			selectorLocator("policy", "PodDisruptionBudget", "spec.selector"),
+		},
+	},
+	{
+		Name: "autoscaling",
+		Locators: []uicoreapi.FacilityLocator{
+			graphLocator("autoscaling", "HorizontalPodAutoscaler", kmapi.EdgeLabelScaledBy),
+			graphLocator("autoscaling.k8s.io", "VerticalPodAutoscaler", kmapi.EdgeLabelScaledBy),
+			graphLocator("keda.sh", "ScaledObject", kmapi.EdgeLabelScaledBy),
+		},
+	},
+	{
+		Name: "certificates",
+		Locators: []uicoreapi.FacilityLocator{
+			graphLocator("cert-manager.io", "Certificate", kmapi.EdgeLabelOffshoot),
+		},
+	},
+	{
+		Name: "externalSecrets",
+		Locators: []uicoreapi.FacilityLocator{
+			rawLocator("external-secrets.io", "ExternalSecret", `query Find($src: String!, $targetGroup: String!, $targetKind: String!) {
+  find(oid: $src) {
+    auth_secret(group: "", kind: "Secret") {
+      refs: offshoot(group: $targetGroup, kind: $targetKind) {
+        namespace
+        name
+      }
+    }
+  }
+}`),
+		},
+	},
+}
+
+const evaluatorUsesTLS = "UsesTLS"
+
+// facilityEvaluators are the built-in checks a FacilityDetector can name as its evaluator.
+var facilityEvaluators = map[string]func(content map[string]any) (bool, error){
+	evaluatorUsesTLS: func(content map[string]any) (bool, error) {
+		yes, err := resourcemetrics.UsesTLS(content)
+		if errors.Is(err, api.ErrMissingRefObject) {
+			return false, nil
+		}
+		return yes, err
+	},
+}
+
+// detectorCache holds the detectors parsed from a revision of the FacilityDetectorsConfigMap.
+type detectorCache struct {
+	mu              sync.Mutex
+	resourceVersion string
+	detectors       []uicoreapi.FacilityDetector
+}
+
+// facilityDetectors returns the built-in detectors merged with the ones configured in the
+// FacilityDetectorsConfigMap. The ConfigMap is read from the cache of the client, and only
+// parsed again when its resource version changes. An invalid ConfigMap is logged and
+// ignored.
+func (r *Storage) facilityDetectors(ctx context.Context) []uicoreapi.FacilityDetector {
+	if r.namespace == "" {
+		return builtinFacilityDetectors
+	}
+	var cm core.ConfigMap
+	err := r.kc.Get(ctx, client.ObjectKey{Namespace: r.namespace, Name: FacilityDetectorsConfigMap}, &cm)
+	if apierrors.IsNotFound(err) {
+		return builtinFacilityDetectors
+	} else if err != nil {
+		klog.ErrorS(err, "failed to read facility detectors", "configmap", client.ObjectKeyFromObject(&cm))
+		return builtinFacilityDetectors
+	}
+
+	r.detectors.mu.Lock()
+	defer r.detectors.mu.Unlock()
+	if r.detectors.detectors != nil && r.detectors.resourceVersion == cm.ResourceVersion {
+		return r.detectors.detectors
+	}
+
+	var custom []uicoreapi.FacilityDetector
+	detectors := builtinFacilityDetectors
+	if err := yaml.Unmarshal([]byte(cm.Data[keyFacilityDetectors]), &custom); err != nil {
+		klog.ErrorS(err, "failed to parse facility detectors", "namespace", r.namespace, "name", FacilityDetectorsConfigMap)
+	} else {
+		detectors = mergeFacilityDetectors(builtinFacilityDetectors, custom)
+	}
+	r.detectors.resourceVersion = cm.ResourceVersion
+	r.detectors.detectors = detectors
+	return detectors
+}
+
+// mergeFacilityDetectors replaces the detectors in base with the custom detectors of the
+// same name, removes the disabled ones and appends the rest.
+func mergeFacilityDetectors(base, custom []uicoreapi.FacilityDetector) []uicoreapi.FacilityDetector {
+	out := slices.Clone(base)
+	for _, d := range custom {
+		i := slices.IndexFunc(out, func(x uicoreapi.FacilityDetector) bool {
+			return x.Name == d.Name
+		})
+		switch {
+		case d.Disabled && i >= 0:
+			out = slices.Delete(out, i, i+1)
+		case d.Disabled:
+		case i >= 0:
+			out[i] = d
+		default:
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+func appliesTo(d uicoreapi.FacilityDetector, gk schema.GroupKind) bool {
+	if len(d.Targets) == 0 {
+		return true
+	}
+	for _, t := range d.Targets {
+		if t.Group == gk.Group && (t.Kind == "" || t.Kind == gk.Kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// detectFacilities runs the detectors that apply to the kind of item. The results are
+// keyed by the detector name.
+func (r *Storage) detectFacilities(ctx context.Context, item *unstructured.Unstructured, oid kmapi.OID) (map[string]rscoreapi.GenericResourceServiceFacilitator, error) {
+	gk := item.GroupVersionKind().GroupKind()
+	result := map[string]rscoreapi.GenericResourceServiceFacilitator{}
+	for _, d := range r.facilityDetectors(ctx) {
+		if !appliesTo(d, gk) {
+			continue
+		}
+		f, err := r.detectFacility(ctx, d, item, oid)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to detect facility %s", d.Name)
+		}
+		result[d.Name] = f
+	}
+	return result, nil
+}
+
+func (r *Storage) detectFacility(ctx context.Context, d uicoreapi.FacilityDetector, item *unstructured.Unstructured, oid kmapi.OID) (rscoreapi.GenericResourceServiceFacilitator, error) {
+	result := rscoreapi.GenericResourceServiceFacilitator{
+		Usage: rscoreapi.FacilityUnknown,
+	}
+	if d.Evaluator != "" {
+		fn, ok := facilityEvaluators[d.Evaluator]
+		if !ok {
+			return result, errors.Errorf("unknown evaluator %s", d.Evaluator)
+		}
+		yes, err := fn(item.UnstructuredContent())
+		if err != nil {
+			return result, err
+		}
+		result.Usage = rscoreapi.FacilityUnused
+		if yes {
+			result.Usage = rscoreapi.FacilityUsed
+		}
+		return result, nil
+	}
+
+	for _, loc := range d.Locators {
+		rid, refs, err := r.locate(ctx, loc, item, oid)
+		if meta.IsNoMatchError(err) {
+			// the kind of the locator is not served by the cluster
+			continue
+		} else if err != nil {
+			return result, err
+		}
+		if len(refs) > 0 {
+			result.Usage = rscoreapi.FacilityUsed
+			result.Resource = rid
+			result.Refs = refs
+			return result, nil
+		}
+		result.Usage = rscoreapi.FacilityUnused
+	}
+	return result, nil
+}
+
+func (r *Storage) locate(ctx context.Context, loc uicoreapi.FacilityLocator, item *unstructured.Unstructured, oid kmapi.OID) (*kmapi.ResourceID, []kmapi.ObjectReference, error) {
+	if loc.SelectorPath != "" {
+		return r.locateBySelector(ctx, loc, item, oid)
+	}
+	if loc.Condition == "" {
+		return graph.ExecRawQuery(ctx, r.kc, oid, loc.ResourceLocator)
+	}
+
+	rid, objs, err := graph.ExecQuery(ctx, r.kc, oid, loc.ResourceLocator)
+	if err != nil {
+		return nil, nil, err
+	}
+	var refs []kmapi.ObjectReference
+	for _, obj := range objs {
+		ok, err := matchesCondition(loc.Condition, obj.UnstructuredContent())
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			refs = append(refs, kmapi.ObjectReference{Namespace: obj.GetNamespace(), Name: obj.GetName()})
+		}
+	}
+	return rid, refs, nil
+}
+
+// locateBySelector finds the objects in the namespace of item whose label selector at the
+// selector path of the locator matches a pod of item.
+func (r *Storage) locateBySelector(ctx context.Context, loc uicoreapi.FacilityLocator, item *unstructured.Unstructured, oid kmapi.OID) (*kmapi.ResourceID, []kmapi.ObjectReference, error) {
+	mapping, err := r.kc.RESTMapper().RESTMapping(schema.GroupKind{Group: loc.Ref.Group, Kind: loc.Ref.Kind})
+	if err != nil {
+		return nil, nil, err
+	}
+	rid := kmapi.NewResourceID(mapping)
+
+	var pods []labels.Set
+	if item.GroupVersionKind().GroupKind() == (schema.GroupKind{Kind: "Pod"}) {
+		pods = append(pods, item.GetLabels())
+	} else {
+		_, objs, err := graph.ExecQuery(ctx, r.kc, oid, sharedapi.ResourceLocator{
+			Ref:   metav1.GroupKind{Group: "", Kind: "Pod"},
+			Query: sharedapi.ResourceQuery{Type: sharedapi.GraphQLQuery, ByLabel: kmapi.EdgeLabelOffshoot},
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, pod := range objs {
+			pods = append(pods, pod.GetLabels())
+		}
+	}
+	if len(pods) == 0 {
+		return rid, nil, nil
+	}
+
+	var list unstructured.UnstructuredList
+	list.SetGroupVersionKind(mapping.GroupVersionKind)
+	if err := r.kc.List(ctx, &list, client.InNamespace(item.GetNamespace())); err != nil {
+		return nil, nil, err
+	}
+	var refs []kmapi.ObjectReference
+	for _, obj := range list.Items {
+		ok, err := selectsAny(obj.UnstructuredContent(), loc.SelectorPath, pods)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to read selector of %s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+		}
+		if ok && loc.Condition != "" {
+			ok, err = matchesCondition(loc.Condition, obj.UnstructuredContent())
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		if ok {
+			refs = append(refs, kmapi.ObjectReference{Namespace: obj.GetNamespace(), Name: obj.GetName()})
+		}
+	}
+	return rid, refs, nil
+}
+
+// selectsAny reports whether the label selector at path selects any of the label sets.
+// An empty selector selects everything, while a missing one selects nothing.
+func selectsAny(content map[string]any, path string, sets []labels.Set) (bool, error) {
+	m, found, err := unstructured.NestedMap(content, strings.Split(path, ".")...)
+	if err != nil || !found {
+		return false, err
+	}
+	var ls metav1.LabelSelector
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, &ls); err != nil {
+		return false, err
+	}
+	sel, err := metav1.LabelSelectorAsSelector(&ls)
+	if err != nil {
+		return false, err
+	}
+	for _, set := range sets {
+		if sel.Matches(set) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func matchesCondition(condition string, content map[string]any) (bool, error) {
+	buf := shared.BufferPool.Get().(*bytes.Buffer)
+	defer shared.BufferPool.Put(buf)
+
+	result, err := shared.RenderTemplate(condition, content, buf)
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(strings.TrimSpace(result), "true"), nil
+}
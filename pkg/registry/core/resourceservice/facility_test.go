@@ -0,0 +1,276 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceservice
+
+import (
+	"context"
+	"testing"
+
+	uicoreapi "kubeops.dev/ui-server/apis/core/v1alpha1"
+
+	core "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	rscoreapi "kmodules.xyz/resource-metadata/apis/core/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+)
+
+func TestMergeFacilityDetectors(t *testing.T) {
+	var custom []uicoreapi.FacilityDetector
+	err := yaml.Unmarshal([]byte(`
+- name: tls
+  disabled: true
+- name: backup
+  locators:
+  - ref:
+      group: core.kubestash.com
+      kind: BackupConfiguration
+    query:
+      type: GraphQL
+      byLabel: backup_via
+- name: ingress
+  targets:
+  - group: kubedb.com
+  locators:
+  - ref:
+      group: networking.k8s.io
+      kind: Ingress
+    query:
+      type: GraphQL
+      byLabel: exposed_by
+`), &custom)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := mergeFacilityDetectors(builtinFacilityDetectors, custom)
+	if len(out) != len(builtinFacilityDetectors) {
+		t.Fatalf("expected %d detectors, got %d", len(builtinFacilityDetectors), len(out))
+	}
+	byName := map[string]uicoreapi.FacilityDetector{}
+	for _, d := range out {
+		byName[d.Name] = d
+	}
+	if _, ok := byName[uicoreapi.FacilityTLS]; ok {
+		t.Error("expected tls detector to be disabled")
+	}
+	if n := len(byName[uicoreapi.FacilityBackup].Locators); n != 1 {
+		t.Errorf("expected backup detector to be replaced, got %d locators", n)
+	}
+	if out[len(out)-1].Name != "ingress" {
+		t.Errorf("expected ingress detector to be appended, got %s", out[len(out)-1].Name)
+	}
+	if len(builtinFacilityDetectors[2].Locators) != 2 {
+		t.Error("expected builtin detectors to be unchanged")
+	}
+}
+
+func TestAppliesTo(t *testing.T) {
+	d := uicoreapi.FacilityDetector{
+		Targets: []metav1.GroupKind{
+			{Group: "kubedb.com"},
+			{Group: "apps", Kind: "StatefulSet"},
+		},
+	}
+	cases := map[schema.GroupKind]bool{
+		{Group: "kubedb.com", Kind: "Postgres"}:  true,
+		{Group: "apps", Kind: "StatefulSet"}:     true,
+		{Group: "apps", Kind: "Deployment"}:      false,
+		{Group: "", Kind: "Pod"}:                 false,
+		{Group: "kubedb.com", Kind: "MongoDB"}:   true,
+		{Group: "ops.kubedb.com", Kind: "Other"}: false,
+	}
+	for gk, expected := range cases {
+		if got := appliesTo(d, gk); got != expected {
+			t.Errorf("%s: expected %v, got %v", gk, expected, got)
+		}
+	}
+	if !appliesTo(uicoreapi.FacilityDetector{}, schema.GroupKind{Kind: "Pod"}) {
+		t.Error("expected a detector without targets to apply to every kind")
+	}
+}
+
+func TestSelectsAny(t *testing.T) {
+	pods := []labels.Set{{"app": "db", "role": "primary"}}
+	cases := []struct {
+		name     string
+		content  map[string]any
+		expected bool
+	}{
+		{
+			name:     "missing selector",
+			content:  map[string]any{"spec": map[string]any{}},
+			expected: false,
+		},
+		{
+			name:     "empty selector",
+			content:  map[string]any{"spec": map[string]any{"podSelector": map[string]any{}}},
+			expected: true,
+		},
+		{
+			name: "matching selector",
+			content: map[string]any{"spec": map[string]any{"podSelector": map[string]any{
+				"matchLabels": map[string]any{"app": "db"},
+			}}},
+			expected: true,
+		},
+		{
+			name: "other selector",
+			content: map[string]any{"spec": map[string]any{"podSelector": map[string]any{
+				"matchLabels": map[string]any{"app": "web"},
+			}}},
+			expected: false,
+		},
+	}
+	for _, c := range cases {
+		got, err := selectsAny(c.content, "spec.podSelector", pods)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if got != c.expected {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, got)
+		}
+	}
+}
+
+func TestMatchesCondition(t *testing.T) {
+	exposed := builtinFacilityDetectors[0].Locators[0].Condition
+	service := func(typ string) map[string]any {
+		spec := map[string]any{}
+		if typ != "" {
+			spec["type"] = typ
+		}
+		return map[string]any{"spec": spec}
+	}
+	cases := []struct {
+		name      string
+		condition string
+		content   map[string]any
+		expected  bool
+		wantErr   bool
+	}{
+		{name: "exposed LoadBalancer", condition: exposed, content: service("LoadBalancer"), expected: true},
+		{name: "exposed NodePort", condition: exposed, content: service("NodePort"), expected: true},
+		{name: "exposed ExternalName", condition: exposed, content: service("ExternalName"), expected: true},
+		{name: "exposed ClusterIP", condition: exposed, content: service("ClusterIP"), expected: false},
+		{name: "exposed without type", condition: exposed, content: service(""), expected: false},
+		{name: "case and space insensitive", condition: ` True `, content: nil, expected: true},
+		{name: "not true", condition: `yes`, content: nil, expected: false},
+		{name: "invalid template", condition: `{{ .spec.type `, content: service("NodePort"), wantErr: true},
+	}
+	for _, c := range cases {
+		got, err := matchesCondition(c.condition, c.content)
+		if (err != nil) != c.wantErr {
+			t.Fatalf("%s: unexpected error %v", c.name, err)
+		}
+		if got != c.expected {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, got)
+		}
+	}
+}
+
+func TestDetectFacility(t *testing.T) {
+	pod := &unstructured.Unstructured{}
+	pod.SetGroupVersionKind(core.SchemeGroupVersion.WithKind("Pod"))
+	pod.SetNamespace("demo")
+	pod.SetName("db-0")
+	pod.SetLabels(map[string]string{"app": "db"})
+
+	policy := func(name string, sel map[string]string) *networking.NetworkPolicy {
+		return &networking.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "demo", Name: name},
+			Spec:       networking.NetworkPolicySpec{PodSelector: metav1.LabelSelector{MatchLabels: sel}},
+		}
+	}
+	networkPolicy := uicoreapi.FacilityDetector{
+		Name:     "networkPolicy",
+		Locators: []uicoreapi.FacilityLocator{selectorLocator("networking.k8s.io", "NetworkPolicy", "spec.podSelector")},
+	}
+	unserved := uicoreapi.FacilityDetector{
+		Name:     "monitoring",
+		Locators: []uicoreapi.FacilityLocator{selectorLocator("monitoring.coreos.com", "PodMonitor", "spec.selector")},
+	}
+
+	cases := []struct {
+		name     string
+		detector uicoreapi.FacilityDetector
+		objects  []runtime.Object
+		expected rscoreapi.FacilityUsage
+		refs     int
+		wantErr  bool
+	}{
+		{name: "kind not served", detector: unserved, expected: rscoreapi.FacilityUnknown},
+		{name: "no match", detector: networkPolicy, objects: []runtime.Object{policy("web", map[string]string{"app": "web"})}, expected: rscoreapi.FacilityUnused},
+		{name: "match", detector: networkPolicy, objects: []runtime.Object{policy("db", map[string]string{"app": "db"}), policy("web", map[string]string{"app": "web"})}, expected: rscoreapi.FacilityUsed, refs: 1},
+		{
+			name: "unserved then matching locator",
+			detector: uicoreapi.FacilityDetector{
+				Name:     "mixed",
+				Locators: []uicoreapi.FacilityLocator{unserved.Locators[0], networkPolicy.Locators[0]},
+			},
+			objects:  []runtime.Object{policy("db", map[string]string{"app": "db"})},
+			expected: rscoreapi.FacilityUsed,
+			refs:     1,
+		},
+		{name: "unknown evaluator", detector: uicoreapi.FacilityDetector{Name: "x", Evaluator: "Other"}, expected: rscoreapi.FacilityUnknown, wantErr: true},
+	}
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{networking.SchemeGroupVersion})
+	mapper.Add(networking.SchemeGroupVersion.WithKind("NetworkPolicy"), meta.RESTScopeNamespace)
+	for _, c := range cases {
+		kc := fake.NewClientBuilder().WithRESTMapper(mapper).WithRuntimeObjects(c.objects...).Build()
+		r := &Storage{kc: kc}
+		got, err := r.detectFacility(context.Background(), c.detector, pod, "")
+		if (err != nil) != c.wantErr {
+			t.Fatalf("%s: unexpected error %v", c.name, err)
+		}
+		if got.Usage != c.expected || len(got.Refs) != c.refs {
+			t.Errorf("%s: expected %s with %d refs, got %+v", c.name, c.expected, c.refs, got)
+		}
+	}
+}
+
+func TestFacilityDetectorsCache(t *testing.T) {
+	cm := &core.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kubeops", Name: FacilityDetectorsConfigMap},
+		Data:       map[string]string{keyFacilityDetectors: "- name: tls\n  disabled: true\n"},
+	}
+	kc := fake.NewClientBuilder().WithObjects(cm).Build()
+	r := &Storage{kc: kc, namespace: "kubeops"}
+
+	ctx := context.Background()
+	first := r.facilityDetectors(ctx)
+	if len(first) != len(builtinFacilityDetectors)-1 {
+		t.Fatalf("expected the tls detector to be disabled, got %d detectors", len(first))
+	}
+	if again := r.facilityDetectors(ctx); &again[0] != &first[0] {
+		t.Error("expected the parsed detectors to be reused")
+	}
+
+	cm.Data[keyFacilityDetectors] = "[]"
+	if err := kc.Update(ctx, cm); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.facilityDetectors(ctx); len(got) != len(builtinFacilityDetectors) {
+		t.Errorf("expected the updated ConfigMap to be parsed, got %d detectors", len(got))
+	}
+}
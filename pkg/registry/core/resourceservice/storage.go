@@ -24,12 +24,13 @@ import (
 	"sort"
 	"strings"
 
+	uicoreapi "kubeops.dev/ui-server/apis/core/v1alpha1"
 	"kubeops.dev/ui-server/pkg/graph"
+	"kubeops.dev/ui-server/pkg/health"
 	"kubeops.dev/ui-server/pkg/shared"
 
 	"github.com/pkg/errors"
 	catalogapi "go.bytebuilders.dev/catalog/api/catalog/v1alpha1"
-	core "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/internalversion"
@@ -40,9 +41,7 @@ import (
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 	apirequest "k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/apiserver/pkg/registry/rest"
-	"k8s.io/client-go/discovery"
-	"k8s.io/client-go/discovery/cached/memory"
-	"k8s.io/client-go/restmapper"
+	"k8s.io/klog/v2"
 	"kmodules.xyz/apiversion"
 	kmapi "kmodules.xyz/client-go/api/v1"
 	clustermeta "kmodules.xyz/client-go/cluster"
@@ -52,18 +51,23 @@ import (
 	rsapi "kmodules.xyz/resource-metadata/apis/meta/v1alpha1"
 	sharedapi "kmodules.xyz/resource-metadata/apis/shared"
 	"kmodules.xyz/resource-metadata/hub/resourcedescriptors"
-	resourcemetrics "kmodules.xyz/resource-metrics"
-	"kmodules.xyz/resource-metrics/api"
 	"sigs.k8s.io/cli-utils/pkg/kstatus/status"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type Storage struct {
 	kc        client.Client
-	dc        discovery.DiscoveryInterface
+	mapper    *shared.RESTMapper
 	clusterID string
 	a         authorizer.Authorizer
-	convertor rest.TableConvertor
+	informers cache.Informers
+	// watchAllKinds allows watches without a group-kind selector.
+	watchAllKinds bool
+	namespace     string
+	detectors     detectorCache
+	scores        *health.Collector
+	convertor     rest.TableConvertor
 }
 
 var (
@@ -72,15 +76,20 @@ var (
 	_ rest.Storage                  = &Storage{}
 	_ rest.Getter                   = &Storage{}
 	_ rest.Lister                   = &Storage{}
+	_ rest.Watcher                  = &Storage{}
 	_ rest.SingularNameProvider     = &Storage{}
 )
 
-func NewStorage(kc client.Client, dc discovery.DiscoveryInterface, clusterID string, a authorizer.Authorizer) *Storage {
+func NewStorage(kc client.Client, mapper *shared.RESTMapper, clusterID string, a authorizer.Authorizer, informers cache.Informers, watchAllKinds bool, namespace string, scores *health.Collector) *Storage {
 	return &Storage{
-		kc:        kc,
-		dc:        dc,
-		clusterID: clusterID,
-		a:         a,
+		kc:            kc,
+		mapper:        mapper,
+		clusterID:     clusterID,
+		a:             a,
+		informers:     informers,
+		watchAllKinds: watchAllKinds,
+		namespace:     namespace,
+		scores:        scores,
 		convertor: rest.NewDefaultTableConvertor(schema.GroupResource{
 			Group:    rscoreapi.GroupName,
 			Resource: rscoreapi.ResourceGenericResourceServices,
@@ -190,17 +199,7 @@ func (r *Storage) List(ctx context.Context, options *internalversion.ListOptions
 		return nil, apierrors.NewInternalError(err)
 	}
 
-	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(r.dc))
-	gvks := make(map[schema.GroupKind]string)
-	for _, gvk := range api.RegisteredTypes() {
-		if !selector.Matches(gvk.GroupKind()) {
-			continue
-		}
-		gk := gvk.GroupKind()
-		if v, exists := gvks[gk]; !exists || apiversion.MustCompare(v, gvk.Version) < 0 {
-			gvks[gk] = gvk.Version
-		}
-	}
+	gvks := shared.RegisteredKinds(selector)
 
 	items := make([]rscoreapi.GenericResourceService, 0)
 	for gk, v := range gvks {
@@ -208,7 +207,7 @@ func (r *Storage) List(ctx context.Context, options *internalversion.ListOptions
 			continue
 		}
 
-		mapping, err := mapper.RESTMapping(gk, v)
+		mapping, err := r.mapper.RESTMapping(gk, v)
 		if meta.IsNoMatchError(err) {
 			continue
 		} else if err != nil {
@@ -285,7 +284,75 @@ func (r *Storage) ConvertToTable(ctx context.Context, object runtime.Object, tab
 	return r.convertor.ConvertToTable(ctx, object, tableOptions)
 }
 
+// HealthTargets lists the resources of the registered kinds in all namespaces for the
+// health.Collector. It implements health.TargetLister. A kind or a resource that fails is
+// logged and skipped, so that the rest are still scored.
+func (r *Storage) HealthTargets(ctx context.Context) ([]health.Target, error) {
+	cmeta, err := clustermeta.ClusterMetadata(r.kc)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []health.Target
+	for gk, v := range shared.RegisteredKinds(shared.NewGroupKindSelector(nil)) {
+		mapping, err := r.mapper.RESTMapping(gk, v)
+		if meta.IsNoMatchError(err) {
+			continue
+		} else if err != nil {
+			klog.ErrorS(err, "failed to map kind for health scores", "kind", gk)
+			continue
+		}
+		apiType := kmapi.NewResourceID(mapping)
+
+		var list unstructured.UnstructuredList
+		list.SetGroupVersionKind(mapping.GroupVersionKind)
+		if err := r.kc.List(ctx, &list); err != nil {
+			klog.ErrorS(err, "failed to list resources for health scores", "kind", gk)
+			continue
+		}
+		for _, item := range list.Items {
+			_, in, err := r.convert(ctx, item, apiType, cmeta)
+			if err != nil {
+				klog.ErrorS(err, "failed to convert resource for health scores", "kind", gk, "namespace", item.GetNamespace(), "name", item.GetName())
+				continue
+			}
+			targets = append(targets, health.Target{
+				ObjectInfo: kmapi.ObjectInfo{
+					Resource: *apiType,
+					Ref:      kmapi.ObjectReference{Namespace: item.GetNamespace(), Name: item.GetName()},
+				},
+				Inputs: in,
+			})
+		}
+	}
+	return targets, nil
+}
+
+// toGenericResourceService converts item and annotates it with the last score the
+// health.Collector computed for it, if any.
 func (r *Storage) toGenericResourceService(ctx context.Context, item unstructured.Unstructured, apiType *kmapi.ResourceID, cmeta *kmapi.ClusterMetadata) (*rscoreapi.GenericResourceService, error) {
+	genres, _, err := r.convert(ctx, item, apiType, cmeta)
+	if err != nil {
+		return nil, err
+	}
+
+	score, ok := r.scores.Score(kmapi.ObjectInfo{
+		Resource: *apiType,
+		Ref:      kmapi.ObjectReference{Namespace: item.GetNamespace(), Name: item.GetName()},
+	})
+	if ok {
+		data, err := json.Marshal(score)
+		if err != nil {
+			return nil, err
+		}
+		genres.Annotations[uicoreapi.AnnotationHealth] = string(data)
+	}
+	return genres, nil
+}
+
+// convert returns the GenericResourceService of item along with the health inputs
+// that come from it.
+func (r *Storage) convert(ctx context.Context, item unstructured.Unstructured, apiType *kmapi.ResourceID, cmeta *kmapi.ClusterMetadata) (*rscoreapi.GenericResourceService, health.Inputs, error) {
 	content := item.UnstructuredContent()
 
 	objID := kmapi.NewObjectID(&item)
@@ -293,14 +360,14 @@ func (r *Storage) toGenericResourceService(ctx context.Context, item unstructure
 
 	s, err := status.Compute(&item)
 	if err != nil {
-		return nil, err
+		return nil, health.Inputs{}, err
 	}
 
 	var resstatus *runtime.RawExtension
 	if v, ok, _ := unstructured.NestedFieldNoCopy(content, "status"); ok {
 		data, err := json.Marshal(v)
 		if err != nil {
-			return nil, fmt.Errorf("failed to convert status to json, reason: %v", err)
+			return nil, health.Inputs{}, fmt.Errorf("failed to convert status to json, reason: %v", err)
 		}
 		resstatus = &runtime.RawExtension{Raw: data}
 	}
@@ -356,47 +423,33 @@ func (r *Storage) toGenericResourceService(ctx context.Context, item unstructure
 		}
 	}
 
-	{
-		rid, objs, err := graph.ExecQuery(ctx, r.kc, oid, sharedapi.ResourceLocator{
-			Ref: metav1.GroupKind{
-				Group: "",
-				Kind:  "Service",
-			},
-			Query: sharedapi.ResourceQuery{
-				Type:    sharedapi.GraphQLQuery,
-				ByLabel: kmapi.EdgeLabelExposedBy,
-			},
-		})
-		if err != nil {
-			return nil, err
-		}
-
-		var isExposed bool
-		var refs []kmapi.ObjectReference
-		for _, obj := range objs {
-			var svc core.Service
-			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &svc); err != nil {
-				return nil, err
-			}
-			if svc.Spec.Type == core.ServiceTypeLoadBalancer ||
-				svc.Spec.Type == core.ServiceTypeNodePort ||
-				svc.Spec.Type == core.ServiceTypeExternalName {
-				isExposed = true
-				refs = append(refs, kmapi.ObjectReference{
-					Namespace: svc.GetNamespace(),
-					Name:      svc.GetName(),
-				})
-				break
-			}
+	facilities, err := r.detectFacilities(ctx, &item, oid)
+	if err != nil {
+		return nil, health.Inputs{}, err
+	}
+	custom := map[string]rscoreapi.GenericResourceServiceFacilitator{}
+	for name, f := range facilities {
+		switch name {
+		case uicoreapi.FacilityExposed:
+			genres.Spec.Facilities.Exposed = f
+		case uicoreapi.FacilityTLS:
+			genres.Spec.Facilities.TLS = f
+		case uicoreapi.FacilityBackup:
+			genres.Spec.Facilities.Backup = f
+		case uicoreapi.FacilityMonitoring:
+			genres.Spec.Facilities.Monitoring = f
+		default:
+			custom[name] = f
 		}
-		if isExposed {
-			genres.Spec.Facilities.Exposed.Usage = rscoreapi.FacilityUsed
-			genres.Spec.Facilities.Exposed.Resource = rid
-			genres.Spec.Facilities.Exposed.Refs = refs
-		} else {
-			genres.Spec.Facilities.Exposed.Usage = rscoreapi.FacilityUnused
+	}
+	if len(custom) > 0 {
+		data, err := json.Marshal(custom)
+		if err != nil {
+			return nil, health.Inputs{}, err
 		}
+		genres.Annotations[uicoreapi.AnnotationFacilities] = string(data)
 	}
+
 	if apiType.Group == "kubedb.com" {
 		rid, objs, err := graph.ExecQuery(ctx, r.kc, oid, sharedapi.ResourceLocator{
 			Ref: metav1.GroupKind{
@@ -414,7 +467,7 @@ func (r *Storage) toGenericResourceService(ctx context.Context, item unstructure
 				if gw == nil || obj.GetNamespace() == item.GetNamespace() {
 					var binding catalogapi.GenericBinding
 					if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &binding); err != nil {
-						return nil, err
+						return nil, health.Inputs{}, err
 					}
 
 					if binding.Status.Gateway != nil {
@@ -443,127 +496,17 @@ func (r *Storage) toGenericResourceService(ctx context.Context, item unstructure
 				}
 			}
 		} else if !meta.IsNoMatchError(err) {
-			return nil, err
-		}
-	}
-	{
-		yes, err := resourcemetrics.UsesTLS(content)
-		if err != nil && !errors.Is(err, api.ErrMissingRefObject) {
-			return nil, err
-		}
-		if yes {
-			genres.Spec.Facilities.TLS.Usage = rscoreapi.FacilityUsed
-		} else {
-			genres.Spec.Facilities.TLS.Usage = rscoreapi.FacilityUnused
-		}
-	}
-	{
-		rid, refs, err := graph.ExecRawQuery(ctx, r.kc, oid, sharedapi.ResourceLocator{
-			Ref: metav1.GroupKind{
-				Group: "stash.appscode.com",
-				Kind:  "BackupSession",
-			},
-			Query: sharedapi.ResourceQuery{
-				Type: sharedapi.GraphQLQuery,
-				Raw: `query Find($src: String!, $targetGroup: String!, $targetKind: String!) {
-		         find(oid: $src) {
-		           backup_via(group: "stash.appscode.com", kind: "BackupConfiguration") {
-		             refs: offshoot(group: $targetGroup, kind: $targetKind) {
-		               namespace
-		               name
-		             }
-		           }
-		         }
-		       }`,
-			},
-		})
-		if len(refs) > 0 {
-			genres.Spec.Facilities.Backup.Usage = rscoreapi.FacilityUsed
-			genres.Spec.Facilities.Backup.Resource = rid
-			genres.Spec.Facilities.Backup.Refs = refs
-		} else if err != nil && !meta.IsNoMatchError(err) {
-			return nil, err
+			return nil, health.Inputs{}, err
 		}
 	}
-	if genres.Spec.Facilities.Backup.Usage == rscoreapi.FacilityUnknown {
-		rid, refs, err := graph.ExecRawQuery(ctx, r.kc, oid, sharedapi.ResourceLocator{
-			Ref: metav1.GroupKind{
-				Group: "core.kubestash.com",
-				Kind:  "BackupConfiguration",
-			},
-			Query: sharedapi.ResourceQuery{
-				Type:    sharedapi.GraphQLQuery,
-				ByLabel: kmapi.EdgeLabelBackupVia,
-			},
-		})
-		if len(refs) > 0 {
-			genres.Spec.Facilities.Backup.Usage = rscoreapi.FacilityUsed
-			genres.Spec.Facilities.Backup.Resource = rid
-			genres.Spec.Facilities.Backup.Refs = refs
-		} else if err == nil {
-			genres.Spec.Facilities.Backup.Usage = rscoreapi.FacilityUnused
-		} else if !meta.IsNoMatchError(err) {
-			return nil, err
-		}
+	usage := make(map[string]rscoreapi.FacilityUsage, len(facilities))
+	for name, f := range facilities {
+		usage[name] = f.Usage
 	}
-	{
-		rid, refs, err := graph.ExecRawQuery(ctx, r.kc, oid, sharedapi.ResourceLocator{
-			Ref: metav1.GroupKind{
-				Group: "monitoring.coreos.com",
-				Kind:  "ServiceMonitor",
-			},
-			Query: sharedapi.ResourceQuery{
-				Type: sharedapi.GraphQLQuery,
-				Raw: `query Find($src: String!, $targetGroup: String!, $targetKind: String!) {
-		           find(oid: $src) {
-		             exposed_by(group: "", kind: "Service") {
-		               refs: monitored_by(group: $targetGroup, kind: $targetKind) {
-		                 namespace
-		                 name
-		               }
-		             }
-		           }
-		         }`,
-			},
-		})
-		if len(refs) > 0 {
-			genres.Spec.Facilities.Monitoring.Usage = rscoreapi.FacilityUsed
-			genres.Spec.Facilities.Monitoring.Resource = rid
-			genres.Spec.Facilities.Monitoring.Refs = refs
-		} else if err != nil && !meta.IsNoMatchError(err) {
-			return nil, err
-		}
-
-		if genres.Spec.Facilities.Monitoring.Usage == rscoreapi.FacilityUnknown {
-			rid, refs, err = graph.ExecRawQuery(ctx, r.kc, oid, sharedapi.ResourceLocator{
-				Ref: metav1.GroupKind{
-					Group: "monitoring.coreos.com",
-					Kind:  "PodMonitor",
-				},
-				Query: sharedapi.ResourceQuery{
-					Type: sharedapi.GraphQLQuery,
-					Raw: `query Find($src: String!, $targetGroup: String!, $targetKind: String!) {
-		           find(oid: $src) {
-		             exposed_by(group: "", kind: "Service") {
-		               refs: monitored_by(group: $targetGroup, kind: $targetKind) {
-		                 namespace
-		                 name
-		               }
-		             }
-		           }
-		         }`,
-				},
-			})
-			if len(refs) > 0 {
-				genres.Spec.Facilities.Monitoring.Usage = rscoreapi.FacilityUsed
-				genres.Spec.Facilities.Monitoring.Resource = rid
-				genres.Spec.Facilities.Monitoring.Refs = refs
-			} else if err == nil {
-				genres.Spec.Facilities.Monitoring.Usage = rscoreapi.FacilityUnused
-			} else if !meta.IsNoMatchError(err) {
-				return nil, err
-			}
-		}
+	usage[uicoreapi.FacilityExposed] = genres.Spec.Facilities.Exposed.Usage
+	in := health.Inputs{
+		Status:     genres.Spec.Status.Status,
+		Facilities: usage,
 	}
 	{
 		buf := shared.BufferPool.Get().(*bytes.Buffer)
@@ -580,14 +523,14 @@ func (r *Storage) toGenericResourceService(ctx context.Context, item unstructure
 						buf.Reset()
 						result, err := shared.RenderTemplate(exec.If.Condition, content, buf)
 						if err != nil {
-							return nil, errors.Wrapf(err, "failed to check condition for %+v exec with alias %s", gvr, exec.Alias)
+							return nil, health.Inputs{}, errors.Wrapf(err, "failed to check condition for %+v exec with alias %s", gvr, exec.Alias)
 						}
 						result = strings.TrimSpace(result)
 						cond = strings.EqualFold(result, "true")
 					} else if exec.If.Connected != nil {
 						_, targets, err := graph.ExecRawQuery(ctx, r.kc, oid, *exec.If.Connected)
 						if err != nil {
-							return nil, errors.Wrapf(err, "failed to check connection for %+v exec with alias %s", gvr, exec.Alias)
+							return nil, health.Inputs{}, errors.Wrapf(err, "failed to check connection for %+v exec with alias %s", gvr, exec.Alias)
 						}
 						cond = len(targets) > 0
 					}
@@ -613,7 +556,7 @@ func (r *Storage) toGenericResourceService(ctx context.Context, item unstructure
 					buf.Reset()
 					svcName, err := shared.RenderTemplate(exec.ServiceNameTemplate, content, buf)
 					if err != nil {
-						return nil, errors.Wrapf(err, "failed to render service name for %+v exec with alias %s", gvr, exec.Alias)
+						return nil, health.Inputs{}, errors.Wrapf(err, "failed to render service name for %+v exec with alias %s", gvr, exec.Alias)
 					}
 
 					execServices = append(execServices, rscoreapi.ExecServiceFacilitator{
@@ -635,7 +578,7 @@ func (r *Storage) toGenericResourceService(ctx context.Context, item unstructure
 		}
 	}
 
-	return &genres, nil
+	return &genres, in, nil
 }
 
 func genKubectlCommand(kind, name, ns string, exec rsapi.ResourceExec) string {
@@ -18,43 +18,49 @@ package ResourceSummary
 
 import (
 	"context"
-	"sort"
+	"fmt"
 	"strings"
 	"time"
 
 	"kubeops.dev/ui-server/pkg/shared"
 
 	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
 	core "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/internalversion"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 	apirequest "k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/apiserver/pkg/registry/rest"
-	"k8s.io/client-go/discovery"
-	"k8s.io/client-go/discovery/cached/memory"
-	"k8s.io/client-go/restmapper"
-	"kmodules.xyz/apiversion"
 	kmapi "kmodules.xyz/client-go/api/v1"
 	clustermeta "kmodules.xyz/client-go/cluster"
 	rscoreapi "kmodules.xyz/resource-metadata/apis/core/v1alpha1"
 	resourcemetrics "kmodules.xyz/resource-metrics"
 	"kmodules.xyz/resource-metrics/api"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// summaryWorkers is the number of kinds summarized in parallel.
+const summaryWorkers = 8
+
 type Storage struct {
 	kc        client.Client
-	dc        discovery.DiscoveryInterface
+	mapper    *shared.RESTMapper
 	clusterID string
 	a         authorizer.Authorizer
-	convertor rest.TableConvertor
+	cache     cache.Cache
+	// watchAllKinds allows watches without a group-kind selector.
+	watchAllKinds bool
+	convertor     rest.TableConvertor
 }
 
 var (
@@ -62,15 +68,18 @@ var (
 	_ rest.Scoper                   = &Storage{}
 	_ rest.Storage                  = &Storage{}
 	_ rest.Lister                   = &Storage{}
+	_ rest.Watcher                  = &Storage{}
 	_ rest.SingularNameProvider     = &Storage{}
 )
 
-func NewStorage(kc client.Client, dc discovery.DiscoveryInterface, clusterID string, a authorizer.Authorizer) *Storage {
+func NewStorage(kc client.Client, mapper *shared.RESTMapper, clusterID string, a authorizer.Authorizer, c cache.Cache, watchAllKinds bool) *Storage {
 	return &Storage{
-		kc:        kc,
-		dc:        dc,
-		clusterID: clusterID,
-		a:         a,
+		kc:            kc,
+		mapper:        mapper,
+		clusterID:     clusterID,
+		a:             a,
+		cache:         c,
+		watchAllKinds: watchAllKinds,
 		convertor: rest.NewDefaultTableConvertor(schema.GroupResource{
 			Group:    rscoreapi.GroupName,
 			Resource: rscoreapi.ResourceResourceSummaries,
@@ -121,147 +130,213 @@ func (r *Storage) List(ctx context.Context, options *internalversion.ListOptions
 		}
 	}
 
+	if options == nil {
+		options = &internalversion.ListOptions{}
+	}
+
+	result := rscoreapi.ResourceSummaryList{
+		TypeMeta: metav1.TypeMeta{},
+		ListMeta: metav1.ListMeta{},
+		Items:    make([]rscoreapi.ResourceSummary, 0),
+	}
+
+	sel := options.FieldSelector
+	if sel == nil {
+		sel = fields.Everything()
+	}
+	for _, req := range sel.Requirements() {
+		if req.Field != "metadata.name" && req.Field != "metadata.namespace" {
+			return nil, apierrors.NewBadRequest(fmt.Sprintf("field label not supported: %s", req.Field))
+		}
+	}
+	if v, found := sel.RequiresExactMatch("metadata.namespace"); found {
+		if ns != "" && ns != v {
+			return &result, nil
+		}
+		ns = v
+	}
+
 	cmeta, err := clustermeta.ClusterMetadata(r.kc)
 	if err != nil {
 		return nil, apierrors.NewInternalError(err)
 	}
 
-	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(r.dc))
+	all, err := shared.RegisteredMappings(r.mapper, shared.NewGroupKindSelector(options.LabelSelector))
+	if err != nil {
+		return nil, err
+	}
+	// a summary is named after its kind, so the field selector is applied to the kinds
+	mappings := make([]*meta.RESTMapping, 0, len(all))
+	for _, m := range all {
+		if sel.Matches(fields.Set{
+			"metadata.name":      m.GroupVersionKind.GroupKind().String(),
+			"metadata.namespace": ns,
+		}) {
+			mappings = append(mappings, m)
+		}
+	}
+
+	if options.Continue != "" {
+		tok, err := shared.DecodeContinueToken(options.Continue)
+		if err != nil {
+			return nil, apierrors.NewBadRequest("invalid continue token")
+		}
+		start, _ := tok.ResumeIndex(mappings)
+		mappings = mappings[start:]
+	}
+	if options.Limit > 0 && int64(len(mappings)) > options.Limit {
+		result.Continue = shared.ContinueToken{
+			GroupKind: mappings[options.Limit].GroupVersionKind.GroupKind().String(),
+		}.Encode()
+		mappings = mappings[:options.Limit]
+	}
 
-	selector := shared.NewGroupKindSelector(options.LabelSelector)
 	now := time.Now()
+	items := make([]rscoreapi.ResourceSummary, len(mappings))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(summaryWorkers)
+	for i, mapping := range mappings {
+		g.Go(func() error {
+			summary, err := r.summarize(gctx, r.kc, user, ns, mapping, cmeta, now)
+			if err != nil {
+				return err
+			}
+			items[i] = *summary
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	result.Items = items
 
-	gvks := make(map[schema.GroupKind]string)
-	for _, gvk := range api.RegisteredTypes() {
-		if !selector.Matches(gvk.GroupKind()) {
-			continue
-		}
-		gk := gvk.GroupKind()
-		if v, exists := gvks[gk]; !exists || apiversion.MustCompare(v, gvk.Version) < 0 {
-			gvks[gk] = gvk.Version
-		}
+	return &result, nil
+}
+
+// summarize aggregates the objects of a kind in ns that user is allowed to get.
+func (r *Storage) summarize(ctx context.Context, kc client.Reader, u user.Info, ns string, mapping *meta.RESTMapping, cmeta *kmapi.ClusterMetadata, now time.Time) (*rscoreapi.ResourceSummary, error) {
+	gk := mapping.GroupVersionKind.GroupKind()
+	apiType := kmapi.NewResourceID(mapping)
+
+	attrs := authorizer.AttributesRecord{
+		User:            u,
+		Verb:            "get",
+		Namespace:       ns,
+		APIGroup:        mapping.Resource.Group,
+		Resource:        mapping.Resource.Resource,
+		Name:            "",
+		ResourceRequest: true,
 	}
 
-	items := make([]rscoreapi.ResourceSummary, 0)
-	for gk, v := range gvks {
-		if !selector.Matches(gk) {
-			continue
-		}
+	summary := rscoreapi.ResourceSummary{
+		TypeMeta: metav1.TypeMeta{},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              gk.String(),
+			Namespace:         ns,
+			CreationTimestamp: metav1.NewTime(now),
+			// stable across calls, so that watch events for a kind refer to the same object
+			UID: types.UID(uuid.NewSHA1(uuid.NameSpaceOID, []byte(r.clusterID+"/"+ns+"/"+gk.String())).String()),
+		},
+		Spec: rscoreapi.ResourceSummarySpec{
+			Cluster: *cmeta,
+			APIType: *apiType,
+			// TotalResource: core.ResourceRequirements{},
+			// AppResource:   core.ResourceRequirements{},
+			Count: 0,
+		},
+	}
 
-		mapping, err := mapper.RESTMapping(gk, v)
-		if meta.IsNoMatchError(err) {
-			continue
-		} else if err != nil {
+	items, err := shared.ListUnstructured(ctx, kc, r.kc.Scheme(), mapping.GroupVersionKind, client.InNamespace(ns))
+	if err != nil {
+		if !meta.IsNoMatchError(err) && !apierrors.IsNotFound(err) {
 			return nil, err
 		}
-		apiType := kmapi.NewResourceID(mapping)
-
-		attrs := authorizer.AttributesRecord{
-			User:            user,
-			Verb:            "get",
-			Namespace:       ns,
-			APIGroup:        mapping.Resource.Group,
-			Resource:        mapping.Resource.Resource,
-			Name:            "",
-			ResourceRequest: true,
-		}
+		// the kind was removed since it was discovered
+		r.mapper.Reset()
+	}
 
-		summary := rscoreapi.ResourceSummary{
-			TypeMeta: metav1.TypeMeta{},
-			ObjectMeta: metav1.ObjectMeta{
-				Name:              gk.String(),
-				Namespace:         ns,
-				CreationTimestamp: metav1.NewTime(now),
-				UID:               types.UID(uuid.Must(uuid.NewUUID()).String()),
-			},
-			Spec: rscoreapi.ResourceSummarySpec{
-				Cluster: *cmeta,
-				APIType: *apiType,
-				// TotalResource: core.ResourceRequirements{},
-				// AppResource:   core.ResourceRequirements{},
-				Count: 0,
-			},
-		}
+	allowed, err := Allowed(ctx, r.a, attrs, items)
+	if err != nil {
+		return nil, err
+	}
+	// hasPermission to check if the user has permission to list the resources
+	hasPermission := len(allowed) > 0
+	totals, err := Aggregate(allowed)
+	if err != nil {
+		return nil, err
+	}
+	summary.Spec.TotalResource.Requests = rscoreapi.ConvertToStringQuantity(totals.TotalRequests)
+	summary.Spec.TotalResource.Limits = rscoreapi.ConvertToStringQuantity(totals.TotalLimits)
+	summary.Spec.AppResource.Requests = rscoreapi.ConvertToStringQuantity(totals.AppRequests)
+	summary.Spec.AppResource.Limits = rscoreapi.ConvertToStringQuantity(totals.AppLimits)
 
-		var list unstructured.UnstructuredList
-		list.SetGroupVersionKind(schema.GroupVersionKind{
-			Group:   gk.Group,
-			Version: v,
-			Kind:    gk.Kind,
-		})
-		if err := r.kc.List(ctx, &list, client.InNamespace(ns)); err != nil {
-			return nil, err
+	if hasPermission {
+		summary.Spec.Count = len(items)
+	}
+	return &summary, nil
+}
+
+// Totals are the resource requirements of a set of objects.
+type Totals struct {
+	TotalRequests core.ResourceList
+	TotalLimits   core.ResourceList
+	AppRequests   core.ResourceList
+	AppLimits     core.ResourceList
+}
+
+// Allowed returns the items that the user of attrs is allowed to get. The name and the
+// namespace of attrs are set from each item.
+func Allowed(ctx context.Context, a authorizer.Authorizer, attrs authorizer.AttributesRecord, items []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+	allowed := make([]unstructured.Unstructured, 0, len(items))
+	for _, item := range items {
+		attrs.Name = item.GetName()
+		attrs.Namespace = item.GetNamespace()
+		decision, _, err := a.Authorize(ctx, attrs)
+		if err != nil {
+			return nil, apierrors.NewInternalError(err)
 		}
+		if decision == authorizer.DecisionAllow {
+			allowed = append(allowed, item)
+		}
+	}
+	return allowed, nil
+}
 
-		// hasPermission to check if the user has permission to list the resources
-		hasPermission := false
-		var totalReqRL, totalLimRL, appReqRL, appLimRL core.ResourceList
-		for _, item := range list.Items {
-			attrs.Name = item.GetName()
-			attrs.Namespace = item.GetNamespace()
-			decision, _, err := r.a.Authorize(ctx, attrs)
+// Aggregate sums the resource requirements of items as computed by resource-metrics.
+func Aggregate(items []unstructured.Unstructured) (*Totals, error) {
+	var t Totals
+	for _, item := range items {
+		content := item.UnstructuredContent()
+		{
+			rv, err := resourcemetrics.TotalResourceRequests(content)
 			if err != nil {
-				return nil, apierrors.NewInternalError(err)
-			}
-			if decision != authorizer.DecisionAllow {
-				continue
+				return nil, err
 			}
-
-			hasPermission = true
-			content := item.UnstructuredContent()
-			{
-				rv, err := resourcemetrics.TotalResourceRequests(content)
-				if err != nil {
-					return nil, err
-				}
-				totalReqRL = api.AddResourceList(totalReqRL, rv)
-			}
-			{
-				rv, err := resourcemetrics.TotalResourceLimits(content)
-				if err != nil {
-					return nil, err
-				}
-				totalLimRL = api.AddResourceList(totalLimRL, rv)
-			}
-			{
-				rv, err := resourcemetrics.AppResourceRequests(content)
-				if err != nil {
-					return nil, err
-				}
-				appReqRL = api.AddResourceList(appReqRL, rv)
-			}
-			{
-				rv, err := resourcemetrics.AppResourceLimits(content)
-				if err != nil {
-					return nil, err
-				}
-				appLimRL = api.AddResourceList(appLimRL, rv)
+			t.TotalRequests = api.AddResourceList(t.TotalRequests, rv)
+		}
+		{
+			rv, err := resourcemetrics.TotalResourceLimits(content)
+			if err != nil {
+				return nil, err
 			}
+			t.TotalLimits = api.AddResourceList(t.TotalLimits, rv)
 		}
-		summary.Spec.TotalResource.Requests = rscoreapi.ConvertToStringQuantity(totalReqRL)
-		summary.Spec.TotalResource.Limits = rscoreapi.ConvertToStringQuantity(totalLimRL)
-		summary.Spec.AppResource.Requests = rscoreapi.ConvertToStringQuantity(appReqRL)
-		summary.Spec.AppResource.Limits = rscoreapi.ConvertToStringQuantity(appLimRL)
-
-		if hasPermission {
-			summary.Spec.Count = len(list.Items)
+		{
+			rv, err := resourcemetrics.AppResourceRequests(content)
+			if err != nil {
+				return nil, err
+			}
+			t.AppRequests = api.AddResourceList(t.AppRequests, rv)
 		}
-		items = append(items, summary)
-	}
-	sort.Slice(items, func(i, j int) bool {
-		if items[i].Spec.APIType.Group != items[j].Spec.APIType.Group {
-			return items[i].Spec.APIType.Group < items[j].Spec.APIType.Group
+		{
+			rv, err := resourcemetrics.AppResourceLimits(content)
+			if err != nil {
+				return nil, err
+			}
+			t.AppLimits = api.AddResourceList(t.AppLimits, rv)
 		}
-		return items[i].Spec.APIType.Kind < items[j].Spec.APIType.Kind
-	})
-
-	result := rscoreapi.ResourceSummaryList{
-		TypeMeta: metav1.TypeMeta{},
-		ListMeta: metav1.ListMeta{},
-		Items:    items,
 	}
-
-	return &result, nil
+	return &t, nil
 }
 
 func (r *Storage) ConvertToTable(ctx context.Context, object runtime.Object, tableOptions runtime.Object) (*metav1.Table, error) {
@@ -0,0 +1,185 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ResourceSummary
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"kubeops.dev/ui-server/pkg/shared"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/authentication/user"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+	kmapi "kmodules.xyz/client-go/api/v1"
+	clustermeta "kmodules.xyz/client-go/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// summaryInterval is the minimum time between two summaries of a kind sent to a watcher,
+// so that a burst of events, like the initial sync of an informer, is summarized once.
+const summaryInterval = time.Second
+
+// Watch streams ResourceSummaries. The summary of a kind is recomputed from the manager's
+// cache whenever an object of that kind changes in the watched namespace, and sent as
+// Added the first time and Modified afterwards.
+func (r *Storage) Watch(ctx context.Context, options *internalversion.ListOptions) (watch.Interface, error) {
+	user, ok := apirequest.UserFrom(ctx)
+	if !ok {
+		return nil, apierrors.NewBadRequest("missing user info")
+	}
+
+	ns, ok := apirequest.NamespaceFrom(ctx)
+	if !ok {
+		return nil, apierrors.NewBadRequest("missing namespace")
+	}
+	// for client org user, show their own namespace only when all namespace summary is requested
+	if ns == "" {
+		result, err := clustermeta.IsClientOrgMember(r.kc, user)
+		if err != nil {
+			return nil, err
+		}
+		if result.IsClientOrg {
+			ns = result.Namespace.Name
+		}
+	}
+
+	cmeta, err := clustermeta.ClusterMetadata(r.kc)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+
+	selector := shared.NewGroupKindSelector(options.LabelSelector)
+	kinds, err := shared.WatchedKinds(selector, r.watchAllKinds)
+	if err != nil {
+		return nil, err
+	}
+
+	w := shared.NewInformerWatcher(ctx, options.ResourceVersion)
+	sw := &summaryWatcher{
+		r:        r,
+		w:        w,
+		user:     user,
+		ns:       ns,
+		cmeta:    cmeta,
+		mappings: map[schema.GroupKind]*meta.RESTMapping{},
+		dirty:    sets.New[schema.GroupKind](),
+		sent:     sets.New[schema.GroupKind](),
+		notify:   make(chan struct{}, 1),
+	}
+	for gk, v := range kinds {
+		mapping, err := r.mapper.RESTMapping(gk, v)
+		if meta.IsNoMatchError(err) {
+			continue
+		} else if err != nil {
+			w.Stop()
+			return nil, err
+		}
+		sw.mappings[gk] = mapping
+
+		inf, err := shared.GetInformer(ctx, r.cache, r.kc.Scheme(), mapping.GroupVersionKind)
+		if err != nil {
+			w.Stop()
+			return nil, err
+		}
+		// kinds without objects are summarized too, as List returns them
+		sw.markDirty(gk)
+		err = w.AddInformer(inf, func(_ watch.EventType, obj client.Object) runtime.Object {
+			if ns == "" || obj.GetNamespace() == ns {
+				sw.markDirty(gk)
+			}
+			return nil
+		})
+		if err != nil {
+			w.Stop()
+			return nil, err
+		}
+	}
+
+	// The Watch context lasts until the client goes away, which also stops the watcher.
+	go sw.run(ctx)
+	return w, nil
+}
+
+type summaryWatcher struct {
+	r        *Storage
+	w        *shared.InformerWatcher
+	user     user.Info
+	ns       string
+	cmeta    *kmapi.ClusterMetadata
+	mappings map[schema.GroupKind]*meta.RESTMapping
+
+	mu     sync.Mutex
+	dirty  sets.Set[schema.GroupKind]
+	sent   sets.Set[schema.GroupKind]
+	notify chan struct{}
+}
+
+func (s *summaryWatcher) markDirty(gk schema.GroupKind) {
+	s.mu.Lock()
+	s.dirty.Insert(gk)
+	s.mu.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (s *summaryWatcher) run(ctx context.Context) {
+	for {
+		select {
+		case <-s.w.Done():
+			return
+		case <-s.notify:
+		}
+
+		s.mu.Lock()
+		dirty := s.dirty
+		s.dirty = sets.New[schema.GroupKind]()
+		s.mu.Unlock()
+
+		now := time.Now()
+		for gk := range dirty {
+			summary, err := s.r.summarize(ctx, s.r.cache, s.user, s.ns, s.mappings[gk], s.cmeta, now)
+			if err != nil {
+				klog.ErrorS(err, "failed to summarize resources", "kind", gk, "namespace", s.ns)
+				continue
+			}
+			t := watch.Modified
+			if !s.sent.Has(gk) {
+				t = watch.Added
+				s.sent.Insert(gk)
+			}
+			s.w.Send(watch.Event{Type: t, Object: summary})
+		}
+
+		select {
+		case <-s.w.Done():
+			return
+		case <-time.After(summaryInterval):
+		}
+	}
+}
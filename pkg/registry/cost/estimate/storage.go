@@ -0,0 +1,157 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package estimate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	costapi "kubeops.dev/ui-server/apis/cost/v1alpha1"
+	"kubeops.dev/ui-server/pkg/registry/cost/pricing"
+
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apiserver/pkg/registry/rest"
+	resourcemetrics "kmodules.xyz/resource-metrics"
+	"kmodules.xyz/resource-metrics/api"
+	opsv1alpha1 "kmodules.xyz/resource-metrics/ops.kubedb.com/v1alpha1"
+	"kmodules.xyz/resource-metrics/utils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Storage estimates the cost of a manifest from its total resource requests, priced at the
+// rates of the configured cost backend.
+type Storage struct {
+	kc     client.Client
+	quoter pricing.Quoter
+}
+
+var (
+	_ rest.GroupVersionKindProvider = &Storage{}
+	_ rest.Scoper                   = &Storage{}
+	_ rest.Storage                  = &Storage{}
+	_ rest.Creater                  = &Storage{}
+	_ rest.SingularNameProvider     = &Storage{}
+)
+
+func NewStorage(kc client.Client, quoter pricing.Quoter) *Storage {
+	return &Storage{
+		kc:     kc,
+		quoter: quoter,
+	}
+}
+
+func (r *Storage) GroupVersionKind(_ schema.GroupVersion) schema.GroupVersionKind {
+	return costapi.SchemeGroupVersion.WithKind(costapi.ResourceKindCostEstimate)
+}
+
+func (r *Storage) NamespaceScoped() bool {
+	return false
+}
+
+func (r *Storage) GetSingularName() string {
+	return strings.ToLower(costapi.ResourceKindCostEstimate)
+}
+
+func (r *Storage) New() runtime.Object {
+	return &costapi.CostEstimate{}
+}
+
+func (r *Storage) Destroy() {}
+
+func (r *Storage) Create(ctx context.Context, obj runtime.Object, _ rest.ValidateObjectFunc, _ *metav1.CreateOptions) (runtime.Object, error) {
+	in := obj.(*costapi.CostEstimate)
+	if in.Request == nil || in.Request.Resource == nil {
+		return nil, apierrors.NewBadRequest("missing resource")
+	}
+
+	var u unstructured.Unstructured
+	if err := json.Unmarshal(in.Request.Resource.Raw, &u); err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	gvk := u.GroupVersionKind()
+	if !api.IsRegistered(gvk) {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("cost of %s can't be estimated", gvk))
+	}
+	if gvk.Group == "ops.kubedb.com" {
+		if err := utils.ExpandReferencedAppInOpsObject(r.kc, &u); err != nil {
+			return nil, err
+		}
+	}
+
+	requests, err := resourcemetrics.TotalResourceRequests(u.UnstructuredContent())
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	q, err := r.quoter.Quote(ctx)
+	if _, ok := err.(apierrors.APIStatus); err != nil && !ok {
+		return nil, apierrors.NewServiceUnavailable(err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+
+	est := q.Estimate(requests)
+	current, err := currentResourceRequests(ctx, r.kc, &u, in.Request.Edit)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil {
+		cur := q.Estimate(current)
+		est.Delta = &costapi.CostDelta{
+			Hourly:  est.Hourly - cur.Hourly,
+			Monthly: est.Monthly - cur.Monthly,
+		}
+	}
+	in.Response = est
+	return in, nil
+}
+
+// currentResourceRequests returns the total resource requests of the resource running in the cluster.
+// For ops requests, it is the referenced database that was expanded into the ops request.
+// It returns nil for new resources.
+func currentResourceRequests(ctx context.Context, kc client.Client, u *unstructured.Unstructured, edit bool) (core.ResourceList, error) {
+	if u.GroupVersionKind().Group == "ops.kubedb.com" {
+		opsPathMapper, err := opsv1alpha1.LoadOpsPathMapper(u.Object)
+		if err != nil {
+			return nil, err
+		}
+		dbObj, found, _ := unstructured.NestedMap(u.Object, opsPathMapper.GetAppRefPath()...)
+		if !found {
+			return nil, nil
+		}
+		return resourcemetrics.TotalResourceRequests(dbObj)
+	}
+	if !edit {
+		return nil, nil
+	}
+
+	var cur unstructured.Unstructured
+	cur.SetGroupVersionKind(u.GroupVersionKind())
+	err := kc.Get(ctx, client.ObjectKeyFromObject(u), &cur)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return resourcemetrics.TotalResourceRequests(cur.UnstructuredContent())
+}
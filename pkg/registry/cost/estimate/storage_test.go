@@ -0,0 +1,115 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package estimate
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	costapi "kubeops.dev/ui-server/apis/cost/v1alpha1"
+	"kubeops.dev/ui-server/pkg/registry/cost/pricing"
+
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/json"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type fakeQuoter pricing.Quote
+
+func (q *fakeQuoter) Quote(_ context.Context) (*pricing.Quote, error) {
+	return (*pricing.Quote)(q), nil
+}
+
+func deployment(replicas int32, cpu string) *apps.Deployment {
+	return &apps.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "demo"},
+		Spec: apps.DeploymentSpec{
+			Replicas: ptr.To(replicas),
+			Template: core.PodTemplateSpec{
+				Spec: core.PodSpec{
+					Containers: []core.Container{
+						{
+							Name: "web",
+							Resources: core.ResourceRequirements{
+								Requests: core.ResourceList{
+									core.ResourceCPU:    resource.MustParse(cpu),
+									core.ResourceMemory: resource.MustParse("1Gi"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestCreate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	kc := fake.NewClientBuilder().WithScheme(scheme).WithObjects(deployment(2, "500m")).Build()
+
+	r := NewStorage(kc, &fakeQuoter{
+		Backend:  costapi.CostBackendLocal,
+		Currency: "USD",
+		Rates:    pricing.Rates{CPUCoreHour: 0.04, RAMGiBHour: 0.005},
+	})
+
+	raw, err := json.Marshal(deployment(3, "1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	approx := func(x, y float64) bool { return math.Abs(x-y) < 1e-9 }
+	for _, edit := range []bool{false, true} {
+		obj, err := r.Create(context.TODO(), &costapi.CostEstimate{
+			Request: &costapi.CostEstimateRequest{
+				Resource: &runtime.RawExtension{Raw: raw},
+				Edit:     edit,
+			},
+		}, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		est := obj.(*costapi.CostEstimate).Response
+		// 3 * (1 core * 0.04 + 1GiB * 0.005)
+		if est == nil || !approx(est.Hourly, 0.135) || !approx(est.Monthly, 0.135*pricing.HoursPerMonth) || est.Currency != "USD" {
+			t.Fatalf("edit=%v: unexpected estimate %+v", edit, est)
+		}
+		switch {
+		case !edit && est.Delta != nil:
+			t.Errorf("unexpected delta %+v for a new resource", est.Delta)
+		case edit && (est.Delta == nil || !approx(est.Delta.Hourly, 0.135-0.05)):
+			// running at 2 * (0.5 core * 0.04 + 1GiB * 0.005)
+			t.Errorf("unexpected delta %+v", est.Delta)
+		}
+	}
+
+	if _, err := r.Create(context.TODO(), &costapi.CostEstimate{}, nil, nil); !apierrors.IsBadRequest(err) {
+		t.Errorf("expected bad request, got %v", err)
+	}
+}
@@ -0,0 +1,209 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	costapi "kubeops.dev/ui-server/apis/cost/v1alpha1"
+
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	keyPricing = "pricing.yaml"
+
+	HoursPerMonth = 730
+	BytesPerGiB   = 1 << 30
+)
+
+// Pricing is read from the pricing ConfigMap, eg,
+//
+//	currency: USD
+//	default:
+//	  cpuCoreHour: 0.0316
+//	  ramGiBHour: 0.0042
+//	  storageGiBMonth: 0.04
+//	nodes:
+//	- instanceType: g4dn.xlarge
+//	  gpuHour: 0.526
+//	- zone: us-east-1a
+//	  labels:
+//	    node.kubernetes.io/lifecycle: spot
+//	  cpuCoreHour: 0.011
+//	storageClasses:
+//	  gp3: 0.08
+type Pricing struct {
+	Currency string `json:"currency,omitempty"`
+	Default  Rates  `json:"default"`
+	// Nodes are matched in order and the first match is used. Rates that are not set
+	// in the matching entry are taken from the default rates.
+	Nodes []NodePricing `json:"nodes,omitempty"`
+	// StorageClasses maps storage class names to their price per GiB-month.
+	StorageClasses map[string]float64 `json:"storageClasses,omitempty"`
+}
+
+type Rates struct {
+	CPUCoreHour     float64 `json:"cpuCoreHour,omitempty"`
+	RAMGiBHour      float64 `json:"ramGiBHour,omitempty"`
+	GPUHour         float64 `json:"gpuHour,omitempty"`
+	StorageGiBMonth float64 `json:"storageGiBMonth,omitempty"`
+}
+
+type NodePricing struct {
+	InstanceType string            `json:"instanceType,omitempty"`
+	Zone         string            `json:"zone,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	Rates        `json:",inline"`
+}
+
+func LoadPricing(ctx context.Context, kc client.Client, key client.ObjectKey) (*Pricing, error) {
+	var cm core.ConfigMap
+	if err := kc.Get(ctx, key, &cm); err != nil {
+		return nil, err
+	}
+	data, ok := cm.Data[keyPricing]
+	if !ok {
+		return nil, apierrors.NewInternalError(fmt.Errorf("ConfigMap %s/%s does not name data[%q]", cm.Namespace, cm.Name, keyPricing))
+	}
+	var p Pricing
+	if err := yaml.Unmarshal([]byte(data), &p); err != nil {
+		return nil, apierrors.NewInternalError(fmt.Errorf("failed to parse pricing in ConfigMap %s/%s: %w", cm.Namespace, cm.Name, err))
+	}
+	return &p, nil
+}
+
+// RatesFor returns the rates of a node. If node is nil, eg, for pending pods, the default rates are returned.
+func (p *Pricing) RatesFor(node *core.Node) Rates {
+	if node == nil {
+		return p.Default
+	}
+	for _, np := range p.Nodes {
+		if np.matches(node.Labels) {
+			return np.Rates.withDefaults(p.Default)
+		}
+	}
+	return p.Default
+}
+
+// StorageGiBMonth returns the price of storage for a storage class.
+func (p *Pricing) StorageGiBMonth(storageClass string) float64 {
+	if v, ok := p.StorageClasses[storageClass]; ok {
+		return v
+	}
+	return p.Default.StorageGiBMonth
+}
+
+func (np NodePricing) matches(labels map[string]string) bool {
+	if np.InstanceType != "" &&
+		np.InstanceType != labels[core.LabelInstanceTypeStable] &&
+		np.InstanceType != labels[core.LabelInstanceType] {
+		return false
+	}
+	if np.Zone != "" &&
+		np.Zone != labels[core.LabelTopologyZone] &&
+		np.Zone != labels[core.LabelFailureDomainBetaZone] {
+		return false
+	}
+	for k, v := range np.Labels {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (r Rates) withDefaults(d Rates) Rates {
+	if r.CPUCoreHour == 0 {
+		r.CPUCoreHour = d.CPUCoreHour
+	}
+	if r.RAMGiBHour == 0 {
+		r.RAMGiBHour = d.RAMGiBHour
+	}
+	if r.GPUHour == 0 {
+		r.GPUHour = d.GPUHour
+	}
+	if r.StorageGiBMonth == 0 {
+		r.StorageGiBMonth = d.StorageGiBMonth
+	}
+	return r
+}
+
+// Amounts of the priced resources in a resource list.
+type Amounts struct {
+	CPUCores   float64
+	RAMBytes   float64
+	GPUs       float64
+	StorageGiB float64
+}
+
+func AmountsOf(rl core.ResourceList) Amounts {
+	var a Amounts
+	for name, q := range rl {
+		switch {
+		case name == core.ResourceCPU:
+			a.CPUCores += q.AsApproximateFloat64()
+		case name == core.ResourceMemory:
+			a.RAMBytes += q.AsApproximateFloat64()
+		case name == core.ResourceStorage:
+			a.StorageGiB += q.AsApproximateFloat64() / BytesPerGiB
+		case strings.HasSuffix(string(name), "/gpu"):
+			a.GPUs += q.AsApproximateFloat64()
+		}
+	}
+	return a
+}
+
+func (a Amounts) RAMGiB() float64 {
+	return a.RAMBytes / BytesPerGiB
+}
+
+// HourlyCost returns the cost per hour of the resources at the given rates.
+func (r Rates) HourlyCost(a Amounts) float64 {
+	return a.CPUCores*r.CPUCoreHour +
+		a.RAMGiB()*r.RAMGiBHour +
+		a.GPUs*r.GPUHour +
+		a.StorageGiB*r.StorageGiBMonth/HoursPerMonth
+}
+
+// Quote is the set of rates used to price resources before they are scheduled to a node.
+type Quote struct {
+	Backend  costapi.CostBackend
+	Currency string
+	Rates    Rates
+}
+
+// Quoter returns the rates of the configured cost backend.
+type Quoter interface {
+	Quote(ctx context.Context) (*Quote, error)
+}
+
+// Estimate returns the estimated cost of the requested resources.
+func (q *Quote) Estimate(requests core.ResourceList) *costapi.CostEstimateResponse {
+	hourly := q.Rates.HourlyCost(AmountsOf(requests))
+	return &costapi.CostEstimateResponse{
+		Backend:  q.Backend,
+		Currency: q.Currency,
+		Hourly:   hourly,
+		Monthly:  hourly * HoursPerMonth,
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pricing
+
+import (
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const testPricing = `
+currency: USD
+default:
+  cpuCoreHour: 0.04
+  ramGiBHour: 0.005
+  storageGiBMonth: 0.1
+nodes:
+- instanceType: g4dn.xlarge
+  gpuHour: 0.5
+- zone: us-east-1a
+  labels:
+    node.kubernetes.io/lifecycle: spot
+  cpuCoreHour: 0.01
+storageClasses:
+  gp3: 0.08
+`
+
+func node(labels map[string]string) *core.Node {
+	return &core.Node{ObjectMeta: metav1.ObjectMeta{Name: "n", Labels: labels}}
+}
+
+func TestRatesFor(t *testing.T) {
+	var p Pricing
+	if err := yaml.Unmarshal([]byte(testPricing), &p); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		node     *core.Node
+		expected Rates
+	}{
+		{
+			name:     "pending pod",
+			node:     nil,
+			expected: p.Default,
+		},
+		{
+			name:     "instance type",
+			node:     node(map[string]string{core.LabelInstanceTypeStable: "g4dn.xlarge"}),
+			expected: Rates{CPUCoreHour: 0.04, RAMGiBHour: 0.005, GPUHour: 0.5, StorageGiBMonth: 0.1},
+		},
+		{
+			name: "zone and labels",
+			node: node(map[string]string{
+				core.LabelTopologyZone:         "us-east-1a",
+				"node.kubernetes.io/lifecycle": "spot",
+			}),
+			expected: Rates{CPUCoreHour: 0.01, RAMGiBHour: 0.005, StorageGiBMonth: 0.1},
+		},
+		{
+			name:     "zone without labels",
+			node:     node(map[string]string{core.LabelTopologyZone: "us-east-1a"}),
+			expected: p.Default,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.RatesFor(tt.node); got != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+
+	if got := p.StorageGiBMonth("gp3"); got != 0.08 {
+		t.Errorf("expected gp3 price 0.08, got %v", got)
+	}
+	if got := p.StorageGiBMonth("standard"); got != 0.1 {
+		t.Errorf("expected default storage price 0.1, got %v", got)
+	}
+}
+
+func TestHourlyCost(t *testing.T) {
+	a := AmountsOf(core.ResourceList{
+		core.ResourceCPU:     resource.MustParse("500m"),
+		core.ResourceMemory:  resource.MustParse("2Gi"),
+		core.ResourceStorage: resource.MustParse("73Gi"),
+		"nvidia.com/gpu":     resource.MustParse("1"),
+	})
+	r := Rates{CPUCoreHour: 0.04, RAMGiBHour: 0.005, GPUHour: 0.5, StorageGiBMonth: 0.1}
+	// 0.5 * 0.04 + 2 * 0.005 + 1 * 0.5 + 73 * 0.1 / 730
+	if got := r.HourlyCost(a); got < 0.5399 || got > 0.5401 {
+		t.Errorf("unexpected hourly cost %v", got)
+	}
+}
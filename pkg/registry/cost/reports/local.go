@@ -0,0 +1,489 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reports
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	costapi "kubeops.dev/ui-server/apis/cost/v1alpha1"
+	"kubeops.dev/ui-server/pkg/registry/cost/pricing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	kmapi "kmodules.xyz/client-go/api/v1"
+	resourcemetrics "kmodules.xyz/resource-metrics"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultWindow         = "1d"
+	unallocatedAllocation = "__unallocated__"
+	unmountedAllocation   = "__unmounted__"
+)
+
+// localEstimator estimates cost from the resource requests of pods and persistent volume claims,
+// priced by a pricing ConfigMap. It is used where OpenCost can't be run.
+//
+// The estimator keeps no history, it prices the pods and claims that exist now over the part of
+// the window they have been running. So windows that end before now are rejected, and the end of
+// a window in the future is cut to now.
+type localEstimator struct {
+	kc         client.Client
+	pricingKey client.ObjectKey
+}
+
+// podCost is the estimated cost of a pod before aggregation.
+type podCost struct {
+	props costapi.CostAllocationProperties
+	alloc costapi.CostAllocation
+}
+
+func (e *localEstimator) loadPricing(ctx context.Context) (*pricing.Pricing, error) {
+	p, err := pricing.LoadPricing(ctx, e.kc, e.pricingKey)
+	if apierrors.IsNotFound(err) {
+		return nil, apierrors.NewServiceUnavailable(fmt.Sprintf("no cost backend found, neither OpenCost nor the pricing ConfigMap %s is available", e.pricingKey))
+	}
+	return p, err
+}
+
+func (e *localEstimator) estimate(ctx context.Context, req *costapi.CostReportRequest, scp *scope, now time.Time) (*costapi.CostReportResponse, error) {
+	window := req.Window
+	if window == "" {
+		window = defaultWindow
+	}
+	start, end, err := parseWindow(window, now)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+	if end.Before(now) {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("window %q ends in the past, the local cost estimator can only price windows that end now", window))
+	}
+	end = now
+
+	keys, err := aggregateKeys(req.Aggregate)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := e.loadPricing(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []client.ListOption
+	if scp.namespace != "" {
+		opts = append(opts, client.InNamespace(scp.namespace))
+	}
+
+	var nodeList core.NodeList
+	if err := e.kc.List(ctx, &nodeList); err != nil {
+		return nil, err
+	}
+	nodes := make(map[string]*core.Node, len(nodeList.Items))
+	for i := range nodeList.Items {
+		nodes[nodeList.Items[i].Name] = &nodeList.Items[i]
+	}
+
+	var podList core.PodList
+	if err := e.kc.List(ctx, &podList, opts...); err != nil {
+		return nil, err
+	}
+	var pvcList core.PersistentVolumeClaimList
+	if err := e.kc.List(ctx, &pvcList, opts...); err != nil {
+		return nil, err
+	}
+	owners, err := listOwners(ctx, e.kc, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var costs []*podCost
+	claimedBy := map[types.NamespacedName][]*podCost{}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Status.Phase == core.PodSucceeded || pod.Status.Phase == core.PodFailed {
+			continue
+		}
+		from := pod.CreationTimestamp.Time
+		if pod.Status.StartTime != nil {
+			from = pod.Status.StartTime.Time
+		}
+		s, hours := overlap(start, end, from)
+		if hours <= 0 {
+			continue
+		}
+
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pod)
+		if err != nil {
+			return nil, err
+		}
+		// items of a typed list do not carry apiVersion and kind
+		content["apiVersion"] = "v1"
+		content["kind"] = "Pod"
+		requests, err := resourcemetrics.TotalResourceRequests(content)
+		if err != nil {
+			return nil, err
+		}
+		amounts := pricing.AmountsOf(requests)
+		rates := p.RatesFor(nodes[pod.Spec.NodeName])
+
+		controller := owners.controllerOf(pod)
+		pc := podCost{
+			props: costapi.CostAllocationProperties{
+				Node:      pod.Spec.NodeName,
+				Namespace: pod.Namespace,
+				Pod:       pod.Name,
+			},
+			alloc: costapi.CostAllocation{
+				Window:  newWindow(s, end),
+				Minutes: hours * 60,
+				CPU: costapi.ResourceCost{
+					Amount:         amounts.CPUCores,
+					AmountHours:    amounts.CPUCores * hours,
+					RequestAverage: amounts.CPUCores,
+					Cost:           amounts.CPUCores * rates.CPUCoreHour * hours,
+				},
+				RAM: costapi.ResourceCost{
+					Amount:         amounts.RAMBytes,
+					AmountHours:    amounts.RAMBytes * hours,
+					RequestAverage: amounts.RAMBytes,
+					Cost:           amounts.RAMGiB() * rates.RAMGiBHour * hours,
+				},
+				GPU: costapi.ResourceCost{
+					Amount:         amounts.GPUs,
+					AmountHours:    amounts.GPUs * hours,
+					RequestAverage: amounts.GPUs,
+					Cost:           amounts.GPUs * rates.GPUHour * hours,
+				},
+			},
+		}
+		if controller.Kind != "Pod" {
+			pc.props.ControllerKind = strings.ToLower(controller.Kind)
+			pc.props.Controller = controller.Name
+		}
+		costs = append(costs, &pc)
+
+		for _, vol := range pod.Spec.Volumes {
+			if vol.PersistentVolumeClaim != nil {
+				key := types.NamespacedName{Namespace: pod.Namespace, Name: vol.PersistentVolumeClaim.ClaimName}
+				claimedBy[key] = append(claimedBy[key], &pc)
+			}
+		}
+	}
+
+	unmounted := map[string]*podCost{}
+	for _, pvc := range pvcList.Items {
+		s, hours := overlap(start, end, pvc.CreationTimestamp.Time)
+		if hours <= 0 {
+			continue
+		}
+		size, ok := pvc.Status.Capacity[core.ResourceStorage]
+		if !ok {
+			size = pvc.Spec.Resources.Requests[core.ResourceStorage]
+		}
+		var sc string
+		if pvc.Spec.StorageClassName != nil {
+			sc = *pvc.Spec.StorageClassName
+		}
+		amounts := pricing.AmountsOf(core.ResourceList{core.ResourceStorage: size})
+		bytes := size.AsApproximateFloat64()
+		cost := amounts.StorageGiB * p.StorageGiBMonth(sc) / pricing.HoursPerMonth * hours
+
+		owners := claimedBy[types.NamespacedName{Namespace: pvc.Namespace, Name: pvc.Name}]
+		if len(owners) == 0 {
+			pc, ok := unmounted[pvc.Namespace]
+			if !ok {
+				pc = &podCost{
+					props: costapi.CostAllocationProperties{
+						Namespace: pvc.Namespace,
+					},
+					alloc: costapi.CostAllocation{
+						Window:  newWindow(s, end),
+						Minutes: hours * 60,
+					},
+				}
+				unmounted[pvc.Namespace] = pc
+				costs = append(costs, pc)
+			}
+			owners = []*podCost{pc}
+		}
+		share := float64(len(owners))
+		for _, pc := range owners {
+			pc.alloc.PersistentVolume.Amount += bytes / share
+			pc.alloc.PersistentVolume.AmountHours += bytes * hours / share
+			pc.alloc.PersistentVolume.Cost += cost / share
+		}
+	}
+
+	var allocations []costapi.CostAllocation
+	for _, pc := range costs {
+		a := pc.alloc
+		a.TotalCost = a.CPU.Cost + a.RAM.Cost + a.GPU.Cost + a.PersistentVolume.Cost
+		props := pc.props
+		a.Properties = &props
+		allocations = append(allocations, a)
+	}
+	allocations = aggregateAllocations(allocations, keys)
+
+	if req.IncludeIdle {
+		if idle := estimateIdle(p, nodeList.Items, allocations, start, end); idle != nil {
+			allocations = append(allocations, *idle)
+		}
+	}
+	sort.Slice(allocations, func(i, j int) bool {
+		return allocations[i].Name < allocations[j].Name
+	})
+
+	cs := costapi.CostAllocationSet{
+		Window:      newWindow(start, end),
+		Allocations: allocations,
+	}
+	if cs.Allocations == nil {
+		cs.Allocations = []costapi.CostAllocation{}
+	}
+	resp := costapi.CostReportResponse{
+		Backend:  costapi.CostBackendLocal,
+		Currency: p.Currency,
+		Sets:     []costapi.CostAllocationSet{cs},
+	}
+	summarize(&resp)
+	return &resp, nil
+}
+
+// ownerIndex maps ReplicaSets and Jobs to their controllers, so that the top level controller
+// of a pod is found without a request per pod.
+type ownerIndex map[kmapi.ObjectID]kmapi.ObjectID
+
+var intermediateOwners = []schema.GroupVersionKind{
+	appsv1.SchemeGroupVersion.WithKind("ReplicaSetList"),
+	batchv1.SchemeGroupVersion.WithKind("JobList"),
+}
+
+func listOwners(ctx context.Context, kc client.Client, opts ...client.ListOption) (ownerIndex, error) {
+	idx := ownerIndex{}
+	for _, gvk := range intermediateOwners {
+		var list metav1.PartialObjectMetadataList
+		list.SetGroupVersionKind(gvk)
+		if err := kc.List(ctx, &list, opts...); meta.IsNoMatchError(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if owner, ok := controllerRef(obj); ok {
+				idx[kmapi.ObjectID{
+					Group:     gvk.Group,
+					Kind:      strings.TrimSuffix(gvk.Kind, "List"),
+					Namespace: obj.Namespace,
+					Name:      obj.Name,
+				}] = owner
+			}
+		}
+	}
+	return idx, nil
+}
+
+// controllerOf returns the top level controller of a pod, or the pod itself if it has no controller.
+func (idx ownerIndex) controllerOf(pod *core.Pod) kmapi.ObjectID {
+	cur, ok := controllerRef(pod)
+	if !ok {
+		return kmapi.ObjectID{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name}
+	}
+	for range maxOwnerDepth {
+		owner, ok := idx[cur]
+		if !ok {
+			break
+		}
+		cur = owner
+	}
+	return cur
+}
+
+func controllerRef(obj metav1.Object) (kmapi.ObjectID, bool) {
+	ref := metav1.GetControllerOf(obj)
+	if ref == nil {
+		return kmapi.ObjectID{}, false
+	}
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return kmapi.ObjectID{}, false
+	}
+	return kmapi.ObjectID{
+		Group:     gv.Group,
+		Kind:      ref.Kind,
+		Namespace: obj.GetNamespace(),
+		Name:      ref.Name,
+	}, true
+}
+
+// estimateIdle returns the cost of the node capacity that is not requested by any pod.
+func estimateIdle(p *pricing.Pricing, nodes []core.Node, allocations []costapi.CostAllocation, start, end time.Time) *costapi.CostAllocation {
+	var cpuCost, ramCost float64
+	for i := range nodes {
+		_, hours := overlap(start, end, nodes[i].CreationTimestamp.Time)
+		if hours <= 0 {
+			continue
+		}
+		rates := p.RatesFor(&nodes[i])
+		amounts := pricing.AmountsOf(nodes[i].Status.Allocatable)
+		cpuCost += amounts.CPUCores * rates.CPUCoreHour * hours
+		ramCost += amounts.RAMGiB() * rates.RAMGiBHour * hours
+	}
+	for _, a := range allocations {
+		cpuCost -= a.CPU.Cost
+		ramCost -= a.RAM.Cost
+	}
+	cpuCost = max(cpuCost, 0)
+	ramCost = max(ramCost, 0)
+	if cpuCost+ramCost == 0 {
+		return nil
+	}
+	return &costapi.CostAllocation{
+		Name:      idleAllocation,
+		Window:    newWindow(start, end),
+		Minutes:   end.Sub(start).Minutes(),
+		CPU:       costapi.ResourceCost{Cost: cpuCost},
+		RAM:       costapi.ResourceCost{Cost: ramCost},
+		TotalCost: cpuCost + ramCost,
+	}
+}
+
+var supportedAggregates = map[string]func(p *costapi.CostAllocationProperties) string{
+	"node": func(p *costapi.CostAllocationProperties) string { return p.Node },
+	aggregateNamespace: func(p *costapi.CostAllocationProperties) string {
+		return p.Namespace
+	},
+	"controllerKind": func(p *costapi.CostAllocationProperties) string { return p.ControllerKind },
+	"controller": func(p *costapi.CostAllocationProperties) string {
+		if p.Controller == "" {
+			return ""
+		}
+		return p.ControllerKind + ":" + p.Controller
+	},
+	aggregatePod: func(p *costapi.CostAllocationProperties) string { return p.Pod },
+}
+
+func aggregateKeys(in []string) ([]string, error) {
+	if len(in) == 0 {
+		return []string{aggregateNamespace, aggregatePod}, nil
+	}
+	for _, k := range in {
+		if _, ok := supportedAggregates[k]; !ok {
+			return nil, apierrors.NewBadRequest(fmt.Sprintf("aggregate %q is not supported by the local cost estimator", k))
+		}
+	}
+	return in, nil
+}
+
+// aggregateAllocations merges allocations with the same values for the keys, the same way OpenCost
+// names aggregated allocations. Properties shared by all merged allocations are kept.
+func aggregateAllocations(in []costapi.CostAllocation, keys []string) []costapi.CostAllocation {
+	merged := map[string]*costapi.CostAllocation{}
+	var names []string
+	for _, a := range in {
+		values := make([]string, 0, len(keys))
+		for _, k := range keys {
+			v := supportedAggregates[k](a.Properties)
+			if v == "" {
+				v = unallocatedAllocation
+				if k == aggregatePod && a.Properties.Pod == "" {
+					v = unmountedAllocation
+				}
+			}
+			values = append(values, v)
+		}
+		name := strings.Join(values, "/")
+
+		m, ok := merged[name]
+		if !ok {
+			a.Name = name
+			merged[name] = &a
+			names = append(names, name)
+			continue
+		}
+		m.Window = unionWindow(m.Window, a.Window)
+		m.Minutes = max(m.Minutes, a.Minutes)
+		addResourceCost(&m.CPU, a.CPU)
+		addResourceCost(&m.RAM, a.RAM)
+		addResourceCost(&m.GPU, a.GPU)
+		addResourceCost(&m.PersistentVolume, a.PersistentVolume)
+		m.TotalCost += a.TotalCost
+		m.Properties = commonProperties(m.Properties, a.Properties)
+	}
+
+	out := make([]costapi.CostAllocation, 0, len(names))
+	for _, name := range names {
+		out = append(out, *merged[name])
+	}
+	return out
+}
+
+func addResourceCost(dst *costapi.ResourceCost, src costapi.ResourceCost) {
+	dst.Amount += src.Amount
+	dst.AmountHours += src.AmountHours
+	dst.RequestAverage += src.RequestAverage
+	dst.Cost += src.Cost
+}
+
+func commonProperties(a, b *costapi.CostAllocationProperties) *costapi.CostAllocationProperties {
+	same := func(x, y string) string {
+		if x == y {
+			return x
+		}
+		return ""
+	}
+	out := costapi.CostAllocationProperties{
+		Node:      same(a.Node, b.Node),
+		Namespace: same(a.Namespace, b.Namespace),
+		Pod:       same(a.Pod, b.Pod),
+	}
+	if a.ControllerKind == b.ControllerKind && a.Controller == b.Controller {
+		out.ControllerKind = a.ControllerKind
+		out.Controller = a.Controller
+	}
+	return &out
+}
+
+// overlap returns the start and the hours of the part of the window after from.
+func overlap(start, end, from time.Time) (time.Time, float64) {
+	if from.After(start) {
+		start = from
+	}
+	if !start.Before(end) {
+		return start, 0
+	}
+	return start, end.Sub(start).Hours()
+}
+
+func newWindow(start, end time.Time) costapi.CostWindow {
+	return costapi.CostWindow{
+		Start: &metav1.Time{Time: start},
+		End:   &metav1.Time{Time: end},
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reports
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	costapi "kubeops.dev/ui-server/apis/cost/v1alpha1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	kmapi "kmodules.xyz/client-go/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParseWindow(t *testing.T) {
+	now := time.Date(2024, 3, 14, 15, 0, 0, 0, time.UTC) // Thursday
+	tests := []struct {
+		window     string
+		start, end time.Time
+	}{
+		{"1d", now.Add(-24 * time.Hour), now},
+		{"90m", now.Add(-90 * time.Minute), now},
+		{"today", time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC), now},
+		{"lastweek", time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{"lastmonth", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"2024-03-01T00:00:00Z,2024-03-02T00:00:00Z", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		start, end, err := parseWindow(tt.window, now)
+		if err != nil {
+			t.Errorf("%s: %v", tt.window, err)
+			continue
+		}
+		if !start.Equal(tt.start) || !end.Equal(tt.end) {
+			t.Errorf("%s: expected [%s, %s], got [%s, %s]", tt.window, tt.start, tt.end, start, end)
+		}
+	}
+	for _, window := range []string{"", "1y", "2024-03-02T00:00:00Z,2024-03-01T00:00:00Z"} {
+		if _, _, err := parseWindow(window, now); err == nil {
+			t.Errorf("%q: expected error", window)
+		}
+	}
+}
+
+func TestLocalEstimate(t *testing.T) {
+	now := time.Now()
+	created := metav1.NewTime(now.Add(-48 * time.Hour))
+	storageClass := "gp3"
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	kc := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&core.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "cost-pricing", Namespace: "kubeops"},
+			Data: map[string]string{
+				"pricing.yaml": "currency: USD\ndefault:\n  cpuCoreHour: 0.04\n  ramGiBHour: 0.005\nstorageClasses:\n  gp3: 73\n",
+			},
+		},
+		&core.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1", CreationTimestamp: created},
+			Status: core.NodeStatus{
+				Allocatable: core.ResourceList{
+					core.ResourceCPU:    resource.MustParse("2"),
+					core.ResourceMemory: resource.MustParse("4Gi"),
+				},
+			},
+		},
+		&core.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "demo", CreationTimestamp: created},
+			Spec: core.PodSpec{
+				NodeName: "node-1",
+				Containers: []core.Container{
+					{
+						Name: "db",
+						Resources: core.ResourceRequirements{
+							Requests: core.ResourceList{
+								core.ResourceCPU:    resource.MustParse("1"),
+								core.ResourceMemory: resource.MustParse("2Gi"),
+							},
+						},
+					},
+				},
+				Volumes: []core.Volume{
+					{
+						Name: "data",
+						VolumeSource: core.VolumeSource{
+							PersistentVolumeClaim: &core.PersistentVolumeClaimVolumeSource{ClaimName: "data-db-0"},
+						},
+					},
+				},
+			},
+			Status: core.PodStatus{Phase: core.PodRunning},
+		},
+		&core.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data-db-0", Namespace: "demo", CreationTimestamp: created},
+			Spec: core.PersistentVolumeClaimSpec{
+				StorageClassName: &storageClass,
+				Resources: core.VolumeResourceRequirements{
+					Requests: core.ResourceList{
+						core.ResourceStorage: resource.MustParse("10Gi"),
+					},
+				},
+			},
+		},
+	).Build()
+
+	e := &localEstimator{
+		kc:         kc,
+		pricingKey: client.ObjectKey{Namespace: "kubeops", Name: "cost-pricing"},
+	}
+	resp, err := e.estimate(context.Background(), &costapi.CostReportRequest{
+		Window:      "10h",
+		Aggregate:   []string{aggregateNamespace},
+		IncludeIdle: true,
+	}, &scope{isCluster: true}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Backend != costapi.CostBackendLocal || resp.Currency != "USD" {
+		t.Errorf("unexpected backend %s %s", resp.Backend, resp.Currency)
+	}
+	if len(resp.Sets) != 1 || len(resp.Sets[0].Allocations) != 2 {
+		t.Fatalf("unexpected allocations %+v", resp.Sets)
+	}
+
+	idle, demo := resp.Sets[0].Allocations[0], resp.Sets[0].Allocations[1]
+	approx := func(x, y float64) bool { return math.Abs(x-y) < 1e-6 }
+	// 1 core * 0.04 * 10h, 2GiB * 0.005 * 10h and 10GiB * 73 / 730 * 10h
+	if demo.Name != "demo" || !approx(demo.CPU.Cost, 0.4) || !approx(demo.RAM.Cost, 0.1) || !approx(demo.PersistentVolume.Cost, 10) {
+		t.Errorf("unexpected allocation %+v", demo)
+	}
+	// the other core and 2GiB of the node are not requested
+	if idle.Name != idleAllocation || !approx(idle.TotalCost, 0.5) {
+		t.Errorf("unexpected idle allocation %+v", idle)
+	}
+	if !approx(resp.Totals.TotalCost, 11) {
+		t.Errorf("unexpected total cost %v", resp.Totals.TotalCost)
+	}
+
+	if _, err := e.estimate(context.Background(), &costapi.CostReportRequest{
+		Aggregate: []string{"label:app"},
+	}, &scope{isCluster: true}, now); err == nil {
+		t.Error("expected error for unsupported aggregate")
+	}
+}
+
+func TestLocalEstimatePastWindow(t *testing.T) {
+	e := &localEstimator{}
+	_, err := e.estimate(context.Background(), &costapi.CostReportRequest{Window: "yesterday"}, &scope{isCluster: true}, time.Now())
+	if !apierrors.IsBadRequest(err) {
+		t.Errorf("expected bad request, got %v", err)
+	}
+}
+
+func TestOwnerIndex(t *testing.T) {
+	ctrl := true
+	ownedBy := func(apiVersion, kind, name string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{APIVersion: apiVersion, Kind: kind, Name: name, Controller: &ctrl}}
+	}
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	kc := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name: "web-6d4b", Namespace: "demo", OwnerReferences: ownedBy("apps/v1", "Deployment", "web"),
+		}},
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+			Name: "backup-1", Namespace: "demo", OwnerReferences: ownedBy("batch/v1", "CronJob", "backup"),
+		}},
+	).Build()
+
+	idx, err := listOwners(context.Background(), kc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		pod  *core.Pod
+		want kmapi.ObjectID
+	}{
+		{
+			pod:  &core.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-6d4b-x", Namespace: "demo", OwnerReferences: ownedBy("apps/v1", "ReplicaSet", "web-6d4b")}},
+			want: kmapi.ObjectID{Group: "apps", Kind: "Deployment", Namespace: "demo", Name: "web"},
+		},
+		{
+			pod:  &core.Pod{ObjectMeta: metav1.ObjectMeta{Name: "backup-1-x", Namespace: "demo", OwnerReferences: ownedBy("batch/v1", "Job", "backup-1")}},
+			want: kmapi.ObjectID{Group: "batch", Kind: "CronJob", Namespace: "demo", Name: "backup"},
+		},
+		{
+			pod:  &core.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "demo", OwnerReferences: ownedBy("apps/v1", "StatefulSet", "db")}},
+			want: kmapi.ObjectID{Group: "apps", Kind: "StatefulSet", Namespace: "demo", Name: "db"},
+		},
+		{
+			pod:  &core.Pod{ObjectMeta: metav1.ObjectMeta{Name: "debug", Namespace: "demo"}},
+			want: kmapi.ObjectID{Kind: "Pod", Namespace: "demo", Name: "debug"},
+		},
+	}
+	for _, tt := range tests {
+		if got := idx.controllerOf(tt.pod); got != tt.want {
+			t.Errorf("%s: expected %+v, got %+v", tt.pod.Name, tt.want, got)
+		}
+	}
+}
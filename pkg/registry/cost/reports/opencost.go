@@ -0,0 +1,325 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reports
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	costapi "kubeops.dev/ui-server/apis/cost/v1alpha1"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OpenCost wraps every api response in the same envelope. Errors may be
+// reported with a 200 status code, so the code in the body has to be checked too.
+// ref: https://www.opencost.io/docs/integrations/api
+type ocResponse struct {
+	Code    int                        `json:"code"`
+	Status  string                     `json:"status"`
+	Data    []map[string]*ocAllocation `json:"data"`
+	Message string                     `json:"message"`
+	Warning string                     `json:"warning"`
+}
+
+type ocWindow struct {
+	Start *time.Time `json:"start"`
+	End   *time.Time `json:"end"`
+}
+
+type ocProperties struct {
+	Cluster        string            `json:"cluster"`
+	Node           string            `json:"node"`
+	Container      string            `json:"container"`
+	Controller     string            `json:"controller"`
+	ControllerKind string            `json:"controllerKind"`
+	Namespace      string            `json:"namespace"`
+	Pod            string            `json:"pod"`
+	Services       []string          `json:"services"`
+	ProviderID     string            `json:"providerID"`
+	Labels         map[string]string `json:"labels"`
+	Annotations    map[string]string `json:"annotations"`
+}
+
+type ocAllocation struct {
+	Name       string        `json:"name"`
+	Properties *ocProperties `json:"properties"`
+	Window     ocWindow      `json:"window"`
+	Minutes    float64       `json:"minutes"`
+
+	CPUCores              float64 `json:"cpuCores"`
+	CPUCoreRequestAverage float64 `json:"cpuCoreRequestAverage"`
+	CPUCoreUsageAverage   float64 `json:"cpuCoreUsageAverage"`
+	CPUCoreHours          float64 `json:"cpuCoreHours"`
+	CPUCost               float64 `json:"cpuCost"`
+	CPUCostAdjustment     float64 `json:"cpuCostAdjustment"`
+	CPUEfficiency         float64 `json:"cpuEfficiency"`
+
+	GPUCount          float64 `json:"gpuCount"`
+	GPUHours          float64 `json:"gpuHours"`
+	GPUCost           float64 `json:"gpuCost"`
+	GPUCostAdjustment float64 `json:"gpuCostAdjustment"`
+
+	NetworkTransferBytes   float64 `json:"networkTransferBytes"`
+	NetworkReceiveBytes    float64 `json:"networkReceiveBytes"`
+	NetworkCost            float64 `json:"networkCost"`
+	NetworkCrossZoneCost   float64 `json:"networkCrossZoneCost"`
+	NetworkCrossRegionCost float64 `json:"networkCrossRegionCost"`
+	NetworkInternetCost    float64 `json:"networkInternetCost"`
+	NetworkCostAdjustment  float64 `json:"networkCostAdjustment"`
+
+	LoadBalancerCost           float64 `json:"loadBalancerCost"`
+	LoadBalancerCostAdjustment float64 `json:"loadBalancerCostAdjustment"`
+
+	PVBytes          float64 `json:"pvBytes"`
+	PVByteHours      float64 `json:"pvByteHours"`
+	PVCost           float64 `json:"pvCost"`
+	PVCostAdjustment float64 `json:"pvCostAdjustment"`
+
+	RAMBytes              float64 `json:"ramBytes"`
+	RAMByteRequestAverage float64 `json:"ramByteRequestAverage"`
+	RAMByteUsageAverage   float64 `json:"ramByteUsageAverage"`
+	RAMByteHours          float64 `json:"ramByteHours"`
+	RAMCost               float64 `json:"ramCost"`
+	RAMCostAdjustment     float64 `json:"ramCostAdjustment"`
+	RAMEfficiency         float64 `json:"ramEfficiency"`
+
+	ExternalCost    float64 `json:"externalCost"`
+	SharedCost      float64 `json:"sharedCost"`
+	TotalCost       float64 `json:"totalCost"`
+	TotalEfficiency float64 `json:"totalEfficiency"`
+}
+
+// decodeAllocations converts the response of the OpenCost allocation api into a CostReportResponse.
+// Errors reported by OpenCost are returned as api status errors.
+func decodeAllocations(statusCode int, body []byte) (*costapi.CostReportResponse, error) {
+	var oc ocResponse
+	if err := json.Unmarshal(body, &oc); err != nil {
+		if statusCode != http.StatusOK {
+			return nil, upstreamError(statusCode, strings.TrimSpace(string(body)))
+		}
+		return nil, apierrors.NewInternalError(fmt.Errorf("failed to decode opencost api response: %w", err))
+	}
+	if statusCode != http.StatusOK {
+		return nil, upstreamError(statusCode, oc.Message)
+	}
+	if oc.Code != 0 && oc.Code != http.StatusOK {
+		return nil, upstreamError(oc.Code, oc.Message)
+	}
+	if oc.Data == nil {
+		msg := oc.Message
+		if msg == "" {
+			msg = "response contains no data"
+		}
+		return nil, upstreamError(http.StatusInternalServerError, msg)
+	}
+
+	resp := costapi.CostReportResponse{
+		Backend: costapi.CostBackendOpenCost,
+		Sets:    make([]costapi.CostAllocationSet, 0, len(oc.Data)),
+	}
+	for _, set := range oc.Data {
+		names := make([]string, 0, len(set))
+		for name, a := range set {
+			if a != nil {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		cs := costapi.CostAllocationSet{
+			Allocations: make([]costapi.CostAllocation, 0, len(names)),
+		}
+		for _, name := range names {
+			a, err := convertAllocation(name, set[name])
+			if err != nil {
+				return nil, err
+			}
+			cs.Window = unionWindow(cs.Window, a.Window)
+			cs.Allocations = append(cs.Allocations, *a)
+		}
+		resp.Sets = append(resp.Sets, cs)
+	}
+	summarize(&resp)
+	return &resp, nil
+}
+
+// summarize computes the window and totals of a response from its allocation sets.
+func summarize(resp *costapi.CostReportResponse) {
+	resp.Window = costapi.CostWindow{}
+	var allocations []costapi.CostAllocation
+	for _, cs := range resp.Sets {
+		resp.Window = unionWindow(resp.Window, cs.Window)
+		allocations = append(allocations, cs.Allocations...)
+	}
+	resp.Totals = sumAllocations(allocations)
+}
+
+func sumAllocations(allocations []costapi.CostAllocation) costapi.CostTotals {
+	var totals costapi.CostTotals
+	var weightedEfficiency, efficiencyCost float64
+	for _, a := range allocations {
+		totals.CPUCost += a.CPU.Cost + a.CPU.CostAdjustment
+		totals.GPUCost += a.GPU.Cost + a.GPU.CostAdjustment
+		totals.RAMCost += a.RAM.Cost + a.RAM.CostAdjustment
+		totals.PersistentVolumeCost += a.PersistentVolume.Cost + a.PersistentVolume.CostAdjustment
+		totals.NetworkCost += a.Network.Cost + a.Network.CostAdjustment
+		totals.LoadBalancerCost += a.LoadBalancer.Cost + a.LoadBalancer.CostAdjustment
+		totals.SharedCost += a.SharedCost
+		totals.ExternalCost += a.ExternalCost
+		totals.TotalCost += a.TotalCost
+
+		c := cpuAndRAMCost(a)
+		weightedEfficiency += a.Efficiency.Total * c
+		efficiencyCost += c
+	}
+	if efficiencyCost > 0 {
+		totals.TotalEfficiency = weightedEfficiency / efficiencyCost
+	}
+	return totals
+}
+
+func convertAllocation(name string, a *ocAllocation) (*costapi.CostAllocation, error) {
+	if a.Name != "" {
+		name = a.Name
+	}
+	if a.Window.Start != nil && a.Window.End != nil && a.Window.End.Before(*a.Window.Start) {
+		return nil, apierrors.NewInternalError(fmt.Errorf("opencost allocation %s has invalid window [%s, %s]", name, a.Window.Start, a.Window.End))
+	}
+	if a.TotalCost < 0 || math.IsNaN(a.TotalCost) || math.IsInf(a.TotalCost, 0) {
+		return nil, apierrors.NewInternalError(fmt.Errorf("opencost allocation %s has invalid total cost %v", name, a.TotalCost))
+	}
+
+	out := costapi.CostAllocation{
+		Name:    name,
+		Window:  toWindow(a.Window),
+		Minutes: a.Minutes,
+		CPU: costapi.ResourceCost{
+			Amount:         a.CPUCores,
+			AmountHours:    a.CPUCoreHours,
+			RequestAverage: a.CPUCoreRequestAverage,
+			UsageAverage:   a.CPUCoreUsageAverage,
+			Cost:           a.CPUCost,
+			CostAdjustment: a.CPUCostAdjustment,
+		},
+		GPU: costapi.ResourceCost{
+			Amount:         a.GPUCount,
+			AmountHours:    a.GPUHours,
+			Cost:           a.GPUCost,
+			CostAdjustment: a.GPUCostAdjustment,
+		},
+		RAM: costapi.ResourceCost{
+			Amount:         a.RAMBytes,
+			AmountHours:    a.RAMByteHours,
+			RequestAverage: a.RAMByteRequestAverage,
+			UsageAverage:   a.RAMByteUsageAverage,
+			Cost:           a.RAMCost,
+			CostAdjustment: a.RAMCostAdjustment,
+		},
+		PersistentVolume: costapi.ResourceCost{
+			Amount:         a.PVBytes,
+			AmountHours:    a.PVByteHours,
+			Cost:           a.PVCost,
+			CostAdjustment: a.PVCostAdjustment,
+		},
+		Network: costapi.NetworkCost{
+			TransferBytes:   a.NetworkTransferBytes,
+			ReceiveBytes:    a.NetworkReceiveBytes,
+			CrossZoneCost:   a.NetworkCrossZoneCost,
+			CrossRegionCost: a.NetworkCrossRegionCost,
+			InternetCost:    a.NetworkInternetCost,
+			Cost:            a.NetworkCost,
+			CostAdjustment:  a.NetworkCostAdjustment,
+		},
+		LoadBalancer: costapi.ResourceCost{
+			Cost:           a.LoadBalancerCost,
+			CostAdjustment: a.LoadBalancerCostAdjustment,
+		},
+		SharedCost:   a.SharedCost,
+		ExternalCost: a.ExternalCost,
+		TotalCost:    a.TotalCost,
+		Efficiency: costapi.CostEfficiency{
+			CPU:   a.CPUEfficiency,
+			RAM:   a.RAMEfficiency,
+			Total: a.TotalEfficiency,
+		},
+	}
+	if p := a.Properties; p != nil {
+		out.Properties = &costapi.CostAllocationProperties{
+			Cluster:        p.Cluster,
+			Node:           p.Node,
+			Namespace:      p.Namespace,
+			ControllerKind: p.ControllerKind,
+			Controller:     p.Controller,
+			Pod:            p.Pod,
+			Container:      p.Container,
+			Services:       p.Services,
+			ProviderID:     p.ProviderID,
+			Labels:         p.Labels,
+			Annotations:    p.Annotations,
+		}
+	}
+	return &out, nil
+}
+
+func toWindow(w ocWindow) costapi.CostWindow {
+	var out costapi.CostWindow
+	if w.Start != nil {
+		out.Start = &metav1.Time{Time: *w.Start}
+	}
+	if w.End != nil {
+		out.End = &metav1.Time{Time: *w.End}
+	}
+	return out
+}
+
+func unionWindow(w, other costapi.CostWindow) costapi.CostWindow {
+	if other.Start != nil && (w.Start == nil || other.Start.Before(w.Start)) {
+		w.Start = other.Start.DeepCopy()
+	}
+	if other.End != nil && (w.End == nil || w.End.Before(other.End)) {
+		w.End = other.End.DeepCopy()
+	}
+	return w
+}
+
+// upstreamError maps an error reported by OpenCost to an api status error.
+// Errors caused by the request are returned as is, everything else means the
+// cost backend is not working and is reported as such.
+func upstreamError(code int, msg string) error {
+	if msg == "" {
+		msg = http.StatusText(code)
+	}
+	msg = "opencost: " + msg
+	switch code {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return apierrors.NewBadRequest(msg)
+	case http.StatusTooManyRequests:
+		return apierrors.NewTooManyRequests(msg, 0)
+	case http.StatusGatewayTimeout:
+		return apierrors.NewTimeoutError(msg, 0)
+	case http.StatusNotFound, http.StatusBadGateway, http.StatusServiceUnavailable:
+		return apierrors.NewServiceUnavailable(msg)
+	}
+	return apierrors.NewInternalError(fmt.Errorf("%s (code %d)", msg, code))
+}
@@ -0,0 +1,124 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reports
+
+import (
+	"net/http"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const allocationBody = `{
+  "code": 200,
+  "status": "success",
+  "data": [
+    {
+      "demo": {
+        "name": "demo",
+        "properties": {"cluster": "default-cluster", "namespace": "demo"},
+        "window": {"start": "2024-01-01T00:00:00Z", "end": "2024-01-02T00:00:00Z"},
+        "minutes": 1440,
+        "cpuCores": 0.5, "cpuCoreHours": 12, "cpuCost": 0.3, "cpuEfficiency": 0.5,
+        "ramBytes": 1073741824, "ramByteHours": 25769803776, "ramCost": 0.1, "ramEfficiency": 0.25,
+        "pvCost": 0.05, "networkCost": 0.01,
+        "totalCost": 0.46, "totalEfficiency": 0.4375
+      },
+      "__idle__": {
+        "name": "__idle__",
+        "window": {"start": "2024-01-01T00:00:00Z", "end": "2024-01-02T00:00:00Z"},
+        "cpuCost": 1, "ramCost": 1, "totalCost": 2, "totalEfficiency": 0
+      }
+    }
+  ]
+}`
+
+func TestDecodeAllocations(t *testing.T) {
+	resp, err := decodeAllocations(http.StatusOK, []byte(allocationBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Sets) != 1 || len(resp.Sets[0].Allocations) != 2 {
+		t.Fatalf("unexpected sets %+v", resp.Sets)
+	}
+	// allocations are sorted by name
+	a := resp.Sets[0].Allocations[1]
+	if a.Name != "demo" || a.Properties == nil || a.Properties.Namespace != "demo" {
+		t.Errorf("unexpected allocation %+v", a)
+	}
+	if a.CPU.Amount != 0.5 || a.RAM.Cost != 0.1 || a.PersistentVolume.Cost != 0.05 {
+		t.Errorf("unexpected resource costs %+v", a)
+	}
+	if got := resp.Totals.TotalCost; got != 2.46 {
+		t.Errorf("expected total cost 2.46, got %v", got)
+	}
+	if resp.Window.Start == nil || resp.Window.End == nil || resp.Window.End.Sub(resp.Window.Start.Time).Hours() != 24 {
+		t.Errorf("unexpected window %+v", resp.Window)
+	}
+	// (0.4375 * 0.4 + 0 * 2) / 2.4
+	if got := resp.Totals.TotalEfficiency; got < 0.0729 || got > 0.073 {
+		t.Errorf("unexpected total efficiency %v", got)
+	}
+}
+
+func TestDecodeAllocationsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		code  int
+		body  string
+		check func(error) bool
+	}{
+		{
+			name:  "error with status ok",
+			code:  http.StatusOK,
+			body:  `{"code": 400, "status": "", "data": null, "message": "Error parsing window (invalid)"}`,
+			check: apierrors.IsBadRequest,
+		},
+		{
+			name:  "bad request",
+			code:  http.StatusBadRequest,
+			body:  `{"code": 400, "message": "invalid aggregation"}`,
+			check: apierrors.IsBadRequest,
+		},
+		{
+			name:  "not json",
+			code:  http.StatusBadGateway,
+			body:  `upstream connect error`,
+			check: apierrors.IsServiceUnavailable,
+		},
+		{
+			name:  "missing data",
+			code:  http.StatusOK,
+			body:  `{"code": 200, "status": "success"}`,
+			check: apierrors.IsInternalError,
+		},
+		{
+			name:  "invalid json",
+			code:  http.StatusOK,
+			body:  `{"code": 200, "data": [`,
+			check: apierrors.IsInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeAllocations(tt.code, []byte(tt.body))
+			if err == nil || !tt.check(err) {
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+}
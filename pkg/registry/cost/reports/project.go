@@ -0,0 +1,84 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reports
+
+import (
+	"sort"
+	"strings"
+
+	costapi "kubeops.dev/ui-server/apis/cost/v1alpha1"
+
+	"gomodules.xyz/sets"
+)
+
+const idleAllocation = "__idle__"
+
+// projectCosts groups namespace allocations by project and shares the idle cost
+// among the projects in proportion to their cpu and memory cost.
+func projectCosts(resp *costapi.CostReportResponse, projectOf map[string]string) []costapi.ProjectCost {
+	var idleCost, resourceCost float64
+	allocations := map[string][]costapi.CostAllocation{}
+	namespaces := map[string]sets.String{}
+	for _, cs := range resp.Sets {
+		for _, a := range cs.Allocations {
+			if strings.Contains(a.Name, idleAllocation) {
+				idleCost += a.TotalCost
+				continue
+			}
+
+			ns := a.Name
+			if a.Properties != nil && a.Properties.Namespace != "" {
+				ns = a.Properties.Namespace
+			}
+			prj, found := projectOf[ns]
+			if !found {
+				prj = costapi.ProjectUnassigned
+			}
+			allocations[prj] = append(allocations[prj], a)
+			if _, found := namespaces[prj]; !found {
+				namespaces[prj] = sets.NewString()
+			}
+			namespaces[prj].Insert(ns)
+			resourceCost += cpuAndRAMCost(a)
+		}
+	}
+
+	result := make([]costapi.ProjectCost, 0, len(allocations))
+	for prj, list := range allocations {
+		pc := costapi.ProjectCost{
+			Project:    prj,
+			Namespaces: namespaces[prj].List(),
+			Totals:     sumAllocations(list),
+		}
+		if resourceCost > 0 {
+			var c float64
+			for _, a := range list {
+				c += cpuAndRAMCost(a)
+			}
+			pc.IdleCost = idleCost * c / resourceCost
+		}
+		result = append(result, pc)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Project < result[j].Project
+	})
+	return result
+}
+
+func cpuAndRAMCost(a costapi.CostAllocation) float64 {
+	return a.CPU.Cost + a.CPU.CostAdjustment + a.RAM.Cost + a.RAM.CostAdjustment
+}
@@ -0,0 +1,66 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reports
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	costapi "kubeops.dev/ui-server/apis/cost/v1alpha1"
+)
+
+func TestProjectCosts(t *testing.T) {
+	const body = `{
+  "code": 200,
+  "data": [
+    {
+      "team-a-dev": {"properties": {"namespace": "team-a-dev"}, "cpuCost": 1, "ramCost": 1, "totalCost": 2, "totalEfficiency": 0.5},
+      "team-a-prod": {"properties": {"namespace": "team-a-prod"}, "cpuCost": 3, "ramCost": 1, "totalCost": 4, "totalEfficiency": 0.25},
+      "team-b": {"properties": {"namespace": "team-b"}, "cpuCost": 2, "totalCost": 2},
+      "__idle__": {"cpuCost": 4, "ramCost": 2, "totalCost": 6}
+    }
+  ]
+}`
+	resp, err := decodeAllocations(http.StatusOK, []byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	projects := projectCosts(resp, map[string]string{
+		"team-a-dev":  "team-a",
+		"team-a-prod": "team-a",
+	})
+	if len(projects) != 2 {
+		t.Fatalf("unexpected projects %+v", projects)
+	}
+
+	// __unassigned__ sorts before team-a
+	unassigned, teamA := projects[0], projects[1]
+	if unassigned.Project != costapi.ProjectUnassigned || unassigned.IdleCost != 1.5 {
+		t.Errorf("unexpected unassigned cost %+v", unassigned)
+	}
+	if teamA.Project != "team-a" || !reflect.DeepEqual(teamA.Namespaces, []string{"team-a-dev", "team-a-prod"}) {
+		t.Errorf("unexpected project %+v", teamA)
+	}
+	if teamA.Totals.TotalCost != 6 || teamA.IdleCost != 4.5 {
+		t.Errorf("unexpected project cost %+v", teamA)
+	}
+	// (0.5 * 2 + 0.25 * 4) / 6
+	if got := teamA.Totals.TotalEfficiency; got < 0.333 || got > 0.334 {
+		t.Errorf("unexpected efficiency %v", got)
+	}
+}
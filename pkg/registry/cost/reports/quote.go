@@ -0,0 +1,143 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reports
+
+import (
+	"context"
+	"time"
+
+	costapi "kubeops.dev/ui-server/apis/cost/v1alpha1"
+	"kubeops.dev/ui-server/pkg/opencost"
+	"kubeops.dev/ui-server/pkg/registry/cost/pricing"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// quoteWindow is long enough to average out the price of spot nodes.
+	quoteWindow = "7d"
+	quoteTTL    = 10 * time.Minute
+	quoteErrTTL = 30 * time.Second
+)
+
+var _ pricing.Quoter = &Storage{}
+
+// Quote returns the rates of the configured cost backend. OpenCost does not expose its price
+// list, so the rates are derived from the cost of the cluster over the last week.
+//
+// Quotes are cached, and failures are cached for a short while, so that a missing backend does
+// not slow down every caller. Concurrent callers share a single fetch.
+func (r *Storage) Quote(ctx context.Context) (*pricing.Quote, error) {
+	r.quoteMu.Lock()
+	q, err, quotedAt := r.quote, r.quoteErr, r.quotedAt
+	r.quoteMu.Unlock()
+	if err != nil && time.Since(quotedAt) < quoteErrTTL {
+		return nil, err
+	} else if err == nil && q != nil && time.Since(quotedAt) < quoteTTL {
+		return q, nil
+	}
+
+	v, err, _ := r.quotes.Do("", func() (any, error) {
+		// the fetch is shared, so it must not be canceled with the request that started it.
+		// It is bounded by the timeout of the OpenCost client.
+		q, err := r.fetchQuote(context.WithoutCancel(ctx))
+
+		r.quoteMu.Lock()
+		defer r.quoteMu.Unlock()
+		r.quote, r.quoteErr, r.quotedAt = q, err, time.Now()
+		return q, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*pricing.Quote), nil
+}
+
+func (r *Storage) fetchQuote(ctx context.Context) (*pricing.Quote, error) {
+	switch r.backend {
+	case BackendLocal:
+		return r.local.quote(ctx)
+	case BackendOpenCost:
+		return r.quoteOpenCost(ctx)
+	default:
+		q, err := r.quoteOpenCost(ctx)
+		if errors.Is(err, opencost.ErrNotFound) {
+			return r.local.quote(ctx)
+		}
+		return q, err
+	}
+}
+
+func (r *Storage) quoteOpenCost(ctx context.Context) (*pricing.Quote, error) {
+	resp, err := r.queryOpenCost(ctx, &costapi.CostReportRequest{
+		Window:     quoteWindow,
+		Aggregate:  []string{"cluster"},
+		Accumulate: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var allocs []costapi.CostAllocation
+	for _, set := range resp.Sets {
+		allocs = append(allocs, set.Allocations...)
+	}
+	return &pricing.Quote{
+		Backend:  costapi.CostBackendOpenCost,
+		Currency: resp.Currency,
+		Rates:    ratesOf(allocs),
+	}, nil
+}
+
+// ratesOf returns the average rates paid for the resources of the allocations.
+func ratesOf(allocs []costapi.CostAllocation) pricing.Rates {
+	var cpu, ram, gpu, pv costapi.ResourceCost
+	for _, a := range allocs {
+		if a.Name == idleAllocation {
+			continue
+		}
+		addResourceCost(&cpu, a.CPU)
+		addResourceCost(&ram, a.RAM)
+		addResourceCost(&gpu, a.GPU)
+		addResourceCost(&pv, a.PersistentVolume)
+	}
+	return pricing.Rates{
+		CPUCoreHour:     rate(cpu),
+		RAMGiBHour:      rate(ram) * pricing.BytesPerGiB,
+		GPUHour:         rate(gpu),
+		StorageGiBMonth: rate(pv) * pricing.BytesPerGiB * pricing.HoursPerMonth,
+	}
+}
+
+func rate(rc costapi.ResourceCost) float64 {
+	if rc.AmountHours <= 0 {
+		return 0
+	}
+	return rc.Cost / rc.AmountHours
+}
+
+// quote returns the default rates of the pricing ConfigMap.
+func (e *localEstimator) quote(ctx context.Context) (*pricing.Quote, error) {
+	p, err := e.loadPricing(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &pricing.Quote{
+		Backend:  costapi.CostBackendLocal,
+		Currency: p.Currency,
+		Rates:    p.Default,
+	}, nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reports
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"testing"
+
+	costapi "kubeops.dev/ui-server/apis/cost/v1alpha1"
+	"kubeops.dev/ui-server/pkg/registry/cost/pricing"
+)
+
+func TestRatesOf(t *testing.T) {
+	rates := ratesOf([]costapi.CostAllocation{
+		{
+			Name: "cluster-one",
+			CPU:  costapi.ResourceCost{AmountHours: 100, Cost: 4},
+			RAM:  costapi.ResourceCost{AmountHours: 200 * pricing.BytesPerGiB, Cost: 1},
+			PersistentVolume: costapi.ResourceCost{
+				AmountHours: 730 * 50 * pricing.BytesPerGiB,
+				Cost:        5,
+			},
+		},
+		{
+			Name: idleAllocation,
+			CPU:  costapi.ResourceCost{Cost: 10},
+		},
+	})
+
+	approx := func(x, y float64) bool { return math.Abs(x-y) < 1e-9 }
+	if !approx(rates.CPUCoreHour, 0.04) ||
+		!approx(rates.RAMGiBHour, 0.005) ||
+		!approx(rates.StorageGiBMonth, 0.1) ||
+		rates.GPUHour != 0 {
+		t.Errorf("unexpected rates %+v", rates)
+	}
+}
+
+func TestQuoteCachesFailures(t *testing.T) {
+	var calls int
+	s := newTestStorage(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"code":500,"message":"prometheus is down"}`))
+	})
+
+	for range 2 {
+		if _, err := s.Quote(context.Background()); err == nil {
+			t.Error("expected error")
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected the failure to be cached, OpenCost was queried %d times", calls)
+	}
+}
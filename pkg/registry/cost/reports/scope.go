@@ -0,0 +1,300 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reports
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	costapi "kubeops.dev/ui-server/apis/cost/v1alpha1"
+	"kubeops.dev/ui-server/pkg/graph"
+	"kubeops.dev/ui-server/pkg/shared"
+
+	"gomodules.xyz/sets"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	kmapi "kmodules.xyz/client-go/api/v1"
+	rsapi "kmodules.xyz/resource-metadata/apis/meta/v1alpha1"
+	sharedapi "kmodules.xyz/resource-metadata/apis/shared"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	aggregateNamespace = "namespace"
+	aggregatePod       = "pod"
+
+	maxOwnerDepth = 5
+)
+
+// scope selects the allocations that belong to a namespace or to the resource graph of an object.
+type scope struct {
+	isCluster bool
+	namespace string
+	// components maps the pods found in the resource graph to their top level controller.
+	components map[types.NamespacedName]kmapi.ObjectID
+	// oldestPod is when the oldest of the pods was created.
+	oldestPod time.Time
+}
+
+func newScope(ctx context.Context, kc client.Client, oi *kmapi.ObjectInfo) (*scope, error) {
+	if shared.IsClusterRequest(oi) {
+		return &scope{isCluster: true}, nil
+	} else if shared.IsNamespaceRequest(oi) {
+		return &scope{namespace: oi.Ref.Name}, nil
+	}
+
+	src, err := sourceID(kc, oi)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := locatePods(ctx, kc, src)
+	if err != nil {
+		return nil, err
+	}
+
+	scp := scope{
+		components: make(map[types.NamespacedName]kmapi.ObjectID, len(pods)),
+	}
+	for _, pod := range pods {
+		c, err := topLevelController(ctx, kc, src, pod)
+		if err != nil {
+			return nil, err
+		}
+		scp.components[pod] = c
+
+		var obj metav1.PartialObjectMetadata
+		obj.SetGroupVersionKind(core.SchemeGroupVersion.WithKind("Pod"))
+		if err := kc.Get(ctx, client.ObjectKey(pod), &obj); client.IgnoreNotFound(err) != nil {
+			return nil, err
+		} else if err == nil && (scp.oldestPod.IsZero() || obj.CreationTimestamp.Before(&metav1.Time{Time: scp.oldestPod})) {
+			scp.oldestPod = obj.CreationTimestamp.Time
+		}
+	}
+	return &scp, nil
+}
+
+// deletedPodsCondition returns a condition if the scope is an object and the window
+// starts before its oldest pod. Only the pods that exist now are found in the resource
+// graph, so the cost of the pods deleted before is not included.
+func (scp *scope) deletedPodsCondition(w costapi.CostWindow) *kmapi.Condition {
+	if scp.components == nil || w.Start == nil {
+		return nil
+	}
+	msg := "No pods of the object were found, the cost of its deleted pods is not included."
+	if !scp.oldestPod.IsZero() {
+		if !w.Start.Time.Before(scp.oldestPod) {
+			return nil
+		}
+		msg = fmt.Sprintf("The cost of the pods of the object deleted before %s is not included.", scp.oldestPod.UTC().Format(time.RFC3339))
+	}
+	return &kmapi.Condition{
+		Type:     costapi.ConditionDeletedPodsExcluded,
+		Status:   metav1.ConditionTrue,
+		Severity: kmapi.ConditionSeverityInfo,
+		Reason:   costapi.ReasonPodsNotFound,
+		Message:  msg,
+	}
+}
+
+// aggregate returns the OpenCost aggregation needed to filter allocations by the scope.
+func (scp *scope) aggregate(in []string) []string {
+	if scp.isCluster {
+		return in
+	} else if scp.components != nil {
+		return []string{aggregateNamespace, aggregatePod}
+	}
+	if !sets.NewString(in...).Has(aggregateNamespace) {
+		return append([]string{aggregateNamespace}, in...)
+	}
+	return in
+}
+
+// apply removes allocations that are out of scope and breaks down the cost of an object to its components.
+func (scp *scope) apply(resp *costapi.CostReportResponse) {
+	if scp.isCluster {
+		return
+	}
+
+	perComponent := map[kmapi.OID][]costapi.CostAllocation{}
+	for i, cs := range resp.Sets {
+		allocations := cs.Allocations[:0]
+		for _, a := range cs.Allocations {
+			if a.Properties == nil {
+				continue
+			}
+			if scp.components == nil {
+				if a.Properties.Namespace == scp.namespace {
+					allocations = append(allocations, a)
+				}
+				continue
+			}
+			c, ok := scp.components[types.NamespacedName{Namespace: a.Properties.Namespace, Name: a.Properties.Pod}]
+			if !ok {
+				continue
+			}
+			allocations = append(allocations, a)
+			perComponent[c.OID()] = append(perComponent[c.OID()], a)
+		}
+		resp.Sets[i].Allocations = allocations
+	}
+	summarize(resp)
+
+	if scp.components == nil {
+		return
+	}
+	resp.Components = make([]costapi.ComponentCost, 0, len(perComponent))
+	for oid, allocations := range perComponent {
+		id, _ := kmapi.ParseObjectID(oid)
+		pods := sets.NewString()
+		for _, a := range allocations {
+			pods.Insert(a.Properties.Pod)
+		}
+		resp.Components = append(resp.Components, costapi.ComponentCost{
+			Component: *id,
+			Pods:      pods.List(),
+			Totals:    sumAllocations(allocations),
+		})
+	}
+	sort.Slice(resp.Components, func(i, j int) bool {
+		return resp.Components[i].Component.OID() < resp.Components[j].Component.OID()
+	})
+}
+
+func sourceID(kc client.Client, oi *kmapi.ObjectInfo) (*kmapi.ObjectID, error) {
+	rid := oi.Resource
+	if rid.Group == "core" {
+		rid.Group = ""
+	}
+	if rid.Kind == "" {
+		r2, err := kmapi.ExtractResourceID(kc.RESTMapper(), oi.Resource)
+		if err != nil {
+			return nil, err
+		}
+		rid = *r2
+	}
+	return &kmapi.ObjectID{
+		Group:     rid.Group,
+		Kind:      rid.Kind,
+		Namespace: oi.Ref.Namespace,
+		Name:      oi.Ref.Name,
+	}, nil
+}
+
+// locatePods finds the pods of an object and of the objects connected to it as storage or backup,
+// eg, the pods of a database and the pods of its backup jobs.
+func locatePods(ctx context.Context, kc client.Client, src *kmapi.ObjectID) ([]types.NamespacedName, error) {
+	rg, err := graph.ResourceGraph(kc.RESTMapper(), *src, []kmapi.EdgeLabel{
+		kmapi.EdgeLabelStorage,
+		kmapi.EdgeLabelBackupVia,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	objects := map[kmapi.OID]kmapi.ObjectID{
+		src.OID(): *src,
+	}
+	for _, conn := range rg.Connections {
+		for _, p := range []rsapi.ObjectPointer{conn.Source, conn.Target} {
+			rid := rg.Resources[p.ResourceID]
+			id := kmapi.ObjectID{
+				Group:     rid.Group,
+				Kind:      rid.Kind,
+				Namespace: p.Namespace,
+				Name:      p.Name,
+			}
+			objects[id.OID()] = id
+		}
+	}
+
+	podSet := map[types.NamespacedName]struct{}{}
+	for _, id := range objects {
+		if id.Group == "" && id.Kind == "Pod" {
+			podSet[types.NamespacedName{Namespace: id.Namespace, Name: id.Name}] = struct{}{}
+			continue
+		}
+		_, refs, err := graph.ExecRawQuery(ctx, kc, id.OID(), sharedapi.ResourceLocator{
+			Ref: metav1.GroupKind{
+				Group: "",
+				Kind:  "Pod",
+			},
+			Query: sharedapi.ResourceQuery{
+				Type:    sharedapi.GraphQLQuery,
+				ByLabel: kmapi.EdgeLabelOffshoot,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, ref := range refs {
+			podSet[types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}] = struct{}{}
+		}
+	}
+
+	pods := make([]types.NamespacedName, 0, len(podSet))
+	for pod := range podSet {
+		pods = append(pods, pod)
+	}
+	return pods, nil
+}
+
+// topLevelController follows the controller references of a pod, eg, Pod -> ReplicaSet -> Deployment.
+// It stops below the source object, so that the pods of a database are reported under its StatefulSet.
+func topLevelController(ctx context.Context, kc client.Client, src *kmapi.ObjectID, pod types.NamespacedName) (kmapi.ObjectID, error) {
+	cur := kmapi.ObjectID{
+		Kind:      "Pod",
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+	}
+	for range maxOwnerDepth {
+		mapping, err := kc.RESTMapper().RESTMapping(cur.GroupKind())
+		if err != nil {
+			return cur, nil
+		}
+		var obj metav1.PartialObjectMetadata
+		obj.SetGroupVersionKind(mapping.GroupVersionKind)
+		if err := kc.Get(ctx, client.ObjectKey{Namespace: cur.Namespace, Name: cur.Name}, &obj); client.IgnoreNotFound(err) != nil {
+			return cur, err
+		} else if err != nil {
+			return cur, nil
+		}
+
+		ref := metav1.GetControllerOf(&obj)
+		if ref == nil {
+			return cur, nil
+		}
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			return cur, nil
+		}
+		owner := kmapi.ObjectID{
+			Group:     gv.Group,
+			Kind:      ref.Kind,
+			Namespace: pod.Namespace,
+			Name:      ref.Name,
+		}
+		if owner.OID() == src.OID() {
+			return cur, nil
+		}
+		cur = owner
+	}
+	return cur, nil
+}
@@ -18,19 +18,20 @@ package reports
 
 import (
 	"context"
-	"fmt"
-	"io"
-	"net/http"
 	"net/url"
 	"strings"
+	"sync"
+	"time"
 
 	costapi "kubeops.dev/ui-server/apis/cost/v1alpha1"
+	"kubeops.dev/ui-server/pkg/opencost"
+	projectstorage "kubeops.dev/ui-server/pkg/registry/core/project"
+	"kubeops.dev/ui-server/pkg/registry/cost/pricing"
 
 	gs "github.com/gorilla/schema"
 	"github.com/pkg/errors"
-	"gomodules.xyz/sync"
-	core "k8s.io/api/core/v1"
-	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"golang.org/x/sync/singleflight"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -38,13 +39,29 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-var (
-	once  sync.Once
-	ocURL *url.URL
+// Backend selects how cost is computed. By default, OpenCost is used if it is
+// running in the cluster and the local estimator otherwise.
+type Backend string
+
+const (
+	BackendAuto     Backend = "auto"
+	BackendOpenCost Backend = "opencost"
+	BackendLocal    Backend = "local"
 )
 
 type Storage struct {
-	kc client.Client
+	kc       client.Client
+	oc       *opencost.Client
+	health   *opencost.HealthChecker
+	projects projectstorage.Source
+	local    *localEstimator
+	backend  Backend
+
+	quotes   singleflight.Group
+	quoteMu  sync.Mutex
+	quote    *pricing.Quote
+	quoteErr error
+	quotedAt time.Time
 }
 
 var (
@@ -55,9 +72,17 @@ var (
 	_ rest.SingularNameProvider     = &Storage{}
 )
 
-func NewStorage(kc client.Client) *Storage {
+func NewStorage(kc client.Client, oc *opencost.Client, health *opencost.HealthChecker, projects projectstorage.Source, backend Backend, pricingKey client.ObjectKey) *Storage {
 	return &Storage{
-		kc: kc,
+		kc:       kc,
+		oc:       oc,
+		health:   health,
+		projects: projects,
+		local: &localEstimator{
+			kc:         kc,
+			pricingKey: pricingKey,
+		},
+		backend: backend,
 	}
 }
 
@@ -82,56 +107,85 @@ func (r *Storage) Destroy() {}
 func (r *Storage) Create(ctx context.Context, obj runtime.Object, _ rest.ValidateObjectFunc, _ *metav1.CreateOptions) (runtime.Object, error) {
 	in := obj.(*costapi.CostReport)
 
-	once.Do(func() error {
-		var svcs core.ServiceList
-		err := r.kc.List(ctx, &svcs, client.MatchingLabels{
-			"app.kubernetes.io/name": "opencost",
-		})
-		if err != nil {
-			return err
-		} else if len(svcs.Items) == 0 {
-			return errors.New("opencost service not found")
-		}
+	req := in.Request
+	if req == nil {
+		req = &costapi.CostReportRequest{}
+	}
+	resp, err := r.Report(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	in.Response = resp
+	return in, nil
+}
 
-		ocSvc := svcs.Items[0]
-		var ocApiPort int32
-		for _, p := range ocSvc.Spec.Ports {
-			if p.Name == "http" {
-				ocApiPort = p.Port
-				break
-			}
+// Report computes the cost report of a request with the configured backend.
+func (r *Storage) Report(ctx context.Context, req *costapi.CostReportRequest) (*costapi.CostReportResponse, error) {
+	scp, err := newScope(ctx, r.kc, &req.ObjectInfo)
+	if err != nil {
+		return nil, err
+	}
+	// the query is built on a copy, so that the request is echoed back as sent
+	q := *req
+	var projectOf map[string]string
+	if req.ProjectCost {
+		if !scp.isCluster {
+			return nil, apierrors.NewBadRequest("projectCost can't be combined with an object or namespace")
 		}
-		if ocApiPort <= 0 {
-			return errors.New("missing http port in opencost service spec")
+		projectOf, err = r.projects.NamespaceProjects(ctx)
+		if err != nil {
+			return nil, err
+		}
+		q.Aggregate = []string{aggregateNamespace}
+		q.IncludeIdle = true
+		q.IdleByNode = false
+	}
+	q.Aggregate = scp.aggregate(q.Aggregate)
+
+	var resp *costapi.CostReportResponse
+	switch r.backend {
+	case BackendLocal:
+		resp, err = r.local.estimate(ctx, &q, scp, time.Now())
+	case BackendOpenCost:
+		resp, err = r.queryOpenCost(ctx, &q)
+	default:
+		resp, err = r.queryOpenCost(ctx, &q)
+		if errors.Is(err, opencost.ErrNotFound) {
+			resp, err = r.local.estimate(ctx, &q, scp, time.Now())
 		}
+	}
+	if _, ok := err.(apierrors.APIStatus); err != nil && !ok {
+		return nil, apierrors.NewServiceUnavailable(err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+
+	scp.apply(resp)
+	if cond := scp.deletedPodsCondition(resp.Window); cond != nil {
+		resp.Conditions = append(resp.Conditions, *cond)
+	}
+	if req.ProjectCost {
+		resp.Projects = projectCosts(resp, projectOf)
+	}
+	if cond := r.health.Condition(); cond != nil && resp.Backend == costapi.CostBackendOpenCost {
+		resp.Conditions = append(resp.Conditions, *cond)
+	}
+	return resp, nil
+}
 
-		ocURL, err = url.Parse(fmt.Sprintf("http://%s.%s.svc:%d/model/allocation/compute", ocSvc.Name, ocSvc.Namespace, ocApiPort))
-		return err
-	})
+func (r *Storage) queryOpenCost(ctx context.Context, req *costapi.CostReportRequest) (*costapi.CostReportResponse, error) {
+	req.AggregateList = strings.Join(req.Aggregate, ",")
 
 	encoder := gs.NewEncoder()
 	form := url.Values{}
-	err := encoder.Encode(in.Request, form)
+	err := encoder.Encode(req, form)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to encode cost report request")
+		return nil, apierrors.NewBadRequest(errors.Wrap(err, "failed to encode cost report request").Error())
 	}
 
-	u := *ocURL
-	u.RawQuery = form.Encode()
-
-	req, err := http.NewRequest("GET", u.String(), nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to create opencost service request")
-	}
-	resp, err := http.DefaultClient.Do(req)
+	code, body, err := r.oc.Get(ctx, opencost.PathAllocationCompute, form)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to call opencost service")
+		return nil, err
 	}
-	defer resp.Body.Close() // nolint:errcheck
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to read opencost api response")
-	}
-	in.Response = &apiextensionsv1.JSON{Raw: body}
-	return in, nil
+	return decodeAllocations(code, body)
 }
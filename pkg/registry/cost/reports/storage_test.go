@@ -0,0 +1,228 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reports
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	costapi "kubeops.dev/ui-server/apis/cost/v1alpha1"
+	"kubeops.dev/ui-server/pkg/opencost"
+	projectstorage "kubeops.dev/ui-server/pkg/registry/core/project"
+
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	kmapi "kmodules.xyz/client-go/api/v1"
+	mgmtapi "kmodules.xyz/resource-metadata/apis/management/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestStorage(t *testing.T, h http.HandlerFunc) *Storage {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	oc, err := opencost.NewClient(nil, opencost.Config{URL: srv.URL, Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewStorage(nil, oc, nil, nil, BackendOpenCost, client.ObjectKey{})
+}
+
+func TestCreate(t *testing.T) {
+	s := newTestStorage(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != opencost.PathAllocationCompute {
+			http.NotFound(w, r)
+			return
+		}
+		q := r.URL.Query()
+		if q.Get("window") != "1d" || q.Get("aggregate") != "namespace,controller" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":400,"message":"unexpected query"}`))
+			return
+		}
+		_, _ = w.Write([]byte(allocationBody))
+	})
+
+	obj, err := s.Create(context.Background(), &costapi.CostReport{
+		Request: &costapi.CostReportRequest{
+			Window:    "1d",
+			Aggregate: []string{"namespace", "controller"},
+		},
+	}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := obj.(*costapi.CostReport).Response
+	if resp == nil || len(resp.Sets) != 1 || len(resp.Sets[0].Allocations) != 2 {
+		t.Errorf("unexpected response %+v", resp)
+	}
+}
+
+func TestCreateProjectCost(t *testing.T) {
+	s := newTestStorage(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("aggregate") != aggregateNamespace || q.Get("includeIdle") != "true" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":400,"message":"unexpected query"}`))
+			return
+		}
+		_, _ = w.Write([]byte(allocationBody))
+	})
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = mgmtapi.AddToScheme(scheme)
+	s.kc = fake.NewClientBuilder().WithScheme(scheme).WithObjects(&core.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Labels: map[string]string{projectstorage.DefaultLabelKey: "team"}},
+	}).Build()
+	s.projects = projectstorage.NewSource(s.kc, nil, projectstorage.Options{Source: projectstorage.SourceLabel})
+
+	req := &costapi.CostReportRequest{
+		Window:      "1d",
+		Aggregate:   []string{"controller"},
+		ProjectCost: true,
+	}
+	obj, err := s.Create(context.Background(), &costapi.CostReport{Request: req}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(req.Aggregate) != 1 || req.Aggregate[0] != "controller" || req.IncludeIdle {
+		t.Errorf("request was modified: %+v", req)
+	}
+	projects := obj.(*costapi.CostReport).Response.Projects
+	if len(projects) != 1 || projects[0].Project != "team" {
+		t.Errorf("expected the demo namespace to be reported under its project, found %+v", projects)
+	}
+}
+
+func TestCreateUpstreamError(t *testing.T) {
+	s := newTestStorage(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"code":400,"message":"Error parsing window"}`))
+	})
+
+	_, err := s.Create(context.Background(), &costapi.CostReport{
+		Request: &costapi.CostReportRequest{Window: "yesterday"},
+	}, nil, nil)
+	if !apierrors.IsBadRequest(err) {
+		t.Errorf("expected bad request, got %v", err)
+	}
+}
+
+func TestCreateUnreachable(t *testing.T) {
+	oc, err := opencost.NewClient(nil, opencost.Config{URL: "http://127.0.0.1:1", Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = NewStorage(nil, oc, nil, nil, BackendOpenCost, client.ObjectKey{}).Create(context.Background(), &costapi.CostReport{}, nil, nil)
+	if !apierrors.IsServiceUnavailable(err) {
+		t.Errorf("expected service unavailable, got %v", err)
+	}
+}
+
+func TestScopeApply(t *testing.T) {
+	const body = `{
+  "code": 200,
+  "data": [
+    {
+      "demo/pg-0": {"properties": {"namespace": "demo", "pod": "pg-0"}, "cpuCost": 1, "totalCost": 1},
+      "demo/pg-1": {"properties": {"namespace": "demo", "pod": "pg-1"}, "cpuCost": 1, "totalCost": 1},
+      "demo/pg-backup-1": {"properties": {"namespace": "demo", "pod": "pg-backup-1"}, "cpuCost": 0.5, "totalCost": 0.5},
+      "demo/web-0": {"properties": {"namespace": "demo", "pod": "web-0"}, "cpuCost": 4, "totalCost": 4},
+      "__idle__": {"cpuCost": 10, "totalCost": 10}
+    }
+  ]
+}`
+	sts := kmapi.ObjectID{Group: "apps", Kind: "StatefulSet", Namespace: "demo", Name: "pg"}
+	job := kmapi.ObjectID{Group: "batch", Kind: "CronJob", Namespace: "demo", Name: "pg-backup"}
+	scp := &scope{
+		components: map[types.NamespacedName]kmapi.ObjectID{
+			{Namespace: "demo", Name: "pg-0"}:        sts,
+			{Namespace: "demo", Name: "pg-1"}:        sts,
+			{Namespace: "demo", Name: "pg-backup-1"}: job,
+		},
+	}
+	if got := scp.aggregate([]string{"node"}); len(got) != 2 || got[0] != aggregateNamespace || got[1] != aggregatePod {
+		t.Errorf("unexpected aggregate %v", got)
+	}
+
+	resp, err := decodeAllocations(http.StatusOK, []byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scp.apply(resp)
+	if got := resp.Totals.TotalCost; got != 2.5 {
+		t.Errorf("expected total cost 2.5, got %v", got)
+	}
+	if len(resp.Components) != 2 {
+		t.Fatalf("unexpected components %+v", resp.Components)
+	}
+	// sorted by object id, so apps/StatefulSet comes before batch/CronJob
+	if c := resp.Components[0]; c.Component != sts || len(c.Pods) != 2 || c.Totals.TotalCost != 2 {
+		t.Errorf("unexpected component %+v", c)
+	}
+	if c := resp.Components[1]; c.Component != job || c.Totals.CPUCost != 0.5 {
+		t.Errorf("unexpected component %+v", c)
+	}
+
+	resp, err = decodeAllocations(http.StatusOK, []byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	(&scope{namespace: "demo"}).apply(resp)
+	if got := resp.Totals.TotalCost; got != 6.5 || resp.Components != nil {
+		t.Errorf("unexpected namespace report %+v", resp)
+	}
+}
+
+func TestDeletedPodsCondition(t *testing.T) {
+	created := time.Date(2026, 10, 10, 0, 0, 0, 0, time.UTC)
+	object := map[types.NamespacedName]kmapi.ObjectID{{Namespace: "demo", Name: "pg-0"}: {}}
+	window := func(start time.Time) costapi.CostWindow {
+		return costapi.CostWindow{Start: &metav1.Time{Time: start}}
+	}
+	cases := []struct {
+		name   string
+		scp    *scope
+		window costapi.CostWindow
+		want   bool
+	}{
+		{"namespace", &scope{namespace: "demo"}, window(created.Add(-time.Hour)), false},
+		{"after oldest pod", &scope{components: object, oldestPod: created}, window(created.Add(time.Hour)), false},
+		{"before oldest pod", &scope{components: object, oldestPod: created}, window(created.Add(-time.Hour)), true},
+		{"no pods", &scope{components: map[types.NamespacedName]kmapi.ObjectID{}}, window(created), true},
+		{"no window", &scope{components: object, oldestPod: created}, costapi.CostWindow{}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cond := c.scp.deletedPodsCondition(c.window)
+			if (cond != nil) != c.want {
+				t.Fatalf("expected condition %v, got %+v", c.want, cond)
+			}
+			if cond != nil && cond.Type != costapi.ConditionDeletedPodsExcluded {
+				t.Errorf("unexpected condition %+v", cond)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reports
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationWindow = regexp.MustCompile(`^(\d+)([mhdw])$`)
+
+// parseWindow parses the window formats supported by OpenCost, eg,
+// "24h", "7d", "today", "lastweek" or "2024-01-01T00:00:00Z,2024-01-02T00:00:00Z".
+// ref: https://www.opencost.io/docs/integrations/api#allocation-api
+func parseWindow(window string, now time.Time) (time.Time, time.Time, error) {
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch window {
+	case "today":
+		return today, now, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), today, nil
+	case "week":
+		start := today.AddDate(0, 0, -int(today.Weekday()))
+		return start, now, nil
+	case "lastweek":
+		end := today.AddDate(0, 0, -int(today.Weekday()))
+		return end.AddDate(0, 0, -7), end, nil
+	case "month":
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, now, nil
+	case "lastmonth":
+		end := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return end.AddDate(0, -1, 0), end, nil
+	}
+
+	if m := durationWindow.FindStringSubmatch(window); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		var d time.Duration
+		switch m[2] {
+		case "m":
+			d = time.Duration(n) * time.Minute
+		case "h":
+			d = time.Duration(n) * time.Hour
+		case "d":
+			d = time.Duration(n) * 24 * time.Hour
+		case "w":
+			d = time.Duration(n) * 7 * 24 * time.Hour
+		}
+		return now.Add(-d), now, nil
+	}
+
+	if start, end, found := strings.Cut(window, ","); found {
+		s, err := time.Parse(time.RFC3339, strings.TrimSpace(start))
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid window %q: %w", window, err)
+		}
+		e, err := time.Parse(time.RFC3339, strings.TrimSpace(end))
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid window %q: %w", window, err)
+		}
+		if !s.Before(e) {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid window %q: start must be before end", window)
+		}
+		return s.UTC(), e.UTC(), nil
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("invalid window %q", window)
+}
@@ -0,0 +1,319 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceaccessreview
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	identityapi "kubeops.dev/ui-server/apis/identity/v1alpha1"
+
+	rbac "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	rbacauthorizer "kmodules.xyz/authorizer/rbac"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var defaultVerbs = []string{"get", "update", "delete"}
+
+type Storage struct {
+	kc client.Client
+	a  authorizer.Authorizer
+}
+
+var (
+	_ rest.GroupVersionKindProvider = &Storage{}
+	_ rest.Scoper                   = &Storage{}
+	_ rest.Storage                  = &Storage{}
+	_ rest.Creater                  = &Storage{}
+	_ rest.SingularNameProvider     = &Storage{}
+)
+
+func NewStorage(kc client.Client, a authorizer.Authorizer) *Storage {
+	return &Storage{
+		kc: kc,
+		a:  a,
+	}
+}
+
+func (r *Storage) GroupVersionKind(_ schema.GroupVersion) schema.GroupVersionKind {
+	return identityapi.SchemeGroupVersion.WithKind(identityapi.ResourceKindResourceAccessReview)
+}
+
+func (r *Storage) NamespaceScoped() bool {
+	return false
+}
+
+func (r *Storage) GetSingularName() string {
+	return strings.ToLower(identityapi.ResourceKindResourceAccessReview)
+}
+
+func (r *Storage) New() runtime.Object {
+	return &identityapi.ResourceAccessReview{}
+}
+
+func (r *Storage) Destroy() {}
+
+func (r *Storage) Create(ctx context.Context, obj runtime.Object, _ rest.ValidateObjectFunc, _ *metav1.CreateOptions) (runtime.Object, error) {
+	in := obj.(*identityapi.ResourceAccessReview)
+	if in.Name != "" {
+		return in, apierrors.NewBadRequest("metadata.name must be empty")
+	}
+	if in.Spec.Resource == "" {
+		return nil, apierrors.NewBadRequest("spec.resource must be set")
+	}
+
+	user, ok := request.UserFrom(ctx)
+	if !ok {
+		return nil, apierrors.NewBadRequest("missing user info")
+	}
+	// the review reveals the bindings, so the user must be able to read them
+	if err := r.canListBindings(ctx, user, in.Spec.Namespace); err != nil {
+		return nil, err
+	}
+
+	verbs := in.Spec.Verbs
+	if len(verbs) == 0 {
+		verbs = defaultVerbs
+	}
+
+	e, err := r.newEvaluator(ctx, in.Spec.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	in.Status = identityapi.ResourceAccessReviewStatus{
+		Verbs: make([]identityapi.ResourceAccessVerb, 0, len(verbs)),
+	}
+	for _, verb := range verbs {
+		in.Status.Verbs = append(in.Status.Verbs, identityapi.ResourceAccessVerb{
+			Verb: verb,
+			Subjects: e.allowedSubjects(authorizer.AttributesRecord{
+				Verb:            verb,
+				Namespace:       in.Spec.Namespace,
+				APIGroup:        in.Spec.Group,
+				APIVersion:      "*",
+				Resource:        in.Spec.Resource,
+				Subresource:     in.Spec.Subresource,
+				Name:            in.Spec.Name,
+				ResourceRequest: true,
+			}),
+		})
+	}
+	if err := utilerrors.NewAggregate(e.errs); err != nil {
+		in.Status.EvaluationError = err.Error()
+	}
+	return in, nil
+}
+
+func (r *Storage) canListBindings(ctx context.Context, u user.Info, ns string) error {
+	check := func(resource, ns string) error {
+		attrs := authorizer.AttributesRecord{
+			User:            u,
+			Verb:            "list",
+			Namespace:       ns,
+			APIGroup:        rbac.GroupName,
+			Resource:        resource,
+			ResourceRequest: true,
+		}
+		decision, why, err := r.a.Authorize(ctx, attrs)
+		if err != nil {
+			return apierrors.NewInternalError(err)
+		}
+		if decision != authorizer.DecisionAllow {
+			return apierrors.NewForbidden(rbac.Resource(resource), "", errors.New(why))
+		}
+		return nil
+	}
+
+	if err := check("clusterrolebindings", ""); err != nil {
+		return err
+	}
+	if ns != "" {
+		return check("rolebindings", ns)
+	}
+	return nil
+}
+
+// evaluator finds the subjects allowed by the bindings that apply to a namespace.
+type evaluator struct {
+	clusterRoles        map[string]*rbac.ClusterRole
+	roles               map[string]*rbac.Role
+	clusterRoleBindings []rbac.ClusterRoleBinding
+	roleBindings        []rbac.RoleBinding
+
+	aggregated map[string][]rbac.PolicyRule
+	errs       []error
+}
+
+func (r *Storage) newEvaluator(ctx context.Context, ns string) (*evaluator, error) {
+	e := &evaluator{
+		clusterRoles: map[string]*rbac.ClusterRole{},
+		roles:        map[string]*rbac.Role{},
+		aggregated:   map[string][]rbac.PolicyRule{},
+	}
+
+	var clusterRoles rbac.ClusterRoleList
+	if err := r.kc.List(ctx, &clusterRoles); err != nil {
+		return nil, err
+	}
+	for i := range clusterRoles.Items {
+		e.clusterRoles[clusterRoles.Items[i].Name] = &clusterRoles.Items[i]
+	}
+	var clusterRoleBindings rbac.ClusterRoleBindingList
+	if err := r.kc.List(ctx, &clusterRoleBindings); err != nil {
+		return nil, err
+	}
+	e.clusterRoleBindings = clusterRoleBindings.Items
+
+	if ns != "" {
+		var roles rbac.RoleList
+		if err := r.kc.List(ctx, &roles, client.InNamespace(ns)); err != nil {
+			return nil, err
+		}
+		for i := range roles.Items {
+			e.roles[roles.Items[i].Name] = &roles.Items[i]
+		}
+		var roleBindings rbac.RoleBindingList
+		if err := r.kc.List(ctx, &roleBindings, client.InNamespace(ns)); err != nil {
+			return nil, err
+		}
+		e.roleBindings = roleBindings.Items
+	}
+	return e, nil
+}
+
+func (e *evaluator) allowedSubjects(attrs authorizer.Attributes) []identityapi.AccessSubject {
+	subjects := map[rbac.Subject]*identityapi.AccessSubject{}
+	add := func(s rbac.Subject, grant *identityapi.AccessGrant) {
+		if s.Kind != rbac.ServiceAccountKind && s.APIGroup == "" {
+			s.APIGroup = rbac.GroupName
+		}
+		as, found := subjects[s]
+		if !found {
+			as = &identityapi.AccessSubject{Subject: s}
+			subjects[s] = as
+		}
+		if grant != nil {
+			as.Grants = append(as.Grants, *grant)
+		}
+	}
+
+	add(rbac.Subject{Kind: rbac.GroupKind, Name: user.SystemPrivilegedGroup}, nil)
+	for _, crb := range e.clusterRoleBindings {
+		if rbacauthorizer.RulesAllow(attrs, e.rulesFor(crb.RoleRef, "")...) {
+			for _, s := range crb.Subjects {
+				add(s, &identityapi.AccessGrant{
+					Kind:    "ClusterRoleBinding",
+					Name:    crb.Name,
+					RoleRef: crb.RoleRef,
+				})
+			}
+		}
+	}
+	for _, rb := range e.roleBindings {
+		if rbacauthorizer.RulesAllow(attrs, e.rulesFor(rb.RoleRef, rb.Namespace)...) {
+			for _, s := range rb.Subjects {
+				if s.Kind == rbac.ServiceAccountKind && s.Namespace == "" {
+					s.Namespace = rb.Namespace
+				}
+				add(s, &identityapi.AccessGrant{
+					Kind:      "RoleBinding",
+					Namespace: rb.Namespace,
+					Name:      rb.Name,
+					RoleRef:   rb.RoleRef,
+				})
+			}
+		}
+	}
+
+	out := make([]identityapi.AccessSubject, 0, len(subjects))
+	for _, s := range subjects {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Kind != out[j].Kind {
+			return out[i].Kind < out[j].Kind
+		}
+		if out[i].Namespace != out[j].Namespace {
+			return out[i].Namespace < out[j].Namespace
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// rulesFor returns the rules of the role referred by a binding in the namespace.
+func (e *evaluator) rulesFor(ref rbac.RoleRef, ns string) []rbac.PolicyRule {
+	switch ref.Kind {
+	case "Role":
+		role, found := e.roles[ref.Name]
+		if !found || ns == "" {
+			e.errs = append(e.errs, fmt.Errorf("role %s/%s not found", ns, ref.Name))
+			return nil
+		}
+		return role.Rules
+	case "ClusterRole":
+		return e.clusterRoleRules(ref.Name)
+	}
+	e.errs = append(e.errs, fmt.Errorf("unknown role kind %s", ref.Kind))
+	return nil
+}
+
+// clusterRoleRules returns the rules of a ClusterRole, including the rules of the ClusterRoles
+// selected by its aggregation rule, even if they were not aggregated by the controller yet.
+func (e *evaluator) clusterRoleRules(name string) []rbac.PolicyRule {
+	if rules, found := e.aggregated[name]; found {
+		return rules
+	}
+
+	cr, found := e.clusterRoles[name]
+	if !found {
+		e.errs = append(e.errs, fmt.Errorf("clusterrole %s not found", name))
+		e.aggregated[name] = nil
+		return nil
+	}
+
+	rules := append([]rbac.PolicyRule(nil), cr.Rules...)
+	if cr.AggregationRule != nil {
+		for _, ls := range cr.AggregationRule.ClusterRoleSelectors {
+			sel, err := metav1.LabelSelectorAsSelector(&ls)
+			if err != nil {
+				e.errs = append(e.errs, fmt.Errorf("clusterrole %s has invalid aggregation rule: %w", name, err))
+				continue
+			}
+			for _, other := range e.clusterRoles {
+				if other.Name != name && sel.Matches(labels.Set(other.Labels)) {
+					rules = append(rules, other.Rules...)
+				}
+			}
+		}
+	}
+	e.aggregated[name] = rules
+	return rules
+}
@@ -0,0 +1,138 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceaccessreview
+
+import (
+	"context"
+	"testing"
+
+	identityapi "kubeops.dev/ui-server/apis/identity/v1alpha1"
+
+	rbac "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type allowAll bool
+
+func (a allowAll) Authorize(_ context.Context, _ authorizer.Attributes) (authorizer.Decision, string, error) {
+	if a {
+		return authorizer.DecisionAllow, "", nil
+	}
+	return authorizer.DecisionDeny, "not allowed", nil
+}
+
+func TestCreate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	kc := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		// aggregated ClusterRole, whose rules were not filled in by the controller yet
+		&rbac.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "secret-admin"},
+			AggregationRule: &rbac.AggregationRule{
+				ClusterRoleSelectors: []metav1.LabelSelector{{MatchLabels: map[string]string{"aggregate-to-secret-admin": "true"}}},
+			},
+		},
+		&rbac.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "secret-editor", Labels: map[string]string{"aggregate-to-secret-admin": "true"}},
+			Rules:      []rbac.PolicyRule{{Verbs: []string{"get", "update"}, APIGroups: []string{""}, Resources: []string{"secrets"}}},
+		},
+		&rbac.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "secret-admins"},
+			RoleRef:    rbac.RoleRef{APIGroup: rbac.GroupName, Kind: "ClusterRole", Name: "secret-admin"},
+			Subjects:   []rbac.Subject{{Kind: rbac.GroupKind, APIGroup: rbac.GroupName, Name: "ops"}},
+		},
+		&rbac.Role{
+			ObjectMeta: metav1.ObjectMeta{Name: "db-creds", Namespace: "demo"},
+			Rules:      []rbac.PolicyRule{{Verbs: []string{"get", "delete"}, APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"db-creds"}}},
+		},
+		&rbac.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "demo"},
+			RoleRef:    rbac.RoleRef{APIGroup: rbac.GroupName, Kind: "Role", Name: "db-creds"},
+			Subjects: []rbac.Subject{
+				{Kind: rbac.ServiceAccountKind, Name: "app"},
+				{Kind: rbac.UserKind, APIGroup: rbac.GroupName, Name: "alice"},
+			},
+		},
+		&rbac.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "ops", Namespace: "demo"},
+			RoleRef:    rbac.RoleRef{APIGroup: rbac.GroupName, Kind: "ClusterRole", Name: "secret-editor"},
+			Subjects:   []rbac.Subject{{Kind: rbac.GroupKind, APIGroup: rbac.GroupName, Name: "ops"}},
+		},
+	).Build()
+
+	ctx := apirequest.WithUser(context.TODO(), &user.DefaultInfo{Name: "admin"})
+	review := &identityapi.ResourceAccessReview{
+		Spec: identityapi.ResourceAccessReviewSpec{
+			Resource:  "secrets",
+			Namespace: "demo",
+			Name:      "db-creds",
+		},
+	}
+
+	if _, err := NewStorage(kc, allowAll(false)).Create(ctx, review.DeepCopy(), nil, &metav1.CreateOptions{}); !apierrors.IsForbidden(err) {
+		t.Fatalf("expected forbidden, got %v", err)
+	}
+
+	obj, err := NewStorage(kc, allowAll(true)).Create(ctx, review.DeepCopy(), nil, &metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	status := obj.(*identityapi.ResourceAccessReview).Status
+	if status.EvaluationError != "" {
+		t.Errorf("unexpected evaluation error %s", status.EvaluationError)
+	}
+
+	type grant struct {
+		subject string
+		grants  int
+	}
+	want := map[string][]grant{
+		"get":    {{"Group/ops", 2}, {"Group/system:masters", 0}, {"ServiceAccount/demo/app", 1}, {"User/alice", 1}},
+		"update": {{"Group/ops", 2}, {"Group/system:masters", 0}},
+		"delete": {{"Group/system:masters", 0}, {"ServiceAccount/demo/app", 1}, {"User/alice", 1}},
+	}
+	if len(status.Verbs) != len(want) {
+		t.Fatalf("expected %d verbs, got %+v", len(want), status.Verbs)
+	}
+	for _, v := range status.Verbs {
+		var got []grant
+		for _, s := range v.Subjects {
+			id := s.Kind + "/" + s.Name
+			if s.Namespace != "" {
+				id = s.Kind + "/" + s.Namespace + "/" + s.Name
+			}
+			got = append(got, grant{id, len(s.Grants)})
+		}
+		if len(got) != len(want[v.Verb]) {
+			t.Errorf("verb %s: expected %v, got %v", v.Verb, want[v.Verb], got)
+			continue
+		}
+		for i := range got {
+			if got[i] != want[v.Verb][i] {
+				t.Errorf("verb %s: expected %v, got %v", v.Verb, want[v.Verb], got)
+				break
+			}
+		}
+	}
+}
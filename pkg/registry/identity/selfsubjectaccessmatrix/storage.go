@@ -0,0 +1,161 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selfsubjectaccessmatrix
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	identityapi "kubeops.dev/ui-server/apis/identity/v1alpha1"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	clustermeta "kmodules.xyz/client-go/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maxCells bounds the number of access checks in one matrix.
+const maxCells = 2000
+
+type Storage struct {
+	kc client.Client
+	a  authorizer.Authorizer
+}
+
+var (
+	_ rest.GroupVersionKindProvider = &Storage{}
+	_ rest.Scoper                   = &Storage{}
+	_ rest.Storage                  = &Storage{}
+	_ rest.Creater                  = &Storage{}
+	_ rest.SingularNameProvider     = &Storage{}
+)
+
+func NewStorage(kc client.Client, a authorizer.Authorizer) *Storage {
+	return &Storage{
+		kc: kc,
+		a:  a,
+	}
+}
+
+func (r *Storage) GroupVersionKind(_ schema.GroupVersion) schema.GroupVersionKind {
+	return identityapi.SchemeGroupVersion.WithKind(identityapi.ResourceKindSelfSubjectAccessMatrix)
+}
+
+func (r *Storage) NamespaceScoped() bool {
+	return false
+}
+
+func (r *Storage) GetSingularName() string {
+	return strings.ToLower(identityapi.ResourceKindSelfSubjectAccessMatrix)
+}
+
+func (r *Storage) New() runtime.Object {
+	return &identityapi.SelfSubjectAccessMatrix{}
+}
+
+func (r *Storage) Destroy() {}
+
+func (r *Storage) Create(ctx context.Context, obj runtime.Object, _ rest.ValidateObjectFunc, _ *metav1.CreateOptions) (runtime.Object, error) {
+	in := obj.(*identityapi.SelfSubjectAccessMatrix)
+	if in.Name != "" {
+		return in, apierrors.NewBadRequest("metadata.name must be empty")
+	}
+	if n := len(in.Spec.Resources) * len(in.Spec.Verbs); n > maxCells {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("matrix has %d cells, maximum is %d", n, maxCells))
+	}
+	for i, res := range in.Spec.Resources {
+		if res.Resource == "" {
+			return nil, apierrors.NewBadRequest(fmt.Sprintf("spec.resources[%d].resource must be set", i))
+		}
+	}
+	for i, v := range in.Spec.Verbs {
+		if v.Verb == "" {
+			return nil, apierrors.NewBadRequest(fmt.Sprintf("spec.verbs[%d].verb must be set", i))
+		}
+	}
+
+	user, ok := request.UserFrom(ctx)
+	if !ok {
+		return nil, apierrors.NewBadRequest("missing user info")
+	}
+
+	// client org members may only access their own namespace
+	var orgNamespace string
+	result, err := clustermeta.IsClientOrgMember(r.kc, user)
+	if err != nil {
+		return nil, err
+	}
+	if result.IsClientOrg {
+		orgNamespace = result.Namespace.Name
+	}
+
+	rows := make([]identityapi.AccessMatrixRow, 0, len(in.Spec.Resources))
+	for _, res := range in.Spec.Resources {
+		row := identityapi.AccessMatrixRow{
+			AccessMatrixResource: res,
+			Verbs:                make([]identityapi.AccessMatrixDecision, 0, len(in.Spec.Verbs)),
+		}
+		for _, v := range in.Spec.Verbs {
+			d := identityapi.AccessMatrixDecision{
+				AccessMatrixVerb: v,
+			}
+			if result.IsClientOrg && res.Namespace != orgNamespace {
+				d.Reason = fmt.Sprintf("client organization %s can only access namespace %s", result.OrgID, orgNamespace)
+			} else {
+				d.Allowed, d.Reason, err = r.authorize(ctx, user, res, v)
+				if err != nil {
+					return nil, apierrors.NewInternalError(err)
+				}
+			}
+			row.Verbs = append(row.Verbs, d)
+		}
+		rows = append(rows, row)
+	}
+	in.Status.Resources = rows
+
+	return in, nil
+}
+
+func (r *Storage) authorize(ctx context.Context, u user.Info, res identityapi.AccessMatrixResource, v identityapi.AccessMatrixVerb) (bool, string, error) {
+	version := res.Version
+	if version == "" {
+		version = "*"
+	}
+	attrs := authorizer.AttributesRecord{
+		User:            u,
+		Verb:            v.Verb,
+		Namespace:       res.Namespace,
+		APIGroup:        res.Group,
+		APIVersion:      version,
+		Resource:        res.Resource,
+		Subresource:     v.Subresource,
+		Name:            res.Name,
+		ResourceRequest: true,
+	}
+	decision, why, err := r.a.Authorize(ctx, attrs)
+	if err != nil {
+		return false, "", err
+	}
+	return decision == authorizer.DecisionAllow, why, nil
+}
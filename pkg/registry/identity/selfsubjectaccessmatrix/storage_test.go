@@ -0,0 +1,120 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selfsubjectaccessmatrix
+
+import (
+	"context"
+	"testing"
+
+	identityapi "kubeops.dev/ui-server/apis/identity/v1alpha1"
+
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	kmapi "kmodules.xyz/client-go/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// podReader allows get and list of pods, and create of pods/exec in the demo namespace.
+type podReader struct{}
+
+func (podReader) Authorize(_ context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+	if a.GetResource() != "pods" {
+		return authorizer.DecisionNoOpinion, "", nil
+	}
+	switch {
+	case a.GetSubresource() == "" && (a.GetVerb() == "get" || a.GetVerb() == "list"):
+		return authorizer.DecisionAllow, "pod reader", nil
+	case a.GetSubresource() == "exec" && a.GetVerb() == "create" && a.GetNamespace() == "demo":
+		return authorizer.DecisionAllow, "demo exec", nil
+	}
+	return authorizer.DecisionNoOpinion, "", nil
+}
+
+func TestCreate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	kc := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&core.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "acme",
+			Labels:      map[string]string{kmapi.ClientOrgKey: "true"},
+			Annotations: map[string]string{kmapi.AceOrgIDKey: "42"},
+		},
+	}).Build()
+	r := NewStorage(kc, podReader{})
+
+	spec := identityapi.SelfSubjectAccessMatrixSpec{
+		Resources: []identityapi.AccessMatrixResource{
+			{Resource: "pods", Namespace: "demo", Name: "web-0"},
+			{Resource: "pods", Namespace: "acme"},
+			{Group: "apps", Resource: "deployments", Namespace: "demo"},
+		},
+		Verbs: []identityapi.AccessMatrixVerb{
+			{Verb: "get"},
+			{Verb: "delete"},
+			{Verb: "create", Subresource: "exec"},
+		},
+	}
+	review := func(u user.Info) [][]bool {
+		t.Helper()
+		obj, err := r.Create(apirequest.WithUser(context.TODO(), u), &identityapi.SelfSubjectAccessMatrix{Spec: spec}, nil, &metav1.CreateOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows := obj.(*identityapi.SelfSubjectAccessMatrix).Status.Resources
+		if len(rows) != len(spec.Resources) {
+			t.Fatalf("expected %d rows, got %d", len(spec.Resources), len(rows))
+		}
+		out := make([][]bool, 0, len(rows))
+		for i, row := range rows {
+			if row.AccessMatrixResource != spec.Resources[i] {
+				t.Errorf("row %d is for %+v, expected %+v", i, row.AccessMatrixResource, spec.Resources[i])
+			}
+			var allowed []bool
+			for _, d := range row.Verbs {
+				allowed = append(allowed, d.Allowed)
+			}
+			out = append(out, allowed)
+		}
+		return out
+	}
+	check := func(name string, got, want [][]bool) {
+		t.Helper()
+		for i := range want {
+			for j := range want[i] {
+				if got[i][j] != want[i][j] {
+					t.Errorf("%s: resource %d verb %d allowed=%v, expected %v", name, i, j, got[i][j], want[i][j])
+				}
+			}
+		}
+	}
+
+	check("user", review(&user.DefaultInfo{Name: "alice"}), [][]bool{
+		{true, false, true},
+		{true, false, false},
+		{false, false, false},
+	})
+	check("client org member", review(&user.DefaultInfo{Name: "bob", Extra: map[string][]string{kmapi.AceOrgIDKey: {"42"}}}), [][]bool{
+		{false, false, false},
+		{true, false, false},
+		{false, false, false},
+	})
+}
@@ -0,0 +1,190 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selfsubjectnamespaceaccessreview
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	authorization "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+)
+
+// maxCachedDecisions bounds the number of access decisions kept in memory.
+const maxCachedDecisions = 100_000
+
+// Options configures how the access checks of a review are evaluated.
+type Options struct {
+	// Workers is the number of namespaces checked in parallel.
+	Workers int
+	// CacheTTL is how long an access decision is reused for the same user, groups and attributes.
+	// Decisions are not cached if zero.
+	CacheTTL time.Duration
+	// InProcess evaluates the access checks with the in-process RBAC authorizer,
+	// instead of creating a SubjectAccessReview for every check.
+	InProcess bool
+}
+
+// allowed checks whether the user can access either the resource or the non-resource attributes.
+func (r *Storage) allowed(ctx context.Context, u user.Info, attr *authorization.ResourceAttributes, nonResAttr *authorization.NonResourceAttributes) (bool, error) {
+	var key string
+	if r.decisions != nil {
+		key = decisionKey(u, attr, nonResAttr)
+		if v, ok := r.decisions.Get(key); ok {
+			return v.(bool), nil
+		}
+	}
+
+	var allowed bool
+	var err error
+	if r.opts.InProcess {
+		allowed, err = r.authorize(ctx, u, attr, nonResAttr)
+	} else {
+		allowed, err = r.review(ctx, u, attr, nonResAttr)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if r.decisions != nil {
+		r.decisions.Add(key, allowed, r.opts.CacheTTL)
+	}
+	return allowed, nil
+}
+
+// review asks the kube-apiserver using a (Local)SubjectAccessReview.
+func (r *Storage) review(ctx context.Context, u user.Info, attr *authorization.ResourceAttributes, nonResAttr *authorization.NonResourceAttributes) (bool, error) {
+	extra := make(map[string]authorization.ExtraValue)
+	for k, v := range u.GetExtra() {
+		extra[k] = v
+	}
+	spec := authorization.SubjectAccessReviewSpec{
+		ResourceAttributes:    attr,
+		NonResourceAttributes: nonResAttr,
+		User:                  u.GetName(),
+		Groups:                u.GetGroups(),
+		Extra:                 extra,
+		UID:                   u.GetUID(),
+	}
+
+	if attr != nil && attr.Namespace != "" {
+		review := &authorization.LocalSubjectAccessReview{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: attr.Namespace,
+			},
+			Spec: spec,
+		}
+		review, err := r.kc.AuthorizationV1().LocalSubjectAccessReviews(attr.Namespace).Create(ctx, review, metav1.CreateOptions{})
+		if err != nil {
+			return false, err
+		}
+		return review.Status.Allowed, nil
+	}
+
+	review := &authorization.SubjectAccessReview{
+		Spec: spec,
+	}
+	review, err := r.kc.AuthorizationV1().SubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
+
+// authorize evaluates the check with the in-process authorizer.
+func (r *Storage) authorize(ctx context.Context, u user.Info, attr *authorization.ResourceAttributes, nonResAttr *authorization.NonResourceAttributes) (bool, error) {
+	decision, _, err := r.a.Authorize(ctx, attributesFor(u, attr, nonResAttr))
+	if err != nil {
+		return false, err
+	}
+	return decision == authorizer.DecisionAllow, nil
+}
+
+// attributesFor converts the attributes of a SubjectAccessReview to authorizer attributes,
+// the same way the kube-apiserver does.
+func attributesFor(u user.Info, attr *authorization.ResourceAttributes, nonResAttr *authorization.NonResourceAttributes) authorizer.AttributesRecord {
+	if attr != nil {
+		return authorizer.AttributesRecord{
+			User:            u,
+			Verb:            attr.Verb,
+			Namespace:       attr.Namespace,
+			APIGroup:        attr.Group,
+			APIVersion:      matchAllVersionIfEmpty(attr.Version),
+			Resource:        attr.Resource,
+			Subresource:     attr.Subresource,
+			Name:            attr.Name,
+			ResourceRequest: true,
+		}
+	}
+	return authorizer.AttributesRecord{
+		User:            u,
+		Verb:            nonResAttr.Verb,
+		Path:            nonResAttr.Path,
+		ResourceRequest: false,
+	}
+}
+
+func matchAllVersionIfEmpty(version string) string {
+	if version == "" {
+		return "*"
+	}
+	return version
+}
+
+// decisionKey identifies a check by the user, its groups and extra, and the attributes.
+func decisionKey(u user.Info, attr *authorization.ResourceAttributes, nonResAttr *authorization.NonResourceAttributes) string {
+	var sb strings.Builder
+	sb.WriteString(u.GetName())
+	sb.WriteByte(0)
+	sb.WriteString(u.GetUID())
+	sb.WriteByte(0)
+
+	groups := append([]string(nil), u.GetGroups()...)
+	sort.Strings(groups)
+	sb.WriteString(strings.Join(groups, ","))
+	sb.WriteByte(0)
+
+	extra := u.GetExtra()
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		sb.WriteString(k)
+		sb.WriteByte('=')
+		sb.WriteString(strings.Join(extra[k], ","))
+		sb.WriteByte(';')
+	}
+	sb.WriteByte(0)
+
+	if attr != nil {
+		for _, s := range []string{attr.Namespace, attr.Verb, attr.Group, attr.Version, attr.Resource, attr.Subresource, attr.Name} {
+			sb.WriteString(s)
+			sb.WriteByte(0)
+		}
+	} else {
+		sb.WriteString(nonResAttr.Verb)
+		sb.WriteByte(0)
+		sb.WriteString(nonResAttr.Path)
+	}
+	return sb.String()
+}
@@ -19,14 +19,19 @@ package selfsubjectnamespaceaccessreview
 import (
 	"context"
 	"sort"
+	"sync"
 
-	authorization "k8s.io/api/authorization/v1"
+	"kubeops.dev/ui-server/pkg/registry/core/project"
+
+	"golang.org/x/sync/errgroup"
 	core "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/cache"
 	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
 	"k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/apiserver/pkg/registry/rest"
 	"k8s.io/client-go/kubernetes"
@@ -36,8 +41,12 @@ import (
 )
 
 type Storage struct {
-	kc  kubernetes.Interface
-	rtc client.Client
+	kc        kubernetes.Interface
+	rtc       client.Client
+	a         authorizer.Authorizer
+	projects  project.Source
+	opts      Options
+	decisions *cache.LRUExpireCache
 }
 
 var (
@@ -48,11 +57,24 @@ var (
 	_ rest.SingularNameProvider     = &Storage{}
 )
 
-func NewStorage(kc kubernetes.Interface, rtc client.Client) *Storage {
-	return &Storage{
-		kc:  kc,
-		rtc: rtc,
+// NewStorage returns the storage for SelfSubjectNamespaceAccessReviews. The authorizer is
+// only used if opts.InProcess is set. The allowed namespaces are grouped by the projects of
+// the source, unless it is nil.
+func NewStorage(kc kubernetes.Interface, rtc client.Client, a authorizer.Authorizer, projects project.Source, opts Options) *Storage {
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	s := &Storage{
+		kc:       kc,
+		rtc:      rtc,
+		a:        a,
+		projects: projects,
+		opts:     opts,
+	}
+	if opts.CacheTTL > 0 {
+		s.decisions = cache.NewLRUExpireCache(maxCachedDecisions)
 	}
+	return s
 }
 
 func (r *Storage) GroupVersionKind(_ schema.GroupVersion) schema.GroupVersionKind {
@@ -83,10 +105,6 @@ func (r *Storage) Create(ctx context.Context, obj runtime.Object, _ rest.Validat
 	if !ok {
 		return nil, apierrors.NewBadRequest("missing user info")
 	}
-	extra := make(map[string]authorization.ExtraValue)
-	for k, v := range user.GetExtra() {
-		extra[k] = v
-	}
 
 	var allNs []core.Namespace
 	result, err := clustermeta.IsClientOrgMember(r.rtc, user)
@@ -103,128 +121,117 @@ func (r *Storage) Create(ctx context.Context, obj runtime.Object, _ rest.Validat
 		allNs = list.Items
 	}
 
+	var mu sync.Mutex
 	allowedNs := make([]core.Namespace, 0, len(allNs))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(r.opts.Workers)
 	for _, ns := range allNs {
-		allowed, err := r.hasNamespaceResourceAccess(ctx, in, ns.Name, user, extra)
-		if err != nil {
-			return nil, err
-		}
-		if allowed {
-			allowedNs = append(allowedNs, ns)
-		}
+		g.Go(func() error {
+			allowed, err := r.hasNamespaceResourceAccess(gctx, in, ns.Name, user)
+			if err != nil {
+				return err
+			}
+			if allowed {
+				mu.Lock()
+				allowedNs = append(allowedNs, ns)
+				mu.Unlock()
+			}
+			return nil
+		})
 	}
 
 	// check for all namespaces
-	{
-		allowed, err := r.hasAllNamespaceResourceAccess(ctx, in, user, extra)
+	g.Go(func() error {
+		allowed, err := r.hasAllNamespaceResourceAccess(gctx, in, user)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		if allowed {
-			allowed, err = r.hasNonResourceAccess(ctx, in, user, extra)
+			allowed, err = r.hasNonResourceAccess(gctx, in, user)
 			if err != nil {
-				return nil, err
+				return err
 			}
 		}
 		in.Status.AllNamespaces = allowed
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
 	}
 
-	if clustermeta.IsRancherManaged(r.rtc.RESTMapper()) {
-		projects := map[string][]string{}
-		for _, ns := range allowedNs {
-			projectId, exists := ns.Labels[clustermeta.LabelKeyRancherFieldProjectId]
-			if !exists {
-				projectId = clustermeta.FakeRancherProjectId
-			}
-			projects[projectId] = append(projects[projectId], ns.Name)
+	namespaceProjects := map[string]string{}
+	var rancher bool
+	if r.projects != nil {
+		namespaceProjects, err = r.projects.NamespaceProjects(ctx)
+		if err != nil {
+			return nil, err
 		}
+		rancher = r.projects.Type() == project.SourceRancher
+	}
+	in.Status.Namespaces, in.Status.Projects = groupNamespaces(allowedNs, namespaceProjects, rancher)
 
-		for projectId, namespaces := range projects {
-			sort.Strings(namespaces)
-			projects[projectId] = namespaces
-		}
-		in.Status.Projects = projects
-	} else {
-		namespaces := make([]string, 0, len(allowedNs))
-		for _, ns := range allowedNs {
+	return in, nil
+}
+
+// groupNamespaces groups the namespaces by project. With Rancher projects, every
+// namespace belongs to a project and only the projects are returned. Otherwise, all the
+// namespaces are returned along with the projects of the ones that belong to one.
+func groupNamespaces(allowedNs []core.Namespace, namespaceProjects map[string]string, rancher bool) ([]string, map[string][]string) {
+	var namespaces []string
+	var projects map[string][]string
+	for _, ns := range allowedNs {
+		if !rancher {
 			namespaces = append(namespaces, ns.Name)
 		}
+		if projectId, found := namespaceProjects[ns.Name]; found {
+			if projects == nil {
+				projects = map[string][]string{}
+			}
+			projects[projectId] = append(projects[projectId], ns.Name)
+		}
+	}
 
+	for projectId, list := range projects {
+		sort.Strings(list)
+		projects[projectId] = list
+	}
+	if !rancher {
+		if namespaces == nil {
+			namespaces = []string{}
+		}
 		sort.Strings(namespaces)
-		in.Status.Namespaces = namespaces
 	}
-
-	return in, nil
+	return namespaces, projects
 }
 
-func (r *Storage) hasNonResourceAccess(ctx context.Context, in *identityapi.SelfSubjectNamespaceAccessReview, user user.Info, extra map[string]authorization.ExtraValue) (bool, error) {
+func (r *Storage) hasNonResourceAccess(ctx context.Context, in *identityapi.SelfSubjectNamespaceAccessReview, user user.Info) (bool, error) {
 	for _, attr := range in.Spec.NonResourceAttributes {
-		review := &authorization.SubjectAccessReview{
-			Spec: authorization.SubjectAccessReviewSpec{
-				NonResourceAttributes: &attr,
-				User:                  user.GetName(),
-				Groups:                user.GetGroups(),
-				Extra:                 extra,
-				UID:                   user.GetUID(),
-			},
-		}
-		review, err := r.kc.AuthorizationV1().SubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
-		if err != nil {
+		allowed, err := r.allowed(ctx, user, nil, &attr)
+		if err != nil || !allowed {
 			return false, err
 		}
-		if !review.Status.Allowed {
-			return false, nil
-		}
 	}
 	return true, nil
 }
 
-func (r *Storage) hasAllNamespaceResourceAccess(ctx context.Context, in *identityapi.SelfSubjectNamespaceAccessReview, user user.Info, extra map[string]authorization.ExtraValue) (bool, error) {
+func (r *Storage) hasAllNamespaceResourceAccess(ctx context.Context, in *identityapi.SelfSubjectNamespaceAccessReview, user user.Info) (bool, error) {
 	for _, attr := range in.Spec.ResourceAttributes {
 		attr.Namespace = ""
-		review := &authorization.SubjectAccessReview{
-			Spec: authorization.SubjectAccessReviewSpec{
-				ResourceAttributes: &attr,
-				User:               user.GetName(),
-				Groups:             user.GetGroups(),
-				Extra:              extra,
-				UID:                user.GetUID(),
-			},
-		}
-		review, err := r.kc.AuthorizationV1().SubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
-		if err != nil {
+		allowed, err := r.allowed(ctx, user, &attr, nil)
+		if err != nil || !allowed {
 			return false, err
 		}
-		if !review.Status.Allowed {
-			return false, nil
-		}
 	}
 	return true, nil
 }
 
-func (r *Storage) hasNamespaceResourceAccess(ctx context.Context, in *identityapi.SelfSubjectNamespaceAccessReview, ns string, user user.Info, extra map[string]authorization.ExtraValue) (bool, error) {
+func (r *Storage) hasNamespaceResourceAccess(ctx context.Context, in *identityapi.SelfSubjectNamespaceAccessReview, ns string, user user.Info) (bool, error) {
 	for _, attr := range in.Spec.ResourceAttributes {
 		attr.Namespace = ns
-		review := &authorization.LocalSubjectAccessReview{
-			ObjectMeta: metav1.ObjectMeta{
-				Namespace: ns,
-			},
-			Spec: authorization.SubjectAccessReviewSpec{
-				ResourceAttributes:    &attr,
-				NonResourceAttributes: nil,
-				User:                  user.GetName(),
-				Groups:                user.GetGroups(),
-				Extra:                 extra,
-				UID:                   user.GetUID(),
-			},
-		}
-		review, err := r.kc.AuthorizationV1().LocalSubjectAccessReviews(ns).Create(ctx, review, metav1.CreateOptions{})
-		if err != nil {
+		allowed, err := r.allowed(ctx, user, &attr, nil)
+		if err != nil || !allowed {
 			return false, err
 		}
-		if !review.Status.Allowed {
-			return false, nil
-		}
 	}
 	return true, nil
 }
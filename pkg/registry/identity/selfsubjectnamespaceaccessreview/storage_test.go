@@ -0,0 +1,248 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selfsubjectnamespaceaccessreview
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	authorization "k8s.io/api/authorization/v1"
+	core "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	rbaclisters "k8s.io/client-go/listers/rbac/v1"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	rbacauthorizer "kmodules.xyz/authorizer/rbac"
+	identityapi "kmodules.xyz/resource-metadata/apis/identity/v1alpha1"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newRBACAuthorizer(t *testing.T, objs ...runtime.Object) authorizer.Authorizer {
+	t.Helper()
+	roles := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	roleBindings := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	clusterRoles := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	clusterRoleBindings := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, obj := range objs {
+		var err error
+		switch obj.(type) {
+		case *rbac.Role:
+			err = roles.Add(obj)
+		case *rbac.RoleBinding:
+			err = roleBindings.Add(obj)
+		case *rbac.ClusterRole:
+			err = clusterRoles.Add(obj)
+		case *rbac.ClusterRoleBinding:
+			err = clusterRoleBindings.Add(obj)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return rbacauthorizer.New(
+		&rbacauthorizer.RoleGetter{Lister: rbaclisters.NewRoleLister(roles)},
+		&rbacauthorizer.RoleBindingLister{Lister: rbaclisters.NewRoleBindingLister(roleBindings)},
+		&rbacauthorizer.ClusterRoleGetter{Lister: rbaclisters.NewClusterRoleLister(clusterRoles)},
+		&rbacauthorizer.ClusterRoleBindingLister{Lister: rbaclisters.NewClusterRoleBindingLister(clusterRoleBindings)},
+	)
+}
+
+// newSARClient returns a clientset that answers SubjectAccessReviews using the authorizer,
+// like a kube-apiserver running the RBAC authorizer.
+func newSARClient(a authorizer.Authorizer, calls *atomic.Int32) *fake.Clientset {
+	kc := fake.NewClientset()
+	kc.PrependReactor("create", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		obj := action.(k8stesting.CreateAction).GetObject()
+		var spec *authorization.SubjectAccessReviewSpec
+		var status *authorization.SubjectAccessReviewStatus
+		switch review := obj.(type) {
+		case *authorization.SubjectAccessReview:
+			spec, status = &review.Spec, &review.Status
+		case *authorization.LocalSubjectAccessReview:
+			spec, status = &review.Spec, &review.Status
+		default:
+			return false, nil, nil
+		}
+		calls.Add(1)
+
+		extra := map[string][]string{}
+		for k, v := range spec.Extra {
+			extra[k] = v
+		}
+		u := &user.DefaultInfo{Name: spec.User, UID: spec.UID, Groups: spec.Groups, Extra: extra}
+		decision, _, err := a.Authorize(context.TODO(), attributesFor(u, spec.ResourceAttributes, spec.NonResourceAttributes))
+		if err != nil {
+			return true, nil, err
+		}
+		status.Allowed = decision == authorizer.DecisionAllow
+		return true, obj, nil
+	})
+	return kc
+}
+
+func TestCreateIdenticalResults(t *testing.T) {
+	var objs []runtime.Object
+	var namespaces []runtime.Object
+	for i := 0; i < 50; i++ {
+		ns := fmt.Sprintf("ns-%02d", i)
+		namespaces = append(namespaces, &core.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}})
+		switch i % 3 {
+		case 0:
+			// can list and create pods
+			objs = append(objs, &rbac.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "edit", Namespace: ns},
+				RoleRef:    rbac.RoleRef{APIGroup: rbac.GroupName, Kind: "ClusterRole", Name: "pod-editor"},
+				Subjects:   []rbac.Subject{{Kind: rbac.GroupKind, Name: "devs"}},
+			})
+		case 1:
+			// can only list pods
+			objs = append(objs, &rbac.Role{
+				ObjectMeta: metav1.ObjectMeta{Name: "view", Namespace: ns},
+				Rules:      []rbac.PolicyRule{{Verbs: []string{"list"}, APIGroups: []string{""}, Resources: []string{"pods"}}},
+			}, &rbac.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "view", Namespace: ns},
+				RoleRef:    rbac.RoleRef{APIGroup: rbac.GroupName, Kind: "Role", Name: "view"},
+				Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "alice"}},
+			})
+		}
+	}
+	objs = append(objs,
+		&rbac.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod-editor"},
+			Rules:      []rbac.PolicyRule{{Verbs: []string{"list", "create"}, APIGroups: []string{""}, Resources: []string{"pods"}}},
+		},
+		&rbac.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "healthz"},
+			Rules:      []rbac.PolicyRule{{Verbs: []string{"get"}, NonResourceURLs: []string{"/healthz"}}},
+		},
+		&rbac.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "admin-pods"},
+			RoleRef:    rbac.RoleRef{APIGroup: rbac.GroupName, Kind: "ClusterRole", Name: "pod-editor"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "admin"}},
+		},
+		&rbac.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "admin-healthz"},
+			RoleRef:    rbac.RoleRef{APIGroup: rbac.GroupName, Kind: "ClusterRole", Name: "healthz"},
+			Subjects:   []rbac.Subject{{Kind: rbac.UserKind, Name: "admin"}},
+		},
+	)
+
+	a := newRBACAuthorizer(t, objs...)
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	rtc := crfake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(namespaces...).Build()
+
+	var calls atomic.Int32
+	kc := newSARClient(a, &calls)
+
+	users := []*user.DefaultInfo{
+		{Name: "alice", Groups: []string{"devs"}},
+		{Name: "alice"},
+		{Name: "bob", Groups: []string{"devs"}},
+		{Name: "admin"},
+		{Name: "nobody"},
+	}
+	specs := []identityapi.SelfSubjectNamespaceAccessReviewSpec{
+		{ResourceAttributes: []authorization.ResourceAttributes{{Verb: "list", Resource: "pods"}}},
+		{ResourceAttributes: []authorization.ResourceAttributes{{Verb: "list", Resource: "pods"}, {Verb: "create", Resource: "pods"}}},
+		{
+			ResourceAttributes:    []authorization.ResourceAttributes{{Verb: "list", Resource: "pods"}},
+			NonResourceAttributes: []authorization.NonResourceAttributes{{Verb: "get", Path: "/healthz"}},
+		},
+	}
+
+	sequential := NewStorage(kc, rtc, nil, nil, Options{})
+	parallel := NewStorage(kc, rtc, nil, nil, Options{Workers: 8, CacheTTL: time.Minute})
+	inProcess := NewStorage(kc, rtc, a, nil, Options{Workers: 8, CacheTTL: time.Minute, InProcess: true})
+
+	review := func(s *Storage, u user.Info, spec identityapi.SelfSubjectNamespaceAccessReviewSpec) identityapi.SubjectAccessNamespaceReviewStatus {
+		t.Helper()
+		ctx := apirequest.WithUser(context.TODO(), u)
+		obj, err := s.Create(ctx, &identityapi.SelfSubjectNamespaceAccessReview{Spec: spec}, nil, &metav1.CreateOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return obj.(*identityapi.SelfSubjectNamespaceAccessReview).Status
+	}
+
+	for _, u := range users {
+		for i, spec := range specs {
+			want := review(sequential, u, spec)
+			if got := review(parallel, u, spec); !reflect.DeepEqual(got, want) {
+				t.Errorf("user %s spec %d: parallel review returned %+v, expected %+v", u.Name, i, got, want)
+			}
+			if got := review(inProcess, u, spec); !reflect.DeepEqual(got, want) {
+				t.Errorf("user %s spec %d: in-process review returned %+v, expected %+v", u.Name, i, got, want)
+			}
+		}
+	}
+
+	if got := review(sequential, users[0], specs[0]); len(got.Namespaces) != 34 || got.AllNamespaces {
+		t.Errorf("unexpected review of alice %+v", got)
+	}
+	if got := review(sequential, users[3], specs[2]); !got.AllNamespaces || len(got.Namespaces) != 50 {
+		t.Errorf("unexpected review of admin %+v", got)
+	}
+
+	calls.Store(0)
+	for _, u := range users {
+		for _, spec := range specs {
+			review(parallel, u, spec)
+		}
+	}
+	if n := calls.Load(); n != 0 {
+		t.Errorf("expected cached decisions, found %d SubjectAccessReviews", n)
+	}
+}
+
+func TestGroupNamespaces(t *testing.T) {
+	allowed := []core.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "scratch"}},
+	}
+	namespaceProjects := map[string]string{"team-a": "a", "team-b": "a", "other": "b"}
+
+	namespaces, projects := groupNamespaces(allowed, namespaceProjects, false)
+	if !reflect.DeepEqual(namespaces, []string{"scratch", "team-a", "team-b"}) {
+		t.Errorf("unexpected namespaces %v", namespaces)
+	}
+	if !reflect.DeepEqual(projects, map[string][]string{"a": {"team-a", "team-b"}}) {
+		t.Errorf("unexpected projects %v", projects)
+	}
+
+	namespaces, projects = groupNamespaces(allowed, namespaceProjects, true)
+	if namespaces != nil || len(projects["a"]) != 2 {
+		t.Errorf("expected only projects on Rancher managed clusters, got %v and %v", namespaces, projects)
+	}
+
+	namespaces, projects = groupNamespaces(nil, nil, false)
+	if namespaces == nil || len(namespaces) != 0 || projects != nil {
+		t.Errorf("expected an empty namespace list, got %v and %v", namespaces, projects)
+	}
+}
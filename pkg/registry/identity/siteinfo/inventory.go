@@ -0,0 +1,117 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package siteinfo
+
+import (
+	"strings"
+
+	identitylocalapi "kubeops.dev/ui-server/apis/identity/v1alpha1"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+	identityapi "kmodules.xyz/resource-metadata/apis/identity/v1alpha1"
+	identitylib "kmodules.xyz/resource-metadata/pkg/identity"
+)
+
+// labelValue returns the value of the first label found on the node.
+func labelValue(node *core.Node, keys ...string) string {
+	for _, key := range keys {
+		if v := node.Labels[key]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// isExtendedResource returns true for resources like nvidia.com/gpu, that are
+// advertised by device plugins.
+func isExtendedResource(name core.ResourceName) bool {
+	return strings.Contains(string(name), "/") &&
+		!strings.HasPrefix(string(name), core.ResourceHugePagesPrefix) &&
+		!strings.HasPrefix(string(name), core.ResourceDefaultNamespacePrefix)
+}
+
+// refreshNodeStats replaces the node stats of the site info, which are cached since it was
+// detected, with the stats of the nodes.
+func refreshNodeStats(si *identityapi.SiteInfo, nodes []core.Node) {
+	si.Kubernetes.NodeStats = identityapi.NodeInfo{}
+	refs := make([]*core.Node, 0, len(nodes))
+	for i := range nodes {
+		refs = append(refs, &nodes[i])
+	}
+	identitylib.RefreshNodeStats(si, refs)
+}
+
+// nodeInventory breaks down the nodes using their labels and status. The control plane and
+// worker counts are taken from the node stats.
+func nodeInventory(nodes []core.Node, stats identityapi.NodeInfo) *identitylocalapi.NodeInventory {
+	inv := &identitylocalapi.NodeInventory{}
+	if stats.ControlPlane != nil {
+		inv.ControlPlane = stats.ControlPlane.Count
+	}
+	if stats.Workers != nil {
+		inv.Workers = stats.Workers.Count
+	}
+	count := func(m *map[string]int, v string) {
+		if v == "" {
+			return
+		}
+		if *m == nil {
+			*m = map[string]int{}
+		}
+		(*m)[v]++
+	}
+
+	var minKubelet, maxKubelet *version.Version
+	for i := range nodes {
+		node := &nodes[i]
+		info := node.Status.NodeInfo
+		count(&inv.Architectures, info.Architecture)
+		count(&inv.OperatingSystems, info.OperatingSystem)
+		count(&inv.OSImages, info.OSImage)
+		count(&inv.KernelVersions, info.KernelVersion)
+		count(&inv.ContainerRuntimeVersions, info.ContainerRuntimeVersion)
+		count(&inv.KubeletVersions, info.KubeletVersion)
+		count(&inv.InstanceTypes, labelValue(node, core.LabelInstanceTypeStable, core.LabelInstanceType))
+		count(&inv.Zones, labelValue(node, core.LabelTopologyZone, core.LabelFailureDomainBetaZone))
+		count(&inv.Regions, labelValue(node, core.LabelTopologyRegion, core.LabelFailureDomainBetaRegion))
+
+		for name, q := range node.Status.Capacity {
+			if isExtendedResource(name) {
+				if inv.ExtendedResources == nil {
+					inv.ExtendedResources = core.ResourceList{}
+				}
+				total := inv.ExtendedResources[name]
+				total.Add(q)
+				inv.ExtendedResources[name] = total
+			}
+		}
+
+		if v, err := version.ParseGeneric(info.KubeletVersion); err == nil {
+			if minKubelet == nil || v.LessThan(minKubelet) {
+				minKubelet = v
+			}
+			if maxKubelet == nil || maxKubelet.LessThan(v) {
+				maxKubelet = v
+			}
+		}
+	}
+	if minKubelet != nil && minKubelet.Major() == maxKubelet.Major() {
+		inv.KubeletVersionSkew = int(maxKubelet.Minor() - minKubelet.Minor())
+	}
+	return inv
+}
@@ -0,0 +1,102 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package siteinfo
+
+import (
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	identityapi "kmodules.xyz/resource-metadata/apis/identity/v1alpha1"
+)
+
+func newNode(name string, labels map[string]string, kubelet string, capacity core.ResourceList) core.Node {
+	return core.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Status: core.NodeStatus{
+			Capacity:    capacity,
+			Allocatable: capacity,
+			NodeInfo: core.NodeSystemInfo{
+				Architecture:            "amd64",
+				OperatingSystem:         "linux",
+				OSImage:                 "Ubuntu 22.04.4 LTS",
+				KernelVersion:           "5.15.0-105-generic",
+				ContainerRuntimeVersion: "containerd://1.7.13",
+				KubeletVersion:          kubelet,
+			},
+		},
+	}
+}
+
+func TestNodeInventory(t *testing.T) {
+	cpu := core.ResourceList{core.ResourceCPU: resource.MustParse("4"), core.ResourcePods: resource.MustParse("110")}
+	gpu := core.ResourceList{
+		core.ResourceCPU:                       resource.MustParse("8"),
+		"nvidia.com/gpu":                       resource.MustParse("2"),
+		"hugepages-2Mi":                        resource.MustParse("0"),
+		core.ResourceName("kubernetes.io/foo"): resource.MustParse("1"),
+	}
+	nodes := []core.Node{
+		newNode("cp", map[string]string{"node-role.kubernetes.io/control-plane": ""}, "v1.29.3", cpu),
+		newNode("w1", map[string]string{
+			core.LabelInstanceTypeStable: "m5.xlarge",
+			core.LabelTopologyZone:       "us-east-1a",
+			core.LabelTopologyRegion:     "us-east-1",
+		}, "v1.29.3", cpu),
+		newNode("w2", map[string]string{
+			core.LabelInstanceType:            "p3.2xlarge",
+			core.LabelFailureDomainBetaZone:   "us-east-1b",
+			core.LabelFailureDomainBetaRegion: "us-east-1",
+		}, "v1.27.10-eks-1234", gpu),
+	}
+
+	// stats of the cluster at the time the site info was detected
+	si := identityapi.SiteInfo{Kubernetes: &identityapi.KubernetesInfo{}}
+	refreshNodeStats(&si, nodes[:2])
+	refreshNodeStats(&si, nodes)
+	stats := si.Kubernetes.NodeStats
+	inv := nodeInventory(nodes, stats)
+
+	if inv.ControlPlane != 1 || inv.Workers != 2 {
+		t.Errorf("unexpected control plane %d and workers %d", inv.ControlPlane, inv.Workers)
+	}
+	if stats.Count != 3 || stats.ControlPlane == nil || stats.ControlPlane.Count != 1 || stats.Workers == nil || stats.Workers.Count != 2 {
+		t.Errorf("unexpected node stats %+v", stats)
+	}
+	if cpus := stats.Workers.Capacity[core.ResourceCPU]; cpus.Value() != 12 {
+		t.Errorf("expected 12 worker cpus, got %s", cpus.String())
+	}
+	if inv.KubeletVersionSkew != 2 {
+		t.Errorf("expected kubelet version skew 2, got %d", inv.KubeletVersionSkew)
+	}
+	if inv.Regions["us-east-1"] != 2 || inv.Zones["us-east-1a"] != 1 || inv.Zones["us-east-1b"] != 1 {
+		t.Errorf("unexpected regions %v and zones %v", inv.Regions, inv.Zones)
+	}
+	if inv.InstanceTypes["m5.xlarge"] != 1 || inv.InstanceTypes["p3.2xlarge"] != 1 || len(inv.InstanceTypes) != 2 {
+		t.Errorf("unexpected instance types %v", inv.InstanceTypes)
+	}
+	if inv.Architectures["amd64"] != 3 || inv.KubeletVersions["v1.29.3"] != 2 {
+		t.Errorf("unexpected architectures %v and kubelet versions %v", inv.Architectures, inv.KubeletVersions)
+	}
+	if len(inv.ExtendedResources) != 1 {
+		t.Errorf("unexpected extended resources %v", inv.ExtendedResources)
+	}
+	if gpus := inv.ExtendedResources["nvidia.com/gpu"]; gpus.Value() != 2 {
+		t.Errorf("expected 2 gpus, got %s", gpus.String())
+	}
+}
@@ -18,10 +18,13 @@ package siteinfo
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"strings"
 	"time"
 
+	identitylocalapi "kubeops.dev/ui-server/apis/identity/v1alpha1"
+
 	"github.com/google/uuid"
 	"gomodules.xyz/sync"
 	core "k8s.io/api/core/v1"
@@ -36,7 +39,6 @@ import (
 	restclient "k8s.io/client-go/rest"
 	identityapi "kmodules.xyz/resource-metadata/apis/identity/v1alpha1"
 	identitylib "kmodules.xyz/resource-metadata/pkg/identity"
-	"kmodules.xyz/resource-metrics/api"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -146,16 +148,16 @@ func (r *Storage) getCurrentSiteInfo() (*identityapi.SiteInfo, error) {
 		return nil, err
 	}
 
-	si.Kubernetes.NodeStats.Count = len(nodes.Items)
-
-	var capacity core.ResourceList
-	var allocatable core.ResourceList
-	for _, node := range nodes.Items {
-		capacity = api.AddResourceList(capacity, node.Status.Capacity)
-		allocatable = api.AddResourceList(allocatable, node.Status.Allocatable)
+	refreshNodeStats(si, nodes.Items)
+	inv := nodeInventory(nodes.Items, si.Kubernetes.NodeStats)
+	data, err := json.Marshal(inv)
+	if err != nil {
+		return nil, err
+	}
+	if si.Annotations == nil {
+		si.Annotations = map[string]string{}
 	}
-	si.Kubernetes.NodeStats.Capacity = capacity
-	si.Kubernetes.NodeStats.Allocatable = allocatable
+	si.Annotations[identitylocalapi.AnnotationNodeInventory] = string(data)
 
 	return si, nil
 }
@@ -0,0 +1,126 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addofflinelicense
+
+import (
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	licenseapi "kubeops.dev/ui-server/apis/offline/v1alpha1"
+
+	verifier "go.bytebuilders.dev/license-verifier"
+	"go.bytebuilders.dev/license-verifier/info"
+	"gomodules.xyz/sets"
+)
+
+var (
+	caMu   sync.Mutex
+	caCert *x509.Certificate
+)
+
+// LicenseCA returns the certificate of the CA that issues the licenses.
+// It is loaded once, as it may be downloaded.
+func LicenseCA() (*x509.Certificate, error) {
+	caMu.Lock()
+	defer caMu.Unlock()
+
+	if caCert != nil {
+		return caCert, nil
+	}
+	data, err := info.LoadLicenseCA()
+	if err != nil {
+		return nil, err
+	}
+	crt, err := info.ParseCertificate(data)
+	if err != nil {
+		return nil, err
+	}
+	caCert = crt
+	return caCert, nil
+}
+
+// Review checks whether a license would work in the cluster with the given UID. It runs the
+// same verification as the license-proxyserver and explains every check that fails.
+func Review(req *licenseapi.OfflineLicenseReviewRequest, clusterUID string, ca *x509.Certificate, now time.Time) *licenseapi.OfflineLicenseReviewResponse {
+	resp := &licenseapi.OfflineLicenseReviewResponse{
+		ClusterUID: clusterUID,
+	}
+	addDiagnostic := func(t licenseapi.LicenseDiagnosticType, field, expected, actual, msg string) {
+		resp.Diagnostics = append(resp.Diagnostics, licenseapi.LicenseDiagnostic{
+			Type:     t,
+			Field:    field,
+			Expected: expected,
+			Actual:   actual,
+			Message:  msg,
+		})
+	}
+
+	crt, err := info.ParseCertificate([]byte(req.License))
+	if err != nil {
+		addDiagnostic(licenseapi.LicenseDiagnosticInvalidFormat, "", "", "", err.Error())
+		return resp
+	}
+	if product, err := GetProductKey([]byte(req.License)); err == nil {
+		resp.Product = product
+	} else {
+		addDiagnostic(licenseapi.LicenseDiagnosticInvalidFormat, "planName", "", "", err.Error())
+	}
+
+	license, verifyErr := verifier.ParseLicense(verifier.ParserOptions{
+		ClusterUID: clusterUID,
+		CACert:     ca,
+		License:    []byte(req.License),
+	})
+	resp.License = &license
+
+	if !strings.HasPrefix(crt.Subject.CommonName, "*.") && !sets.NewString(crt.DNSNames...).Has(clusterUID) {
+		addDiagnostic(licenseapi.LicenseDiagnosticClusterMismatch, "clusters", clusterUID, strings.Join(crt.DNSNames, ","),
+			fmt.Sprintf("license was issued for cluster %s, not %s", strings.Join(crt.DNSNames, ","), clusterUID))
+	}
+	if now.After(crt.NotAfter) {
+		addDiagnostic(licenseapi.LicenseDiagnosticExpired, "notAfter", now.UTC().Format(time.RFC3339), crt.NotAfter.UTC().Format(time.RFC3339),
+			fmt.Sprintf("license expired at %s", crt.NotAfter.UTC().Format(time.RFC3339)))
+	}
+	if now.Before(crt.NotBefore) {
+		addDiagnostic(licenseapi.LicenseDiagnosticNotYetValid, "notBefore", now.UTC().Format(time.RFC3339), crt.NotBefore.UTC().Format(time.RFC3339),
+			fmt.Sprintf("license is not valid before %s", crt.NotBefore.UTC().Format(time.RFC3339)))
+	}
+	if req.Product != "" && resp.Product != req.Product {
+		addDiagnostic(licenseapi.LicenseDiagnosticProductMismatch, "planName", req.Product, resp.Product,
+			fmt.Sprintf("license was issued for %s, not %s", resp.Product, req.Product))
+	}
+	features := sets.NewString(crt.Subject.Organization...)
+	for _, f := range req.Features {
+		if !features.Has(f) {
+			addDiagnostic(licenseapi.LicenseDiagnosticMissingFeature, "features", f, strings.Join(crt.Subject.Organization, ","),
+				fmt.Sprintf("license was not issued for %s", f))
+		}
+	}
+	if err := crt.CheckSignatureFrom(ca); err != nil {
+		addDiagnostic(licenseapi.LicenseDiagnosticUntrustedIssuer, "issuer", ca.Subject.CommonName, crt.Issuer.CommonName,
+			fmt.Sprintf("license was not signed by the license CA: %v", err))
+	}
+	if verifyErr != nil && len(resp.Diagnostics) == 0 {
+		addDiagnostic(licenseapi.LicenseDiagnosticVerificationFailed, "", "", "", verifyErr.Error())
+	}
+
+	resp.Valid = len(resp.Diagnostics) == 0
+	return resp
+}
@@ -0,0 +1,187 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addofflinelicense
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	licenseapi "kubeops.dev/ui-server/apis/offline/v1alpha1"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "license-ca"},
+		NotBefore:             time.Now().AddDate(-5, 0, 0),
+		NotAfter:              time.Now().AddDate(5, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	crt, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: crt, key: key}
+}
+
+func (ca *testCA) issue(t *testing.T, cluster, product string, notBefore, notAfter time.Time) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject: pkix.Name{
+			CommonName:         cluster,
+			OrganizationalUnit: []string{product},
+			Organization:       []string{"kubedb"},
+		},
+		DNSNames:    []string{cluster},
+		NotBefore:   notBefore,
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func diagnosticTypes(resp *licenseapi.OfflineLicenseReviewResponse) []licenseapi.LicenseDiagnosticType {
+	var out []licenseapi.LicenseDiagnosticType
+	for _, d := range resp.Diagnostics {
+		out = append(out, d.Type)
+	}
+	return out
+}
+
+func TestReview(t *testing.T) {
+	now := time.Now()
+	ca := newTestCA(t)
+	other := newTestCA(t)
+
+	tests := []struct {
+		name string
+		req  licenseapi.OfflineLicenseReviewRequest
+		ca   *x509.Certificate
+		want []licenseapi.LicenseDiagnosticType
+	}{
+		{
+			name: "valid",
+			req: licenseapi.OfflineLicenseReviewRequest{
+				License:  ca.issue(t, "cluster-uid", "kubedb-enterprise", now.AddDate(0, -1, 0), now.AddDate(0, 1, 0)),
+				Product:  "kubedb-enterprise",
+				Features: []string{"kubedb"},
+			},
+			ca: ca.cert,
+		},
+		{
+			name: "not a certificate",
+			req:  licenseapi.OfflineLicenseReviewRequest{License: "not a license"},
+			ca:   ca.cert,
+			want: []licenseapi.LicenseDiagnosticType{licenseapi.LicenseDiagnosticInvalidFormat},
+		},
+		{
+			name: "other cluster",
+			req: licenseapi.OfflineLicenseReviewRequest{
+				License: ca.issue(t, "other-uid", "kubedb-enterprise", now.AddDate(0, -1, 0), now.AddDate(0, 1, 0)),
+			},
+			ca:   ca.cert,
+			want: []licenseapi.LicenseDiagnosticType{licenseapi.LicenseDiagnosticClusterMismatch},
+		},
+		{
+			name: "expired",
+			req: licenseapi.OfflineLicenseReviewRequest{
+				License: ca.issue(t, "cluster-uid", "kubedb-enterprise", now.AddDate(0, -2, 0), now.AddDate(0, -1, 0)),
+			},
+			ca:   ca.cert,
+			want: []licenseapi.LicenseDiagnosticType{licenseapi.LicenseDiagnosticExpired},
+		},
+		{
+			name: "not yet valid",
+			req: licenseapi.OfflineLicenseReviewRequest{
+				License: ca.issue(t, "cluster-uid", "kubedb-enterprise", now.AddDate(0, 1, 0), now.AddDate(0, 2, 0)),
+			},
+			ca:   ca.cert,
+			want: []licenseapi.LicenseDiagnosticType{licenseapi.LicenseDiagnosticNotYetValid},
+		},
+		{
+			name: "wrong product and feature",
+			req: licenseapi.OfflineLicenseReviewRequest{
+				License:  ca.issue(t, "cluster-uid", "stash-enterprise", now.AddDate(0, -1, 0), now.AddDate(0, 1, 0)),
+				Product:  "kubedb-enterprise",
+				Features: []string{"stash"},
+			},
+			ca: ca.cert,
+			want: []licenseapi.LicenseDiagnosticType{
+				licenseapi.LicenseDiagnosticProductMismatch,
+				licenseapi.LicenseDiagnosticMissingFeature,
+			},
+		},
+		{
+			name: "untrusted issuer",
+			req: licenseapi.OfflineLicenseReviewRequest{
+				License: other.issue(t, "cluster-uid", "kubedb-enterprise", now.AddDate(0, -1, 0), now.AddDate(0, 1, 0)),
+			},
+			ca:   ca.cert,
+			want: []licenseapi.LicenseDiagnosticType{licenseapi.LicenseDiagnosticUntrustedIssuer},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := Review(&tt.req, "cluster-uid", tt.ca, now)
+			got := diagnosticTypes(resp)
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected diagnostics %v, got %+v", tt.want, resp.Diagnostics)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("expected diagnostics %v, got %v", tt.want, got)
+				}
+			}
+			if resp.Valid != (len(tt.want) == 0) {
+				t.Errorf("unexpected valid %v", resp.Valid)
+			}
+		})
+	}
+}
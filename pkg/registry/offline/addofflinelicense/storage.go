@@ -18,8 +18,11 @@ package addofflinelicense
 
 import (
 	"context"
+	"crypto/x509"
 	"errors"
+	"sort"
 	"strings"
+	"time"
 
 	licenseapi "kubeops.dev/ui-server/apis/offline/v1alpha1"
 
@@ -51,8 +54,10 @@ var secretGR = schema.GroupResource{
 }
 
 type Storage struct {
-	kc client.Client
-	a  authorizer.Authorizer
+	kc         client.Client
+	a          authorizer.Authorizer
+	clusterUID string
+	loadCA     func() (*x509.Certificate, error)
 }
 
 var (
@@ -63,10 +68,12 @@ var (
 	_ rest.SingularNameProvider     = &Storage{}
 )
 
-func NewStorage(kc client.Client, a authorizer.Authorizer) *Storage {
+func NewStorage(kc client.Client, a authorizer.Authorizer, clusterUID string) *Storage {
 	return &Storage{
-		kc: kc,
-		a:  a,
+		kc:         kc,
+		a:          a,
+		clusterUID: clusterUID,
+		loadCA:     LicenseCA,
 	}
 }
 
@@ -88,7 +95,7 @@ func (r *Storage) New() runtime.Object {
 
 func (r *Storage) Destroy() {}
 
-func (r *Storage) Create(ctx context.Context, obj runtime.Object, _ rest.ValidateObjectFunc, _ *metav1.CreateOptions) (runtime.Object, error) {
+func (r *Storage) Create(ctx context.Context, obj runtime.Object, _ rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
 	in := obj.(*licenseapi.AddOfflineLicense)
 	if in.Request == nil {
 		return nil, apierrors.NewBadRequest("missing apirequest")
@@ -106,16 +113,14 @@ func (r *Storage) Create(ctx context.Context, obj runtime.Object, _ rest.Validat
 	if req.License == "" {
 		return nil, apierrors.NewBadRequest("missing license info")
 	}
+	if options != nil && isDryRun(options.DryRun) {
+		return r.dryRun(ctx, in)
+	}
 
 	var vt kutil.VerbType
 	defer func() {
 		if vt != kutil.VerbUnchanged {
-			// restart license-proxyserver pods
-			e2 := r.kc.DeleteAllOf(ctx, &core.Pod{}, client.InNamespace(meta.PodNamespace()), client.MatchingLabels{
-				"app.kubernetes.io/instance": "license-proxyserver",
-				"app.kubernetes.io/name":     "license-proxyserver",
-			})
-			klog.InfoS("restarted license-proxyserver pods", "err", e2)
+			RestartLicenseProxyServer(ctx, r.kc)
 		}
 	}()
 
@@ -140,7 +145,7 @@ func (r *Storage) Create(ctx context.Context, obj runtime.Object, _ rest.Validat
 			return nil, apierrors.NewForbidden(secretGR, LicenseSecretName, errors.New(why))
 		}
 
-		productKey, err := getProductKey([]byte(req.License))
+		productKey, err := GetProductKey([]byte(req.License))
 		if err != nil {
 			return nil, err
 		}
@@ -190,17 +195,21 @@ func (r *Storage) Create(ctx context.Context, obj runtime.Object, _ rest.Validat
 		return nil, apierrors.NewForbidden(secretGR, LicenseSecretName, errors.New(why))
 	}
 
-	productKey, err := getProductKey([]byte(req.License))
+	productKey, err := GetProductKey([]byte(req.License))
 	if err != nil {
 		return nil, err
 	}
 
+	var pruned []string
 	vt, err = cg.CreateOrPatch(ctx, r.kc, &licenseSecret, func(obj client.Object, createOp bool) client.Object {
 		in := obj.(*core.Secret)
 		if in.Data == nil {
 			in.Data = map[string][]byte{}
 		}
 		in.Data[productKey] = []byte(req.License)
+		if req.PruneExpired {
+			pruned = PruneExpired(in.Data, productKey, time.Now())
+		}
 		return in
 	})
 	if err != nil {
@@ -214,14 +223,113 @@ func (r *Storage) Create(ctx context.Context, obj runtime.Object, _ rest.Validat
 			},
 			Key: productKey,
 		},
+		Pruned: pruned,
 	}
 	return in, nil
 }
 
-func getProductKey(lic []byte) (string, error) {
+// dryRun reviews the license and reports the changes to the license Secret, without writing it.
+func (r *Storage) dryRun(ctx context.Context, in *licenseapi.AddOfflineLicense) (runtime.Object, error) {
+	req := in.Request
+	user, _ := apirequest.UserFrom(ctx)
+
+	var licenseSecret core.Secret
+	verb := "patch"
+	err := r.kc.Get(ctx, types.NamespacedName{Name: LicenseSecretName, Namespace: req.Namespace}, &licenseSecret)
+	if apierrors.IsNotFound(err) {
+		verb = "create"
+	} else if err != nil {
+		return nil, err
+	}
+
+	attrs := authorizer.AttributesRecord{
+		User:            user,
+		Verb:            verb,
+		Namespace:       req.Namespace,
+		APIGroup:        secretGR.Group,
+		Resource:        secretGR.Resource,
+		Name:            LicenseSecretName,
+		ResourceRequest: true,
+	}
+	decision, why, err := r.a.Authorize(ctx, attrs)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	if decision != authorizer.DecisionAllow {
+		return nil, apierrors.NewForbidden(secretGR, LicenseSecretName, errors.New(why))
+	}
+
+	ca, err := r.loadCA()
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	review := Review(&licenseapi.OfflineLicenseReviewRequest{License: req.License}, r.clusterUID, ca, time.Now())
+
+	in.Response = &licenseapi.AddOfflineLicenseResponse{
+		Review: review,
+	}
+	if review.Product == "" {
+		return in, nil
+	}
+	in.Response.SecretKeyRef = &core.SecretKeySelector{
+		LocalObjectReference: core.LocalObjectReference{
+			Name: LicenseSecretName,
+		},
+		Key: review.Product,
+	}
+	if req.PruneExpired && licenseSecret.Data != nil {
+		data := make(map[string][]byte, len(licenseSecret.Data))
+		for k, v := range licenseSecret.Data {
+			data[k] = v
+		}
+		in.Response.Pruned = PruneExpired(data, review.Product, time.Now())
+	}
+	return in, nil
+}
+
+func isDryRun(dryRun []string) bool {
+	return len(dryRun) > 0 && dryRun[0] == metav1.DryRunAll
+}
+
+// RestartLicenseProxyServer restarts the license-proxyserver pods, so that they load the updated licenses.
+func RestartLicenseProxyServer(ctx context.Context, kc client.Client) {
+	err := kc.DeleteAllOf(ctx, &core.Pod{}, client.InNamespace(meta.PodNamespace()), client.MatchingLabels{
+		"app.kubernetes.io/instance": "license-proxyserver",
+		"app.kubernetes.io/name":     "license-proxyserver",
+	})
+	klog.InfoS("restarted license-proxyserver pods", "err", err)
+}
+
+// PruneExpired removes the licenses that expired before now from the license Secret data,
+// except the license of the keep product. It returns the sorted products that were removed.
+// Entries that can't be parsed are left alone.
+func PruneExpired(data map[string][]byte, keep string, now time.Time) []string {
+	var pruned []string
+	for product, lic := range data {
+		if product == keep {
+			continue
+		}
+		certs, err := cert.ParseCertsPEM(lic)
+		if err != nil || len(certs) == 0 {
+			continue
+		}
+		if certs[0].NotAfter.Before(now) {
+			delete(data, product)
+			pruned = append(pruned, product)
+		}
+	}
+	sort.Strings(pruned)
+	return pruned
+}
+
+// GetProductKey returns the key of a license in the license Secret.
+func GetProductKey(lic []byte) (string, error) {
 	certs, err := cert.ParseCertsPEM(lic)
 	if err != nil {
 		return "", err
 	}
+	if len(certs[0].Subject.OrganizationalUnit) == 0 {
+		return "", apierrors.NewBadRequest("license does not name a product")
+	}
 	return certs[0].Subject.OrganizationalUnit[0], nil
 }
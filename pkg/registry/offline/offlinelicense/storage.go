@@ -18,6 +18,9 @@ package offlinelicense
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
 	"strings"
 
 	licenseapi "kubeops.dev/ui-server/apis/offline/v1alpha1"
@@ -29,17 +32,31 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/internalversion"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
 	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/generic"
 	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/apiserver/pkg/storage"
 	"k8s.io/client-go/util/cert"
+	cg "kmodules.xyz/client-go/client"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+var secretGR = schema.GroupResource{
+	Group:    "",
+	Resource: "secrets",
+}
+
 type Storage struct {
 	kc        client.Client
+	a         authorizer.Authorizer
+	informers cache.Informers
 	convertor rest.TableConvertor
 }
 
@@ -47,13 +64,19 @@ var (
 	_ rest.GroupVersionKindProvider = &Storage{}
 	_ rest.Scoper                   = &Storage{}
 	_ rest.Storage                  = &Storage{}
+	_ rest.Getter                   = &Storage{}
 	_ rest.Lister                   = &Storage{}
+	_ rest.Watcher                  = &Storage{}
+	_ rest.Updater                  = &Storage{}
+	_ rest.GracefulDeleter          = &Storage{}
 	_ rest.SingularNameProvider     = &Storage{}
 )
 
-func NewStorage(kc client.Client) *Storage {
+func NewStorage(kc client.Client, a authorizer.Authorizer, informers cache.Informers) *Storage {
 	return &Storage{
-		kc: kc,
+		kc:        kc,
+		a:         a,
+		informers: informers,
 		convertor: rest.NewDefaultTableConvertor(schema.GroupResource{
 			Group:    licenseapi.GroupName,
 			Resource: licenseapi.ResourceOfflineLicenses,
@@ -81,52 +104,15 @@ func (r *Storage) Destroy() {}
 
 func (r *Storage) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
 	ns, ok := apirequest.NamespaceFrom(ctx)
-	if !ok {
+	if !ok || ns == "" {
 		return nil, apierrors.NewBadRequest("missing namespace")
 	}
 
-	licenseSecret, err := getLicenseSecret(ctx, r.kc, ns)
+	licenseSecret, lic, err := r.getLicense(ctx, ns, name)
 	if err != nil {
-		return &licenseapi.OfflineLicense{}, err
-	}
-
-	for product, lic := range licenseSecret.Data {
-		if product == name {
-			certs, err := cert.ParseCertsPEM(lic)
-			if err != nil {
-				return nil, err
-			}
-
-			license, err := verifier.ParseLicense(verifier.ParserOptions{
-				ClusterUID: certs[0].Subject.CommonName,
-				CACert:     certs[0],
-				License:    lic,
-			})
-			if err != nil && ignoreCertificateExpiredError(err) != nil {
-				return nil, err
-			}
-
-			return &licenseapi.OfflineLicense{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:              license.PlanName,
-					Namespace:         licenseSecret.Namespace,
-					CreationTimestamp: *license.NotBefore,
-					UID:               types.UID(uuid.Must(uuid.NewUUID()).String()),
-				},
-				Status: licenseapi.OfflineLicenseStatus{
-					License: license,
-					SecretKeyRef: &core.SecretKeySelector{
-						LocalObjectReference: core.LocalObjectReference{
-							Name: licenseSecret.Name,
-						},
-						Key: product,
-					},
-				},
-			}, nil
-		}
+		return nil, err
 	}
-
-	return &licenseapi.OfflineLicense{}, err
+	return toOfflineLicense(licenseSecret, name, lic)
 }
 
 // Lister
@@ -134,64 +120,256 @@ func (r *Storage) NewList() runtime.Object {
 	return &licenseapi.OfflineLicenseList{}
 }
 
+// List returns the licenses in a namespace, or in all namespaces if the request has no namespace.
 func (r *Storage) List(ctx context.Context, options *internalversion.ListOptions) (runtime.Object, error) {
+	ns, _ := apirequest.NamespaceFrom(ctx)
+
+	list, err := listLicenseSecrets(ctx, r.kc, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	pred := predicateOf(options)
+	licenses := make([]licenseapi.OfflineLicense, 0)
+	for i := range list {
+		items, err := licensesOf(&list[i], pred)
+		if err != nil {
+			return nil, err
+		}
+		licenses = append(licenses, items...)
+	}
+	sort.Slice(licenses, func(i, j int) bool {
+		if licenses[i].Namespace != licenses[j].Namespace {
+			return licenses[i].Namespace < licenses[j].Namespace
+		}
+		return licenses[i].Name < licenses[j].Name
+	})
+
+	return &licenseapi.OfflineLicenseList{
+		Items: licenses,
+	}, nil
+}
+
+// Update replaces the license of a product with the license in spec.license.
+func (r *Storage) Update(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo, _ rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc, _ bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
 	ns, ok := apirequest.NamespaceFrom(ctx)
 	if !ok {
-		return nil, apierrors.NewBadRequest("missing namespace")
+		return nil, false, apierrors.NewBadRequest("missing namespace")
+	}
+	if err := r.authorize(ctx, ns); err != nil {
+		return nil, false, err
 	}
 
-	var licenses []licenseapi.OfflineLicense
-	var err error
+	licenseSecret, lic, err := r.getLicense(ctx, ns, name)
+	if err != nil {
+		return nil, false, err
+	}
+	old, err := toOfflineLicense(licenseSecret, name, lic)
+	if err != nil {
+		return nil, false, err
+	}
+	obj, err := objInfo.UpdatedObject(ctx, old)
+	if err != nil {
+		return nil, false, err
+	}
+	in := obj.(*licenseapi.OfflineLicense)
+	if in.Spec.License == "" {
+		return nil, false, apierrors.NewBadRequest("missing spec.license")
+	}
+	productKey, err := addofflinelicense.GetProductKey([]byte(in.Spec.License))
+	if err != nil {
+		return nil, false, apierrors.NewBadRequest(err.Error())
+	}
+	if productKey != name {
+		return nil, false, apierrors.NewBadRequest(fmt.Sprintf("license is for product %q, not %q", productKey, name))
+	}
+	if updateValidation != nil {
+		if err := updateValidation(ctx, in, old); err != nil {
+			return nil, false, err
+		}
+	}
+	out, err := toOfflineLicense(licenseSecret, name, []byte(in.Spec.License))
+	if err != nil {
+		return nil, false, apierrors.NewBadRequest(err.Error())
+	}
+	if isDryRun(options.DryRun) {
+		return out, false, nil
+	}
 
-	list, err := listLicenseSecrets(ctx, r.kc, ns)
-	for _, licenseSecret := range list {
-		for product, lic := range licenseSecret.Data {
-			certs, err := cert.ParseCertsPEM(lic)
-			if err != nil {
-				return nil, err
-			}
-
-			license, err := verifier.ParseLicense(verifier.ParserOptions{
-				ClusterUID: certs[0].Subject.CommonName,
-				CACert:     certs[0],
-				License:    lic,
-			})
-			if err != nil && ignoreCertificateExpiredError(err) != nil {
-				return nil, err
-			}
-
-			licenses = append(licenses, licenseapi.OfflineLicense{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:              license.PlanName,
-					Namespace:         licenseSecret.Namespace,
-					CreationTimestamp: *license.NotBefore,
-					UID:               types.UID(uuid.Must(uuid.NewUUID()).String()),
-				},
-				Status: licenseapi.OfflineLicenseStatus{
-					License: license,
-					SecretKeyRef: &core.SecretKeySelector{
-						LocalObjectReference: core.LocalObjectReference{
-							Name: licenseSecret.Name,
-						},
-						Key: product,
-					},
-				},
-			})
+	_, err = cg.CreateOrPatch(ctx, r.kc, licenseSecret, func(obj client.Object, createOp bool) client.Object {
+		s := obj.(*core.Secret)
+		s.Data[name] = []byte(in.Spec.License)
+		return s
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	addofflinelicense.RestartLicenseProxyServer(ctx, r.kc)
+	return out, false, nil
+}
+
+// Delete removes the license of a product from the license Secret.
+func (r *Storage) Delete(ctx context.Context, name string, deleteValidation rest.ValidateObjectFunc, options *metav1.DeleteOptions) (runtime.Object, bool, error) {
+	ns, ok := apirequest.NamespaceFrom(ctx)
+	if !ok {
+		return nil, false, apierrors.NewBadRequest("missing namespace")
+	}
+	if err := r.authorize(ctx, ns); err != nil {
+		return nil, false, err
+	}
+
+	licenseSecret, lic, err := r.getLicense(ctx, ns, name)
+	if err != nil {
+		return nil, false, err
+	}
+	out, err := toOfflineLicense(licenseSecret, name, lic)
+	if err != nil {
+		return nil, false, err
+	}
+	if deleteValidation != nil {
+		if err := deleteValidation(ctx, out); err != nil {
+			return nil, false, err
 		}
 	}
+	if isDryRun(options.DryRun) {
+		return out, true, nil
+	}
 
-	result := licenseapi.OfflineLicenseList{
-		TypeMeta: metav1.TypeMeta{},
-		Items:    licenses,
+	_, err = cg.CreateOrPatch(ctx, r.kc, licenseSecret, func(obj client.Object, createOp bool) client.Object {
+		s := obj.(*core.Secret)
+		delete(s.Data, name)
+		return s
+	})
+	if err != nil {
+		return nil, false, err
 	}
+	addofflinelicense.RestartLicenseProxyServer(ctx, r.kc)
+	return out, true, nil
+}
 
-	return &result, err
+// authorize checks that the user can modify the license Secret, as the licenses are stored there.
+func (r *Storage) authorize(ctx context.Context, ns string) error {
+	user, ok := apirequest.UserFrom(ctx)
+	if !ok {
+		return apierrors.NewBadRequest("missing user info")
+	}
+	attrs := authorizer.AttributesRecord{
+		User:            user,
+		Verb:            "patch",
+		Namespace:       ns,
+		APIGroup:        secretGR.Group,
+		Resource:        secretGR.Resource,
+		Name:            addofflinelicense.LicenseSecretName,
+		ResourceRequest: true,
+	}
+	decision, why, err := r.a.Authorize(ctx, attrs)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	if decision != authorizer.DecisionAllow {
+		return apierrors.NewForbidden(secretGR, addofflinelicense.LicenseSecretName, errors.New(why))
+	}
+	return nil
+}
+
+func (r *Storage) getLicense(ctx context.Context, ns, name string) (*core.Secret, []byte, error) {
+	var licenseSecret core.Secret
+	err := r.kc.Get(ctx, types.NamespacedName{Name: addofflinelicense.LicenseSecretName, Namespace: ns}, &licenseSecret)
+	if apierrors.IsNotFound(err) {
+		return nil, nil, apierrors.NewNotFound(licenseapi.Resource(licenseapi.ResourceOfflineLicenses), name)
+	} else if err != nil {
+		return nil, nil, err
+	}
+	lic, ok := licenseSecret.Data[name]
+	if !ok {
+		return nil, nil, apierrors.NewNotFound(licenseapi.Resource(licenseapi.ResourceOfflineLicenses), name)
+	}
+	return &licenseSecret, lic, nil
+}
+
+func isDryRun(dryRun []string) bool {
+	return len(dryRun) > 0 && dryRun[0] == metav1.DryRunAll
 }
 
 func (r *Storage) ConvertToTable(ctx context.Context, object runtime.Object, tableOptions runtime.Object) (*metav1.Table, error) {
 	return r.convertor.ConvertToTable(ctx, object, tableOptions)
 }
 
+// toOfflineLicense converts the license of a product in a license Secret. The name of the object is the
+// product key, and the UID is derived from the Secret UID and the product key, so that it is stable.
+func toOfflineLicense(licenseSecret *core.Secret, product string, lic []byte) (*licenseapi.OfflineLicense, error) {
+	certs, err := cert.ParseCertsPEM(lic)
+	if err != nil {
+		return nil, err
+	}
+
+	license, err := verifier.ParseLicense(verifier.ParserOptions{
+		ClusterUID: certs[0].Subject.CommonName,
+		CACert:     certs[0],
+		License:    lic,
+	})
+	if err != nil && ignoreCertificateExpiredError(err) != nil {
+		return nil, err
+	}
+
+	return &licenseapi.OfflineLicense{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              product,
+			Namespace:         licenseSecret.Namespace,
+			Labels:            licenseSecret.Labels,
+			CreationTimestamp: *license.NotBefore,
+			UID:               types.UID(uuid.NewSHA1(uuid.NameSpaceOID, []byte(string(licenseSecret.UID)+"/"+product)).String()),
+			ResourceVersion:   licenseSecret.ResourceVersion,
+		},
+		Status: licenseapi.OfflineLicenseStatus{
+			License: license,
+			SecretKeyRef: &core.SecretKeySelector{
+				LocalObjectReference: core.LocalObjectReference{
+					Name: licenseSecret.Name,
+				},
+				Key: product,
+			},
+		},
+	}, nil
+}
+
+// licensesOf returns the licenses in a license Secret that match the predicate.
+func licensesOf(licenseSecret *core.Secret, pred storage.SelectionPredicate) ([]licenseapi.OfflineLicense, error) {
+	licenses := make([]licenseapi.OfflineLicense, 0, len(licenseSecret.Data))
+	for product, lic := range licenseSecret.Data {
+		license, err := toOfflineLicense(licenseSecret, product, lic)
+		if err != nil {
+			return nil, err
+		}
+		if ok, err := pred.Matches(license); err != nil {
+			return nil, err
+		} else if ok {
+			licenses = append(licenses, *license)
+		}
+	}
+	return licenses, nil
+}
+
+func predicateOf(options *internalversion.ListOptions) storage.SelectionPredicate {
+	pred := storage.SelectionPredicate{
+		Label: labels.Everything(),
+		Field: fields.Everything(),
+		GetAttrs: func(obj runtime.Object) (labels.Set, fields.Set, error) {
+			license := obj.(*licenseapi.OfflineLicense)
+			return license.Labels, generic.ObjectMetaFieldsSet(&license.ObjectMeta, true), nil
+		},
+	}
+	if options != nil {
+		if options.LabelSelector != nil {
+			pred.Label = options.LabelSelector
+		}
+		if options.FieldSelector != nil {
+			pred.Field = options.FieldSelector
+		}
+	}
+	return pred
+}
+
 func ignoreCertificateExpiredError(err error) error {
 	if strings.Contains(err.Error(), "x509: certificate has expired or is not yet valid") {
 		return nil
@@ -199,17 +377,6 @@ func ignoreCertificateExpiredError(err error) error {
 	return err
 }
 
-func getLicenseSecret(ctx context.Context, kc client.Client, ns string) (*core.Secret, error) {
-	var licenseSecret core.Secret
-	err := kc.Get(ctx, types.NamespacedName{Name: addofflinelicense.LicenseSecretName, Namespace: ns}, &licenseSecret)
-	if err != nil && apierrors.IsNotFound(err) {
-		return &core.Secret{}, nil // never return nil
-	} else if err != nil {
-		return &core.Secret{}, err // never return nil
-	}
-	return &licenseSecret, nil
-}
-
 func listLicenseSecrets(ctx context.Context, kc client.Client, ns string) ([]core.Secret, error) {
 	var list core.SecretList
 	err := kc.List(ctx, &list, client.InNamespace(ns))
@@ -0,0 +1,222 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package offlinelicense
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	licenseapi "kubeops.dev/ui-server/apis/offline/v1alpha1"
+	"kubeops.dev/ui-server/pkg/registry/offline/addofflinelicense"
+
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newLicense(t *testing.T, product string, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject: pkix.Name{
+			CommonName:         "cluster-uid",
+			OrganizationalUnit: []string{product},
+			Organization:       []string{"kubedb"},
+		},
+		DNSNames:              []string{"cluster-uid"},
+		NotBefore:             notAfter.AddDate(-1, 0, 0),
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+type allowSecrets bool
+
+func (a allowSecrets) Authorize(_ context.Context, _ authorizer.Attributes) (authorizer.Decision, string, error) {
+	if a {
+		return authorizer.DecisionAllow, "", nil
+	}
+	return authorizer.DecisionDeny, "not allowed", nil
+}
+
+func TestUpdateDelete(t *testing.T) {
+	now := time.Now()
+	enterprise := newLicense(t, "kubedb-enterprise", now.AddDate(0, 1, 0))
+	renewed := newLicense(t, "kubedb-enterprise", now.AddDate(1, 0, 0))
+	expired := newLicense(t, "stash-enterprise", now.AddDate(0, -1, 0))
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	kc := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&core.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: addofflinelicense.LicenseSecretName, Namespace: "kubeops"},
+		Data: map[string][]byte{
+			"kubedb-enterprise": enterprise,
+			"stash-enterprise":  expired,
+		},
+	}).Build()
+
+	ctx := apirequest.WithNamespace(apirequest.WithUser(context.TODO(), &user.DefaultInfo{Name: "admin"}), "kubeops")
+	secretKey := types.NamespacedName{Name: addofflinelicense.LicenseSecretName, Namespace: "kubeops"}
+
+	r := NewStorage(kc, allowSecrets(false), nil)
+	if _, _, err := r.Delete(ctx, "kubedb-enterprise", nil, &metav1.DeleteOptions{}); !apierrors.IsForbidden(err) {
+		t.Errorf("expected forbidden, got %v", err)
+	}
+
+	r = NewStorage(kc, allowSecrets(true), nil)
+	update := func(license []byte) error {
+		_, _, err := r.Update(ctx, "kubedb-enterprise", rest.DefaultUpdatedObjectInfo(&licenseapi.OfflineLicense{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubedb-enterprise", Namespace: "kubeops"},
+			Spec:       licenseapi.OfflineLicenseSpec{License: string(license)},
+		}), nil, nil, false, &metav1.UpdateOptions{})
+		return err
+	}
+	if err := update(expired); !apierrors.IsBadRequest(err) {
+		t.Errorf("expected bad request for the license of another product, got %v", err)
+	}
+	if err := update(renewed); err != nil {
+		t.Fatal(err)
+	}
+	var secret core.Secret
+	if err := kc.Get(ctx, secretKey, &secret); err != nil {
+		t.Fatal(err)
+	}
+	if string(secret.Data["kubedb-enterprise"]) != string(renewed) {
+		t.Error("license was not replaced")
+	}
+
+	if pruned := addofflinelicense.PruneExpired(secret.Data, "", now); len(pruned) != 1 || pruned[0] != "stash-enterprise" {
+		t.Errorf("unexpected pruned licenses %v", pruned)
+	}
+
+	if _, _, err := r.Delete(ctx, "kubedb-enterprise", nil, &metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := kc.Get(ctx, secretKey, &secret); err != nil {
+		t.Fatal(err)
+	}
+	if _, found := secret.Data["kubedb-enterprise"]; found {
+		t.Error("license was not deleted")
+	}
+	if _, _, err := r.Delete(ctx, "kubedb-enterprise", nil, &metav1.DeleteOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+func TestListAndWatchEvents(t *testing.T) {
+	now := time.Now()
+	enterprise := newLicense(t, "kubedb-enterprise", now.AddDate(0, 1, 0))
+	renewed := newLicense(t, "kubedb-enterprise", now.AddDate(1, 0, 0))
+	stash := newLicense(t, "stash-enterprise", now.AddDate(0, 1, 0))
+
+	newSecret := func(ns string, data map[string][]byte) *core.Secret {
+		return &core.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      addofflinelicense.LicenseSecretName,
+				Namespace: ns,
+				UID:       types.UID(ns + "-uid"),
+			},
+			Data: data,
+		}
+	}
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	kc := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newSecret("kubeops", map[string][]byte{"kubedb-enterprise": enterprise, "stash-enterprise": stash}),
+		newSecret("demo", map[string][]byte{"kubedb-enterprise": enterprise}),
+	).Build()
+
+	r := NewStorage(kc, allowSecrets(true), nil)
+	ctx := apirequest.WithUser(context.TODO(), &user.DefaultInfo{Name: "admin"})
+	obj, err := r.List(ctx, &internalversion.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", "kubedb-enterprise"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := obj.(*licenseapi.OfflineLicenseList)
+	if len(list.Items) != 2 || list.Items[0].Namespace != "demo" || list.Items[1].Namespace != "kubeops" {
+		t.Fatalf("unexpected licenses %+v", list.Items)
+	}
+	if list.Items[0].UID == list.Items[1].UID {
+		t.Error("expected licenses in different namespaces to have different UIDs")
+	}
+
+	obj, err = r.Get(apirequest.WithNamespace(ctx, "demo"), "kubedb-enterprise", &metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uid := obj.(*licenseapi.OfflineLicense).UID; uid != list.Items[0].UID {
+		t.Errorf("expected stable UID %s, got %s", list.Items[0].UID, uid)
+	}
+
+	oldSecret := newSecret("kubeops", map[string][]byte{"kubedb-enterprise": enterprise, "stash-enterprise": stash})
+	curSecret := newSecret("kubeops", map[string][]byte{"kubedb-enterprise": renewed})
+	events, err := licenseEvents(oldSecret, curSecret, predicateOf(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]watch.EventType{}
+	for _, e := range events {
+		got[e.Object.(*licenseapi.OfflineLicense).Name] = e.Type
+	}
+	if len(got) != 2 || got["kubedb-enterprise"] != watch.Modified || got["stash-enterprise"] != watch.Deleted {
+		t.Errorf("unexpected events %v", got)
+	}
+
+	events, err = licenseEvents(nil, oldSecret, predicateOf(&internalversion.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", "stash-enterprise"),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Type != watch.Added {
+		t.Errorf("unexpected events %v", events)
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package offlinelicense
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	"kubeops.dev/ui-server/pkg/registry/offline/addofflinelicense"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	"k8s.io/apimachinery/pkg/watch"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/storage"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// watchBufferSize is the number of events buffered for a watcher. The watch is closed
+// if the client falls behind, and the client is expected to watch again.
+const watchBufferSize = 100
+
+// Watch streams the changes to the licenses, using the informer of the license Secrets.
+// The current licenses are sent as Added events when the watch starts.
+func (r *Storage) Watch(ctx context.Context, options *internalversion.ListOptions) (watch.Interface, error) {
+	ns, _ := apirequest.NamespaceFrom(ctx)
+
+	inf, err := r.informers.GetInformer(ctx, &core.Secret{})
+	if err != nil {
+		return nil, err
+	}
+
+	w := &licenseWatcher{
+		ns:     ns,
+		pred:   predicateOf(options),
+		result: make(chan watch.Event, watchBufferSize),
+		done:   make(chan struct{}),
+	}
+	reg, err := inf.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			w.handle(nil, obj)
+		},
+		UpdateFunc: func(oldObj, newObj any) {
+			w.handle(oldObj, newObj)
+		},
+		DeleteFunc: func(obj any) {
+			if d, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = d.Obj
+			}
+			w.handle(obj, nil)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-w.done:
+		}
+		if err := inf.RemoveEventHandler(reg); err != nil {
+			klog.ErrorS(err, "failed to remove OfflineLicense watch handler")
+		}
+		w.close()
+	}()
+	return w, nil
+}
+
+type licenseWatcher struct {
+	ns   string
+	pred storage.SelectionPredicate
+
+	mu     sync.Mutex
+	closed bool
+	result chan watch.Event
+	done   chan struct{}
+	once   sync.Once
+}
+
+var _ watch.Interface = &licenseWatcher{}
+
+func (w *licenseWatcher) ResultChan() <-chan watch.Event {
+	return w.result
+}
+
+func (w *licenseWatcher) Stop() {
+	w.once.Do(func() {
+		close(w.done)
+	})
+}
+
+func (w *licenseWatcher) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	close(w.result)
+}
+
+func (w *licenseWatcher) handle(oldObj, newObj any) {
+	oldSecret := w.licenseSecret(oldObj)
+	newSecret := w.licenseSecret(newObj)
+	if oldSecret == nil && newSecret == nil {
+		return
+	}
+
+	events, err := licenseEvents(oldSecret, newSecret, w.pred)
+	if err != nil {
+		klog.ErrorS(err, "failed to convert license Secret", "namespace", w.ns)
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, e := range events {
+		if w.closed {
+			return
+		}
+		select {
+		case w.result <- e:
+		default:
+			w.Stop()
+			return
+		}
+	}
+}
+
+// licenseSecret returns the license Secret watched by w, or nil for other Secrets.
+func (w *licenseWatcher) licenseSecret(obj any) *core.Secret {
+	s, ok := obj.(*core.Secret)
+	if !ok || s.Name != addofflinelicense.LicenseSecretName {
+		return nil
+	}
+	if w.ns != "" && s.Namespace != w.ns {
+		return nil
+	}
+	return s
+}
+
+// licenseEvents compares the licenses of two versions of a license Secret. Either of them may be nil.
+func licenseEvents(oldSecret, newSecret *core.Secret, pred storage.SelectionPredicate) ([]watch.Event, error) {
+	var oldData, newData map[string][]byte
+	if oldSecret != nil {
+		oldData = oldSecret.Data
+	}
+	if newSecret != nil {
+		newData = newSecret.Data
+	}
+
+	var events []watch.Event
+	emit := func(t watch.EventType, s *core.Secret, product string, lic []byte) error {
+		license, err := toOfflineLicense(s, product, lic)
+		if err != nil {
+			return err
+		}
+		if ok, err := pred.Matches(license); err != nil || !ok {
+			return err
+		}
+		events = append(events, watch.Event{Type: t, Object: license})
+		return nil
+	}
+	for product, lic := range newData {
+		old, found := oldData[product]
+		switch {
+		case !found:
+			if err := emit(watch.Added, newSecret, product, lic); err != nil {
+				return nil, err
+			}
+		case !bytes.Equal(old, lic):
+			if err := emit(watch.Modified, newSecret, product, lic); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for product, lic := range oldData {
+		if _, found := newData[product]; !found {
+			if err := emit(watch.Deleted, oldSecret, product, lic); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return events, nil
+}
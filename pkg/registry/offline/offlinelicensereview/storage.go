@@ -0,0 +1,92 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package offlinelicensereview
+
+import (
+	"context"
+	"crypto/x509"
+	"strings"
+	"time"
+
+	licenseapi "kubeops.dev/ui-server/apis/offline/v1alpha1"
+	"kubeops.dev/ui-server/pkg/registry/offline/addofflinelicense"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+type Storage struct {
+	clusterUID string
+	loadCA     func() (*x509.Certificate, error)
+}
+
+var (
+	_ rest.GroupVersionKindProvider = &Storage{}
+	_ rest.Scoper                   = &Storage{}
+	_ rest.Storage                  = &Storage{}
+	_ rest.Creater                  = &Storage{}
+	_ rest.SingularNameProvider     = &Storage{}
+)
+
+func NewStorage(clusterUID string) *Storage {
+	return &Storage{
+		clusterUID: clusterUID,
+		loadCA:     addofflinelicense.LicenseCA,
+	}
+}
+
+func (r *Storage) GroupVersionKind(_ schema.GroupVersion) schema.GroupVersionKind {
+	return licenseapi.SchemeGroupVersion.WithKind(licenseapi.ResourceKindOfflineLicenseReview)
+}
+
+func (r *Storage) NamespaceScoped() bool {
+	return false
+}
+
+func (r *Storage) GetSingularName() string {
+	return strings.ToLower(licenseapi.ResourceKindOfflineLicenseReview)
+}
+
+func (r *Storage) New() runtime.Object {
+	return &licenseapi.OfflineLicenseReview{}
+}
+
+func (r *Storage) Destroy() {}
+
+func (r *Storage) Create(ctx context.Context, obj runtime.Object, _ rest.ValidateObjectFunc, _ *metav1.CreateOptions) (runtime.Object, error) {
+	in := obj.(*licenseapi.OfflineLicenseReview)
+	if in.Request == nil {
+		return nil, apierrors.NewBadRequest("missing apirequest")
+	}
+	if _, ok := apirequest.UserFrom(ctx); !ok {
+		return nil, apierrors.NewBadRequest("missing user info")
+	}
+	if in.Request.License == "" {
+		return nil, apierrors.NewBadRequest("missing license info")
+	}
+
+	ca, err := r.loadCA()
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	in.Response = addofflinelicense.Review(in.Request, r.clusterUID, ca, time.Now())
+	return in, nil
+}
@@ -0,0 +1,188 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package check
+
+import (
+	"encoding/json"
+	"strings"
+
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Match mirrors the spec.match field of Gatekeeper constraints.
+// ref: https://open-policy-agent.github.io/gatekeeper/website/docs/howto#the-match-field
+type Match struct {
+	Kinds              []Kinds               `json:"kinds,omitempty"`
+	Scope              string                `json:"scope,omitempty"`
+	Namespaces         []string              `json:"namespaces,omitempty"`
+	ExcludedNamespaces []string              `json:"excludedNamespaces,omitempty"`
+	LabelSelector      *metav1.LabelSelector `json:"labelSelector,omitempty"`
+	NamespaceSelector  *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	Name               string                `json:"name,omitempty"`
+}
+
+type Kinds struct {
+	APIGroups []string `json:"apiGroups,omitempty"`
+	Kinds     []string `json:"kinds,omitempty"`
+}
+
+// Target is the object being checked against the match criteria.
+type Target struct {
+	GVK        schema.GroupVersionKind
+	Name       string
+	Namespace  string
+	Namespaced bool
+	Labels     map[string]string
+	// NamespaceLabels are the labels of the object's namespace, or of the object itself when it is a Namespace.
+	NamespaceLabels map[string]string
+}
+
+func GetMatchOfConstraint(constraint unstructured.Unstructured) (*Match, error) {
+	m, _, err := unstructured.NestedMap(constraint.UnstructuredContent(), "spec", "match")
+	if err != nil {
+		return nil, err
+	}
+	jsonBytes, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	var match Match
+	if err := json.Unmarshal(jsonBytes, &match); err != nil {
+		return nil, err
+	}
+	return &match, nil
+}
+
+// Matches reports whether a constraint with the match criteria applies to the target.
+// As in Gatekeeper, namespace based criteria are ignored for cluster scoped objects
+// other than Namespaces.
+func (m *Match) Matches(t Target) (bool, error) {
+	if !m.kindsMatch(t.GVK) || !m.scopeMatch(t) || !globMatch(m.Name, t.Name, true) {
+		return false, nil
+	}
+
+	if ns, ok := t.effectiveNamespace(); ok {
+		if len(m.Namespaces) > 0 && !anyGlobMatch(m.Namespaces, ns) {
+			return false, nil
+		}
+		if anyGlobMatch(m.ExcludedNamespaces, ns) {
+			return false, nil
+		}
+		if m.NamespaceSelector != nil {
+			sel, err := metav1.LabelSelectorAsSelector(m.NamespaceSelector)
+			if err != nil {
+				return false, err
+			}
+			if !sel.Matches(labels.Set(t.NamespaceLabels)) {
+				return false, nil
+			}
+		}
+	}
+
+	if m.LabelSelector != nil {
+		sel, err := metav1.LabelSelectorAsSelector(m.LabelSelector)
+		if err != nil {
+			return false, err
+		}
+		if !sel.Matches(labels.Set(t.Labels)) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (m *Match) kindsMatch(gvk schema.GroupVersionKind) bool {
+	if len(m.Kinds) == 0 {
+		return true
+	}
+	for _, kk := range m.Kinds {
+		if (len(kk.APIGroups) == 0 || contains(kk.APIGroups, gvk.Group)) &&
+			(len(kk.Kinds) == 0 || contains(kk.Kinds, gvk.Kind)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Match) scopeMatch(t Target) bool {
+	switch m.Scope {
+	case "Cluster":
+		return !t.Namespaced
+	case "Namespaced":
+		return t.Namespaced
+	default:
+		return true
+	}
+}
+
+func (t Target) effectiveNamespace() (string, bool) {
+	if t.GVK.Group == "" && t.GVK.Kind == "Namespace" {
+		return t.Name, true
+	}
+	return t.Namespace, t.Namespaced
+}
+
+// NamespaceLabels returns the labels used to evaluate namespaceSelector for objects
+// in namespace ns. ns may not exist yet, when it is created along with the object.
+func NamespaceLabels(ns string, obj *core.Namespace) map[string]string {
+	lbls := map[string]string{}
+	if obj != nil {
+		for k, v := range obj.Labels {
+			lbls[k] = v
+		}
+	}
+	lbls[core.LabelMetadataName] = ns
+	return lbls
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == "*" || e == s {
+			return true
+		}
+	}
+	return false
+}
+
+func anyGlobMatch(patterns []string, s string) bool {
+	for _, p := range patterns {
+		if globMatch(p, s, false) {
+			return true
+		}
+	}
+	return false
+}
+
+// globMatch supports the prefix (kube-*) and suffix (*-system) globs accepted by Gatekeeper.
+func globMatch(pattern, s string, emptyMatchesAll bool) bool {
+	switch {
+	case pattern == "":
+		return emptyMatchesAll
+	case pattern == "*":
+		return true
+	case strings.HasSuffix(pattern, "*"):
+		return strings.HasPrefix(s, strings.TrimSuffix(pattern, "*"))
+	case strings.HasPrefix(pattern, "*"):
+		return strings.HasSuffix(s, strings.TrimPrefix(pattern, "*"))
+	default:
+		return pattern == s
+	}
+}
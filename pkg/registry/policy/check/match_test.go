@@ -0,0 +1,139 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package check
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestMatch_Matches(t *testing.T) {
+	deploy := Target{
+		GVK:             schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+		Name:            "web",
+		Namespace:       "demo",
+		Namespaced:      true,
+		Labels:          map[string]string{"app": "web"},
+		NamespaceLabels: NamespaceLabels("demo", nil),
+	}
+	clusterRole := Target{
+		GVK:  schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"},
+		Name: "admin",
+	}
+	ns := Target{
+		GVK:             schema.GroupVersionKind{Version: "v1", Kind: "Namespace"},
+		Name:            "kube-system",
+		NamespaceLabels: NamespaceLabels("kube-system", nil),
+	}
+
+	tests := []struct {
+		name   string
+		match  Match
+		target Target
+		want   bool
+	}{
+		{
+			name:   "Empty match",
+			match:  Match{},
+			target: deploy,
+			want:   true,
+		},
+		{
+			name:   "Kind matches",
+			match:  Match{Kinds: []Kinds{{APIGroups: []string{"apps"}, Kinds: []string{"Deployment"}}}},
+			target: deploy,
+			want:   true,
+		},
+		{
+			name:   "Wildcard kind",
+			match:  Match{Kinds: []Kinds{{APIGroups: []string{"*"}, Kinds: []string{"*"}}}},
+			target: deploy,
+			want:   true,
+		},
+		{
+			name:   "Kind does not match",
+			match:  Match{Kinds: []Kinds{{APIGroups: []string{""}, Kinds: []string{"Pod"}}}},
+			target: deploy,
+			want:   false,
+		},
+		{
+			name:   "Cluster scope",
+			match:  Match{Scope: "Cluster"},
+			target: deploy,
+			want:   false,
+		},
+		{
+			name:   "Namespace prefix glob",
+			match:  Match{Namespaces: []string{"de*"}},
+			target: deploy,
+			want:   true,
+		},
+		{
+			name:   "Excluded namespace",
+			match:  Match{ExcludedNamespaces: []string{"demo"}},
+			target: deploy,
+			want:   false,
+		},
+		{
+			name:   "Namespaces ignored for cluster scoped objects",
+			match:  Match{Namespaces: []string{"demo"}},
+			target: clusterRole,
+			want:   true,
+		},
+		{
+			name:   "Namespace object matched by its own name",
+			match:  Match{ExcludedNamespaces: []string{"kube-*"}},
+			target: ns,
+			want:   false,
+		},
+		{
+			name: "Label selector",
+			match: Match{LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "db"},
+			}},
+			target: deploy,
+			want:   false,
+		},
+		{
+			name: "Namespace selector on metadata name",
+			match: Match{NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"kubernetes.io/metadata.name": "demo"},
+			}},
+			target: deploy,
+			want:   true,
+		},
+		{
+			name:   "Name suffix glob",
+			match:  Match{Name: "*eb"},
+			target: deploy,
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.match.Matches(tt.target)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
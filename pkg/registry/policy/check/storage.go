@@ -0,0 +1,244 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package check
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	policyapi "kubeops.dev/ui-server/apis/policy/v1alpha1"
+	policystorage "kubeops.dev/ui-server/pkg/registry/policy/reports"
+
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	enforcementActionScoped = "scoped"
+	enforcementPointWebhook = "validation.gatekeeper.sh"
+)
+
+type Storage struct {
+	kc client.Client
+}
+
+var (
+	_ rest.GroupVersionKindProvider = &Storage{}
+	_ rest.Scoper                   = &Storage{}
+	_ rest.Storage                  = &Storage{}
+	_ rest.Creater                  = &Storage{}
+	_ rest.SingularNameProvider     = &Storage{}
+)
+
+func NewStorage(kc client.Client) *Storage {
+	return &Storage{
+		kc: kc,
+	}
+}
+
+func (r *Storage) GroupVersionKind(_ schema.GroupVersion) schema.GroupVersionKind {
+	return policyapi.SchemeGroupVersion.WithKind(policyapi.ResourceKindPolicyCheck)
+}
+
+func (r *Storage) NamespaceScoped() bool {
+	return false
+}
+
+func (r *Storage) GetSingularName() string {
+	return strings.ToLower(policyapi.ResourceKindPolicyCheck)
+}
+
+func (r *Storage) New() runtime.Object {
+	return &policyapi.PolicyCheck{}
+}
+
+func (r *Storage) Destroy() {}
+
+func (r *Storage) Create(ctx context.Context, obj runtime.Object, _ rest.ValidateObjectFunc, _ *metav1.CreateOptions) (runtime.Object, error) {
+	in := obj.(*policyapi.PolicyCheck)
+	if in.Request == nil || in.Request.Resource == nil {
+		return nil, apierrors.NewBadRequest("missing apirequest")
+	}
+
+	var u unstructured.Unstructured
+	err := json.Unmarshal(in.Request.Resource.Raw, &u)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+
+	target, err := r.toTarget(ctx, &u)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp policyapi.PolicyCheckResponse
+	templates, err := policystorage.ListTemplates(ctx, r.kc)
+	if err != nil {
+		return nil, err
+	}
+	for _, template := range templates.Items {
+		constraintKind, _, err := unstructured.NestedString(template.UnstructuredContent(), "spec", "crd", "spec", "names", "kind")
+		if err != nil {
+			return nil, err
+		}
+		constraints, err := policystorage.ListConstraints(ctx, r.kc, constraintKind)
+		if err != nil {
+			return nil, err
+		}
+		for _, constraint := range constraints.Items {
+			match, err := GetMatchOfConstraint(constraint)
+			if err != nil {
+				return nil, err
+			}
+			if ok, err := match.Matches(*target); err != nil {
+				return nil, apierrors.NewInternalError(err)
+			} else if !ok {
+				continue
+			}
+
+			resource, err := policystorage.GetResourceFQNOfConstraint(constraint)
+			if err != nil {
+				return nil, err
+			}
+			action, actions, err := getEnforcementActions(constraint)
+			if err != nil {
+				return nil, err
+			}
+			violations, err := policystorage.GetViolationsOfConstraint(constraint)
+			if err != nil {
+				return nil, err
+			}
+
+			resp.Constraints = append(resp.Constraints, policyapi.ApplicableConstraint{
+				Name:               constraint.GetName(),
+				GVR:                resource,
+				EnforcementAction:  action,
+				EnforcementActions: actions,
+				Violations:         violationsOfTarget(violations, *target),
+			})
+		}
+	}
+
+	sort.Slice(resp.Constraints, func(i, j int) bool {
+		return resp.Constraints[i].Name < resp.Constraints[j].Name
+	})
+	in.Response = &resp
+	return in, nil
+}
+
+func (r *Storage) toTarget(ctx context.Context, u *unstructured.Unstructured) (*Target, error) {
+	gvk := u.GroupVersionKind()
+	if gvk.Kind == "" {
+		return nil, apierrors.NewBadRequest("missing kind in resource")
+	}
+	var versions []string
+	if gvk.Version != "" {
+		versions = append(versions, gvk.Version)
+	}
+	mapping, err := r.kc.RESTMapper().RESTMapping(gvk.GroupKind(), versions...)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(err.Error())
+	}
+
+	t := Target{
+		GVK:        gvk,
+		Name:       u.GetName(),
+		Namespaced: mapping.Scope.Name() == meta.RESTScopeNameNamespace,
+		Labels:     u.GetLabels(),
+	}
+	if t.Namespaced {
+		ns := u.GetNamespace()
+		if ns == "" {
+			ns = core.NamespaceDefault
+		}
+		t.Namespace = ns
+
+		var nsObj core.Namespace
+		err := r.kc.Get(ctx, client.ObjectKey{Name: ns}, &nsObj)
+		if apierrors.IsNotFound(err) {
+			t.NamespaceLabels = NamespaceLabels(ns, nil)
+		} else if err != nil {
+			return nil, err
+		} else {
+			t.NamespaceLabels = NamespaceLabels(ns, &nsObj)
+		}
+	} else if gvk.Group == "" && gvk.Kind == "Namespace" {
+		t.NamespaceLabels = NamespaceLabels(t.Name, &core.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Labels: u.GetLabels()},
+		})
+	}
+	return &t, nil
+}
+
+// getEnforcementActions returns spec.enforcementAction and, for scoped
+// enforcement, the actions applied by the admission webhook.
+func getEnforcementActions(constraint unstructured.Unstructured) (string, []string, error) {
+	action, found, err := unstructured.NestedString(constraint.UnstructuredContent(), "spec", "enforcementAction")
+	if err != nil {
+		return "", nil, err
+	}
+	if !found || action == "" {
+		action = "deny"
+	}
+	if action != enforcementActionScoped {
+		return action, nil, nil
+	}
+
+	scoped, _, err := unstructured.NestedSlice(constraint.UnstructuredContent(), "spec", "scopedEnforcementActions")
+	if err != nil {
+		return "", nil, err
+	}
+	var actions []string
+	for _, sa := range scoped {
+		m, ok := sa.(map[string]any)
+		if !ok {
+			continue
+		}
+		a, _, _ := unstructured.NestedString(m, "action")
+		points, _, _ := unstructured.NestedSlice(m, "enforcementPoints")
+		for _, p := range points {
+			pm, ok := p.(map[string]any)
+			if !ok {
+				continue
+			}
+			if name, _, _ := unstructured.NestedString(pm, "name"); name == "*" || name == enforcementPointWebhook {
+				actions = append(actions, a)
+				break
+			}
+		}
+	}
+	return action, actions, nil
+}
+
+func violationsOfTarget(violations []policyapi.StatusViolation, t Target) []policyapi.StatusViolation {
+	var result []policyapi.StatusViolation
+	for _, v := range violations {
+		if v.Group == t.GVK.Group && v.Kind == t.GVK.Kind && v.Namespace == t.Namespace && v.Name == t.Name {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package reports
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -25,10 +26,12 @@ import (
 	"time"
 
 	policyapi "kubeops.dev/ui-server/apis/policy/v1alpha1"
+	"kubeops.dev/ui-server/pkg/exporter"
 	"kubeops.dev/ui-server/pkg/graph"
 	"kubeops.dev/ui-server/pkg/shared"
 
 	"gomodules.xyz/sets"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -78,24 +81,16 @@ func (r *Storage) Destroy() {}
 func (r *Storage) Create(ctx context.Context, obj runtime.Object, _ rest.ValidateObjectFunc, _ *metav1.CreateOptions) (runtime.Object, error) {
 	in := obj.(*policyapi.PolicyReport)
 
-	var (
-		scp           scopeDetails
-		resourceGraph *v1alpha1.ResourceGraphResponse
-		err           error
-	)
-	if in.Request == nil || shared.IsClusterRequest(&in.Request.ObjectInfo) {
-		scp.isCluster = true
-	} else if shared.IsNamespaceRequest(&in.Request.ObjectInfo) {
-		scp.isNamespace = true
-		scp.namespace = in.Request.Ref.Name
-	} else {
-		resourceGraph, err = getResourceGraph(r.kc, in.Request.ObjectInfo)
-		if err != nil {
-			return nil, err
-		}
+	var oi *kmapi.ObjectInfo
+	if in.Request != nil {
+		oi = &in.Request.ObjectInfo
+	}
+	scp, err := NewScope(r.kc, oi)
+	if err != nil {
+		return nil, err
 	}
 
-	resp, err := r.locateResource(ctx, resourceGraph, scp)
+	resp, err := r.locateResource(ctx, scp)
 	if err != nil {
 		return nil, err
 	}
@@ -106,16 +101,67 @@ func (r *Storage) Create(ctx context.Context, obj runtime.Object, _ rest.Validat
 	sort.Slice(resp.Constraints, func(i, j int) bool {
 		return in.Response.Constraints[i].Name < in.Response.Constraints[j].Name
 	})
+
+	if in.Request != nil && in.Request.ExportFormat != "" {
+		format := exporter.Format(in.Request.ExportFormat)
+		if !format.Valid() {
+			return nil, apierrors.NewBadRequest(fmt.Sprintf("unsupported export format %q", in.Request.ExportFormat))
+		}
+
+		buf := shared.BufferPool.Get().(*bytes.Buffer)
+		defer shared.BufferPool.Put(buf)
+		buf.Reset()
+
+		rpt := exporter.FromPolicyReport(resp, exporter.Scope(oi), time.Now())
+		if err := exporter.Render(buf, format, rpt); err != nil {
+			return nil, apierrors.NewInternalError(err)
+		}
+		resp.Export = &policyapi.ExportedReport{
+			Format:      in.Request.ExportFormat,
+			ContentType: format.ContentType(),
+			Data:        buf.String(),
+		}
+	}
 	return in, nil
 }
 
-type scopeDetails struct {
-	isCluster   bool
-	isNamespace bool
-	namespace   string
+// Scope selects the violations that belong to a cluster, a namespace or
+// the resource graph of an object.
+type Scope struct {
+	isCluster     bool
+	isNamespace   bool
+	namespace     string
+	resourceGraph *v1alpha1.ResourceGraphResponse
+}
+
+func NewScope(kc client.Client, oi *kmapi.ObjectInfo) (*Scope, error) {
+	var scp Scope
+	if shared.IsClusterRequest(oi) {
+		scp.isCluster = true
+	} else if shared.IsNamespaceRequest(oi) {
+		scp.isNamespace = true
+		scp.namespace = oi.Ref.Name
+	} else {
+		resourceGraph, err := getResourceGraph(kc, *oi)
+		if err != nil {
+			return nil, err
+		}
+		scp.resourceGraph = resourceGraph
+	}
+	return &scp, nil
+}
+
+// IsCluster reports whether the scope is the whole cluster.
+func (scp *Scope) IsCluster() bool {
+	return scp.isCluster
+}
+
+// Filter returns the violations that fall inside the scope.
+func (scp *Scope) Filter(violations []policyapi.StatusViolation) []policyapi.StatusViolation {
+	return evaluateForSingleConstraint(scp.resourceGraph, violations, scp)
 }
 
-func (r *Storage) locateResource(ctx context.Context, resourceGraph *v1alpha1.ResourceGraphResponse, scp scopeDetails) (*policyapi.PolicyReportResponse, error) {
+func (r *Storage) locateResource(ctx context.Context, scp *Scope) (*policyapi.PolicyReportResponse, error) {
 	var resp policyapi.PolicyReportResponse
 	templates, err := ListTemplates(ctx, r.kc)
 	if err != nil {
@@ -154,7 +200,7 @@ func (r *Storage) locateResource(ctx context.Context, resourceGraph *v1alpha1.Re
 				AuditTimestamp: metav1.Time{Time: auditTime},
 				Name:           constraintName,
 				GVR:            resource,
-				Violations:     evaluateForSingleConstraint(resourceGraph, violations, scp),
+				Violations:     scp.Filter(violations),
 			}
 			if len(c.Violations) > 0 {
 				resp.Constraints = append(resp.Constraints, c)
@@ -192,7 +238,7 @@ func getResourceGraph(kc client.Client, oi kmapi.ObjectInfo) (*v1alpha1.Resource
 	})
 }
 
-func ListTemplates(ctx context.Context, kc client.Client) (unstructured.UnstructuredList, error) {
+func ListTemplates(ctx context.Context, kc client.Reader) (unstructured.UnstructuredList, error) {
 	var templates unstructured.UnstructuredList
 	templates.SetGroupVersionKind(schema.GroupVersionKind{
 		Group:   "templates.gatekeeper.sh",
@@ -203,7 +249,7 @@ func ListTemplates(ctx context.Context, kc client.Client) (unstructured.Unstruct
 	return templates, err
 }
 
-func ListConstraints(ctx context.Context, kc client.Client, kind string) (unstructured.UnstructuredList, error) {
+func ListConstraints(ctx context.Context, kc client.Reader, kind string) (unstructured.UnstructuredList, error) {
 	var constraints unstructured.UnstructuredList
 	constraints.SetGroupVersionKind(schema.GroupVersionKind{
 		Group:   "constraints.gatekeeper.sh",
@@ -290,7 +336,7 @@ c = number if resourceGraph.response.connections
 So, overall n * lg^2(n) complexity for a single constraint
 */
 
-func evaluateForSingleConstraint(gr *v1alpha1.ResourceGraphResponse, violations []policyapi.StatusViolation, scp scopeDetails) []policyapi.StatusViolation {
+func evaluateForSingleConstraint(gr *v1alpha1.ResourceGraphResponse, violations []policyapi.StatusViolation, scp *Scope) []policyapi.StatusViolation {
 	if scp.isCluster {
 		return violations
 	} else if scp.isNamespace {
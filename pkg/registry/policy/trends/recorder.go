@@ -0,0 +1,324 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trends
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	policyapi "kubeops.dev/ui-server/apis/policy/v1alpha1"
+	"kubeops.dev/ui-server/pkg/graph"
+	policystorage "kubeops.dev/ui-server/pkg/registry/policy/reports"
+
+	"github.com/pkg/errors"
+	"gomodules.xyz/sets"
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	cu "kmodules.xyz/client-go/client"
+	"kmodules.xyz/client-go/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	keyTrend = "trend"
+
+	// Gatekeeper audits every 60s by default, so consecutive audit runs with
+	// the same violations are merged into one entry and only flushed to the
+	// ConfigMap once in a while.
+	recordInterval = 1 * time.Minute
+	flushInterval  = 15 * time.Minute
+
+	// maxAuditRuns bounds the entries kept per constraint. Each constraint
+	// lists at most 20 violations by default, which keeps the ConfigMap
+	// well below its size limit.
+	maxAuditRuns = 200
+)
+
+// auditRun is a set of violations observed unchanged by consecutive audit runs
+// between First and Last. Violations holds the violations listed by Gatekeeper, which
+// are capped, while TotalViolations is the total it reported.
+type auditRun struct {
+	First           metav1.Time    `json:"first"`
+	Last            metav1.Time    `json:"last"`
+	TotalViolations int            `json:"totalViolations,omitempty"`
+	Violations      []violationKey `json:"violations,omitempty"`
+}
+
+type violationKey struct {
+	Group     string `json:"group,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+type trendRecord struct {
+	Constraint string                      `json:"constraint"`
+	GVR        schema.GroupVersionResource `json:"gvr"`
+	Runs       []auditRun                  `json:"runs"`
+}
+
+// configmapName returns the name of the ConfigMap holding the trend of a constraint.
+// Names that don't fit in a ConfigMap name are truncated and suffixed with a hash of
+// the full name.
+func configmapName(gvr schema.GroupVersionResource, constraint string) string {
+	name := fmt.Sprintf("%s.%s.%s", policyapi.ResourcePolicyTrend, gvr.Resource, constraint)
+	if len(name) <= validation.DNS1123SubdomainMaxLength {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	suffix := hex.EncodeToString(sum[:])[:16]
+	prefix := strings.TrimRight(name[:validation.DNS1123SubdomainMaxLength-len(suffix)-1], ".-")
+	return prefix + "-" + suffix
+}
+
+func trendLabels() map[string]string {
+	return map[string]string{
+		"k8s.io/group": policyapi.GroupName,
+		"k8s.io/kind":  policyapi.ResourceKindPolicyTrend,
+	}
+}
+
+func extractRecord(cm *core.ConfigMap) (*trendRecord, error) {
+	data, ok := cm.Data[keyTrend]
+	if !ok {
+		return nil, apierrors.NewInternalError(fmt.Errorf("ConfigMap %s/%s does not name data[%q]", cm.Namespace, cm.Name, keyTrend))
+	}
+	var rec trendRecord
+	if err := yaml.Unmarshal([]byte(data), &rec); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func listRecords(ctx context.Context, kc client.Client, ns string) (map[string]*trendRecord, error) {
+	var list core.ConfigMapList
+	err := kc.List(ctx, &list, client.InNamespace(ns), client.MatchingLabels(trendLabels()))
+	if err != nil {
+		return nil, err
+	}
+	records := make(map[string]*trendRecord, len(list.Items))
+	for i := range list.Items {
+		rec, err := extractRecord(&list.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		records[list.Items[i].Name] = rec
+	}
+	return records, nil
+}
+
+// StartRecorder periodically appends the latest Gatekeeper audit results of
+// every constraint to a ConfigMap backed ring in namespace ns.
+func StartRecorder(kc client.Client, ns string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return wait.PollUntilContextCancel(ctx, recordInterval, true, func(ctx context.Context) (done bool, err error) {
+			if !graph.OPAInstalled.Load() {
+				return false, nil
+			}
+			if err := record(ctx, kc, ns); err != nil {
+				klog.ErrorS(err, "failed to record policy violation trends")
+			}
+			return false, nil
+		})
+	}
+}
+
+func record(ctx context.Context, kc client.Client, ns string) error {
+	records, err := listRecords(ctx, kc, ns)
+	if err != nil {
+		return err
+	}
+
+	templates, err := policystorage.ListTemplates(ctx, kc)
+	if err != nil {
+		return err
+	}
+
+	// The errors are collected, so that a failing constraint doesn't stop the others
+	// from being recorded. The history of deleted constraints is only collected if every constraint
+	// was listed, so that a failed list doesn't drop the history of the ones it missed.
+	var errs []error
+	seen := sets.NewString()
+	listed := true
+	for _, template := range templates.Items {
+		constraintKind, _, err := unstructured.NestedString(template.UnstructuredContent(), "spec", "crd", "spec", "names", "kind")
+		if err != nil {
+			errs = append(errs, err)
+			listed = false
+			continue
+		}
+		constraints, err := policystorage.ListConstraints(ctx, kc, constraintKind)
+		if err != nil {
+			errs = append(errs, err)
+			listed = false
+			continue
+		}
+		for _, constraint := range constraints.Items {
+			gvr, err := policystorage.GetResourceFQNOfConstraint(constraint)
+			if err != nil {
+				errs = append(errs, err)
+				listed = false
+				continue
+			}
+			cmName := configmapName(gvr, constraint.GetName())
+			seen.Insert(cmName)
+
+			if err := recordConstraint(ctx, kc, ns, cmName, gvr, constraint, records[cmName]); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	// garbage collect the history of deleted constraints
+	for cmName := range records {
+		if !listed || seen.Has(cmName) {
+			continue
+		}
+		var cm core.ConfigMap
+		cm.Namespace = ns
+		cm.Name = cmName
+		if err := kc.Delete(ctx, &cm); client.IgnoreNotFound(err) != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// recordConstraint appends the last audit run of a constraint to its record, rec, which
+// is nil if the constraint has no history yet.
+func recordConstraint(ctx context.Context, kc client.Client, ns, cmName string, gvr schema.GroupVersionResource, constraint unstructured.Unstructured, rec *trendRecord) error {
+	if _, found, _ := unstructured.NestedString(constraint.UnstructuredContent(), "status", "auditTimestamp"); !found {
+		return nil // not audited yet
+	}
+	auditTime, err := policystorage.GetAuditTimeOfConstraint(constraint)
+	if err != nil {
+		return err
+	}
+	violations, err := policystorage.GetViolationsOfConstraint(constraint)
+	if err != nil {
+		return err
+	}
+	total, _, err := unstructured.NestedInt64(constraint.UnstructuredContent(), "status", "totalViolations")
+	if err != nil {
+		return err
+	}
+
+	if rec == nil {
+		rec = &trendRecord{
+			Constraint: constraint.GetName(),
+			GVR:        gvr,
+		}
+	}
+	if !appendAuditRun(rec, auditRun{
+		First:           metav1.NewTime(auditTime),
+		Last:            metav1.NewTime(auditTime),
+		TotalViolations: int(total),
+		Violations:      toViolationKeys(violations),
+	}) {
+		return nil
+	}
+	return writeRecord(ctx, kc, ns, cmName, rec)
+}
+
+// appendAuditRun adds an audit run to the record and reports whether the
+// record needs to be persisted.
+func appendAuditRun(rec *trendRecord, run auditRun) bool {
+	if n := len(rec.Runs); n > 0 {
+		last := &rec.Runs[n-1]
+		if !run.Last.After(last.Last.Time) {
+			return false // same audit run seen before
+		}
+		if last.TotalViolations == run.TotalViolations && slices.Equal(last.Violations, run.Violations) {
+			if run.Last.Sub(last.Last.Time) < flushInterval {
+				return false
+			}
+			last.Last = run.Last
+			return true
+		}
+	}
+
+	rec.Runs = append(rec.Runs, run)
+	if len(rec.Runs) > maxAuditRuns {
+		rec.Runs = slices.Delete(rec.Runs, 0, len(rec.Runs)-maxAuditRuns)
+	}
+	return true
+}
+
+func toViolationKeys(violations []policyapi.StatusViolation) []violationKey {
+	keys := make([]violationKey, 0, len(violations))
+	for _, v := range violations {
+		keys = append(keys, violationKey{
+			Group:     v.Group,
+			Version:   v.Version,
+			Kind:      v.Kind,
+			Namespace: v.Namespace,
+			Name:      v.Name,
+		})
+	}
+	slices.SortFunc(keys, func(a, b violationKey) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return keys
+}
+
+func (k violationKey) String() string {
+	return fmt.Sprintf("%s/%s/%s/%s/%s", k.Group, k.Version, k.Kind, k.Namespace, k.Name)
+}
+
+func (k violationKey) toStatusViolation() policyapi.StatusViolation {
+	return policyapi.StatusViolation{
+		Group:     k.Group,
+		Version:   k.Version,
+		Kind:      k.Kind,
+		Namespace: k.Namespace,
+		Name:      k.Name,
+	}
+}
+
+func writeRecord(ctx context.Context, kc client.Client, ns, cmName string, rec *trendRecord) error {
+	data, err := yaml.Marshal(rec)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal trend of constraint %s into yaml", rec.Constraint)
+	}
+
+	var cm core.ConfigMap
+	cm.Namespace = ns
+	cm.Name = cmName
+	_, err = cu.CreateOrPatch(ctx, kc, &cm, func(obj client.Object, createOp bool) client.Object {
+		in := obj.(*core.ConfigMap)
+		in.Labels = meta.OverwriteKeys(in.Labels, trendLabels())
+		in.Data = map[string]string{
+			keyTrend: string(data),
+		}
+		return in
+	})
+	return err
+}
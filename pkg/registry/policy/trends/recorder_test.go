@@ -0,0 +1,208 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trends
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	policyapi "kubeops.dev/ui-server/apis/policy/v1alpha1"
+	policystorage "kubeops.dev/ui-server/pkg/registry/policy/reports"
+
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	kmapi "kmodules.xyz/client-go/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var t0 = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func at(d time.Duration) metav1.Time {
+	return metav1.NewTime(t0.Add(d))
+}
+
+func run(d time.Duration, total int, keys ...violationKey) auditRun {
+	return auditRun{First: at(d), Last: at(d), TotalViolations: total, Violations: keys}
+}
+
+func key(ns, name string) violationKey {
+	return violationKey{Version: "v1", Kind: "Pod", Namespace: ns, Name: name}
+}
+
+func TestAppendAuditRun(t *testing.T) {
+	rec := &trendRecord{}
+	if !appendAuditRun(rec, run(0, 1, key("a", "x"))) {
+		t.Fatal("expected the first run to be persisted")
+	}
+	if appendAuditRun(rec, run(0, 1, key("a", "x"))) {
+		t.Error("expected a run seen before to be ignored")
+	}
+	if appendAuditRun(rec, run(time.Minute, 1, key("a", "x"))) {
+		t.Error("expected an unchanged run to be merged without persisting")
+	}
+	if !appendAuditRun(rec, run(flushInterval+time.Minute, 1, key("a", "x"))) {
+		t.Error("expected an unchanged run to be flushed after flushInterval")
+	}
+	if len(rec.Runs) != 1 || !rec.Runs[0].Last.Equal(ptr(at(flushInterval+time.Minute))) {
+		t.Fatalf("expected a single merged run, found %+v", rec.Runs)
+	}
+
+	// the listed violations are capped, so a change of the total alone is a new run
+	if !appendAuditRun(rec, run(flushInterval+2*time.Minute, 25, key("a", "x"))) {
+		t.Error("expected a run with a new total to be persisted")
+	}
+	if len(rec.Runs) != 2 {
+		t.Fatalf("expected 2 runs, found %d", len(rec.Runs))
+	}
+
+	for i := range maxAuditRuns {
+		appendAuditRun(rec, run(time.Duration(i+100)*time.Hour, i))
+	}
+	if len(rec.Runs) != maxAuditRuns {
+		t.Errorf("expected %d runs to be kept, found %d", maxAuditRuns, len(rec.Runs))
+	}
+}
+
+func ptr(t metav1.Time) *metav1.Time {
+	return &t
+}
+
+func TestToViolationSeries(t *testing.T) {
+	rec := &trendRecord{
+		Constraint: "no-latest",
+		Runs: []auditRun{
+			{First: at(0), Last: at(time.Hour), TotalViolations: 30, Violations: []violationKey{key("a", "x"), key("a", "y"), key("b", "x")}},
+			run(2*time.Hour, 0),
+		},
+	}
+
+	cluster, err := policystorage.NewScope(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	series := toViolationSeries(rec, cluster, nil)
+	if len(series) != 2 || series[0].Namespace != "a" || series[1].Namespace != "b" {
+		t.Fatalf("expected a series for namespaces a and b, found %+v", series)
+	}
+	want := []int{2, 2, 0}
+	if len(series[0].Points) != len(want) {
+		t.Fatalf("expected %d points, found %+v", len(want), series[0].Points)
+	}
+	for i, p := range series[0].Points {
+		if p.Violations != want[i] {
+			t.Errorf("point %d of namespace a = %d, want %d", i, p.Violations, want[i])
+		}
+	}
+
+	since := at(30 * time.Minute)
+	total := toTotalSeries(rec, &since)
+	if len(total.Points) != 3 || total.Points[0].Violations != 30 || !total.Points[0].AuditTimestamp.Equal(&since) {
+		t.Errorf("unexpected total series %+v", total.Points)
+	}
+
+	ns, err := policystorage.NewScope(nil, &kmapi.ObjectInfo{
+		Resource: kmapi.ResourceID{Kind: "Namespace"},
+		Ref:      kmapi.ObjectReference{Name: "b"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	series = toViolationSeries(rec, ns, nil)
+	if len(series) != 1 || series[0].Namespace != "b" || series[0].Points[0].Violations != 1 {
+		t.Errorf("expected a single series for namespace b, found %+v", series)
+	}
+}
+
+func TestConfigmapName(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "constraints.gatekeeper.sh", Version: "v1beta1", Resource: "k8srequiredlabels"}
+	if name := configmapName(gvr, "ns-must-have-owner"); name != policyapi.ResourcePolicyTrend+".k8srequiredlabels.ns-must-have-owner" {
+		t.Errorf("unexpected name %s", name)
+	}
+
+	long := strings.Repeat("a", 250)
+	name := configmapName(gvr, long)
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		t.Errorf("invalid name %s: %v", name, errs)
+	}
+	if name == configmapName(gvr, long+"b") {
+		t.Error("expected truncated names of different constraints to differ")
+	}
+}
+
+func TestRecordContinuesOnError(t *testing.T) {
+	templateGVK := schema.GroupVersionKind{Group: "templates.gatekeeper.sh", Version: "v1", Kind: "ConstraintTemplate"}
+	constraintGVK := schema.GroupVersionKind{Group: "constraints.gatekeeper.sh", Version: "v1beta1", Kind: "K8sRequiredLabels"}
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(templateGVK, meta.RESTScopeRoot)
+	mapper.Add(constraintGVK, meta.RESTScopeRoot)
+	mapper.Add(core.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+
+	template := &unstructured.Unstructured{}
+	template.SetGroupVersionKind(templateGVK)
+	template.SetName("k8srequiredlabels")
+	_ = unstructured.SetNestedField(template.Object, "K8sRequiredLabels", "spec", "crd", "spec", "names", "kind")
+	constraint := func(name string) *unstructured.Unstructured {
+		c := &unstructured.Unstructured{}
+		c.SetGroupVersionKind(constraintGVK)
+		c.SetName(name)
+		_ = unstructured.SetNestedField(c.Object, t0.Format(time.RFC3339), "status", "auditTimestamp")
+		_ = unstructured.SetNestedField(c.Object, int64(0), "status", "totalViolations")
+		return c
+	}
+	gvr := schema.GroupVersionResource{Group: constraintGVK.Group, Version: constraintGVK.Version, Resource: "k8srequiredlabels"}
+	failing := configmapName(gvr, "a")
+	gone := &core.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kubeops", Name: configmapName(gvr, "gone"), Labels: trendLabels()},
+		Data:       map[string]string{keyTrend: "constraint: gone\n"},
+	}
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	kc := fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).
+		WithObjects(template, constraint("a"), constraint("b"), gone).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if obj.GetName() == failing {
+					return errors.New("create failed")
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).Build()
+
+	if err := record(context.TODO(), kc, "kubeops"); err == nil || !strings.Contains(err.Error(), "create failed") {
+		t.Errorf("expected the error of constraint a, got %v", err)
+	}
+	var cm core.ConfigMap
+	if err := kc.Get(context.TODO(), client.ObjectKey{Namespace: "kubeops", Name: configmapName(gvr, "b")}, &cm); err != nil {
+		t.Errorf("expected constraint b to be recorded, got %v", err)
+	}
+	if err := kc.Get(context.TODO(), client.ObjectKeyFromObject(gone), &cm); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the record of a deleted constraint to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trends
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	policyapi "kubeops.dev/ui-server/apis/policy/v1alpha1"
+	policystorage "kubeops.dev/ui-server/pkg/registry/policy/reports"
+
+	"gomodules.xyz/sets"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/registry/rest"
+	kmapi "kmodules.xyz/client-go/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type Storage struct {
+	kc client.Client
+	ns string
+}
+
+var (
+	_ rest.GroupVersionKindProvider = &Storage{}
+	_ rest.Scoper                   = &Storage{}
+	_ rest.Storage                  = &Storage{}
+	_ rest.Creater                  = &Storage{}
+	_ rest.SingularNameProvider     = &Storage{}
+)
+
+func NewStorage(kc client.Client, ns string) *Storage {
+	return &Storage{
+		kc: kc,
+		ns: ns,
+	}
+}
+
+func (r *Storage) GroupVersionKind(_ schema.GroupVersion) schema.GroupVersionKind {
+	return policyapi.SchemeGroupVersion.WithKind(policyapi.ResourceKindPolicyTrend)
+}
+
+func (r *Storage) NamespaceScoped() bool {
+	return false
+}
+
+func (r *Storage) GetSingularName() string {
+	return strings.ToLower(policyapi.ResourceKindPolicyTrend)
+}
+
+func (r *Storage) New() runtime.Object {
+	return &policyapi.PolicyTrend{}
+}
+
+func (r *Storage) Destroy() {}
+
+func (r *Storage) Create(ctx context.Context, obj runtime.Object, _ rest.ValidateObjectFunc, _ *metav1.CreateOptions) (runtime.Object, error) {
+	in := obj.(*policyapi.PolicyTrend)
+
+	var (
+		oi    *kmapi.ObjectInfo
+		since *metav1.Time
+	)
+	if in.Request != nil {
+		oi = &in.Request.ObjectInfo
+		since = in.Request.Since
+	}
+	scp, err := policystorage.NewScope(r.kc, oi)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := listRecords(ctx, r.kc, r.ns)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp policyapi.PolicyTrendResponse
+	for _, rec := range records {
+		resp.Series = append(resp.Series, toViolationSeries(rec, scp, since)...)
+		if scp.IsCluster() {
+			resp.Totals = append(resp.Totals, toTotalSeries(rec, since))
+		}
+	}
+	sort.Slice(resp.Series, func(i, j int) bool {
+		if resp.Series[i].Constraint != resp.Series[j].Constraint {
+			return resp.Series[i].Constraint < resp.Series[j].Constraint
+		}
+		return resp.Series[i].Namespace < resp.Series[j].Namespace
+	})
+	sort.Slice(resp.Totals, func(i, j int) bool {
+		return resp.Totals[i].Constraint < resp.Totals[j].Constraint
+	})
+	in.Response = &resp
+	return in, nil
+}
+
+// toViolationSeries returns one series per namespace that had a violation
+// in scope during any of the recorded audit runs.
+func toViolationSeries(rec *trendRecord, scp *policystorage.Scope, since *metav1.Time) []policyapi.ViolationSeries {
+	type sample struct {
+		run    auditRun
+		counts map[string]int
+	}
+
+	samples := make([]sample, 0, len(rec.Runs))
+	namespaces := sets.NewString()
+	for _, run := range rec.Runs {
+		if since != nil && run.Last.Before(since) {
+			continue
+		}
+		violations := make([]policyapi.StatusViolation, 0, len(run.Violations))
+		for _, v := range run.Violations {
+			violations = append(violations, v.toStatusViolation())
+		}
+		counts := map[string]int{}
+		for _, v := range scp.Filter(violations) {
+			namespaces.Insert(v.Namespace)
+			counts[v.Namespace]++
+		}
+		samples = append(samples, sample{run: run, counts: counts})
+	}
+
+	series := make([]policyapi.ViolationSeries, 0, namespaces.Len())
+	for _, ns := range namespaces.List() {
+		s := policyapi.ViolationSeries{
+			Constraint: rec.Constraint,
+			GVR:        rec.GVR,
+			Namespace:  ns,
+		}
+		for _, smp := range samples {
+			s.Points = append(s.Points, runPoints(smp.run, since, smp.counts[ns])...)
+		}
+		series = append(series, s)
+	}
+	return series
+}
+
+// toTotalSeries returns the total violations of the constraint reported by Gatekeeper
+// during the recorded audit runs.
+func toTotalSeries(rec *trendRecord, since *metav1.Time) policyapi.ViolationSeries {
+	s := policyapi.ViolationSeries{
+		Constraint: rec.Constraint,
+		GVR:        rec.GVR,
+		Points:     []policyapi.ViolationPoint{},
+	}
+	for _, run := range rec.Runs {
+		if since != nil && run.Last.Before(since) {
+			continue
+		}
+		s.Points = append(s.Points, runPoints(run, since, run.TotalViolations)...)
+	}
+	return s
+}
+
+// runPoints returns the points at the start and the end of an audit run, clamped to since.
+func runPoints(run auditRun, since *metav1.Time, violations int) []policyapi.ViolationPoint {
+	first := run.First
+	if since != nil && first.Before(since) {
+		first = *since
+	}
+	points := []policyapi.ViolationPoint{{
+		AuditTimestamp: first,
+		Violations:     violations,
+	}}
+	if run.Last.After(first.Time) {
+		points = append(points, policyapi.ViolationPoint{
+			AuditTimestamp: run.Last,
+			Violations:     violations,
+		})
+	}
+	return points
+}
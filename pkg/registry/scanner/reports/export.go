@@ -0,0 +1,114 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reports
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	policyapi "kubeops.dev/ui-server/apis/policy/v1alpha1"
+	uireportsapi "kubeops.dev/ui-server/apis/reports/v1alpha1"
+	"kubeops.dev/ui-server/pkg/exporter"
+	"kubeops.dev/ui-server/pkg/shared"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/registry/rest"
+	kmapi "kmodules.xyz/client-go/api/v1"
+	reportsapi "kubeops.dev/scanner/apis/reports/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ExportStorage serves CVEReportExports, which render a CVEReport as a file.
+type ExportStorage struct {
+	s *Storage
+}
+
+var (
+	_ rest.GroupVersionKindProvider = &ExportStorage{}
+	_ rest.Scoper                   = &ExportStorage{}
+	_ rest.Storage                  = &ExportStorage{}
+	_ rest.Creater                  = &ExportStorage{}
+	_ rest.SingularNameProvider     = &ExportStorage{}
+)
+
+func NewExportStorage(kc client.Client) *ExportStorage {
+	return &ExportStorage{s: NewStorage(kc)}
+}
+
+func (r *ExportStorage) GroupVersionKind(_ schema.GroupVersion) schema.GroupVersionKind {
+	return uireportsapi.SchemeGroupVersion.WithKind(uireportsapi.ResourceKindCVEReportExport)
+}
+
+func (r *ExportStorage) NamespaceScoped() bool {
+	return false
+}
+
+func (r *ExportStorage) GetSingularName() string {
+	return strings.ToLower(uireportsapi.ResourceKindCVEReportExport)
+}
+
+func (r *ExportStorage) New() runtime.Object {
+	return &uireportsapi.CVEReportExport{}
+}
+
+func (r *ExportStorage) Destroy() {}
+
+func (r *ExportStorage) Create(ctx context.Context, obj runtime.Object, _ rest.ValidateObjectFunc, _ *metav1.CreateOptions) (runtime.Object, error) {
+	in := obj.(*uireportsapi.CVEReportExport)
+	if in.Request == nil {
+		return nil, apierrors.NewBadRequest("missing request")
+	}
+	format := exporter.Format(in.Request.ExportFormat)
+	if !format.Valid() {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("unsupported export format %q", in.Request.ExportFormat))
+	}
+
+	// an empty ObjectInfo requests the cluster wide report, like a nil CVEReport request
+	var oi *kmapi.ObjectInfo
+	if in.Request.ObjectInfo != (kmapi.ObjectInfo{}) {
+		oi = &in.Request.ObjectInfo
+	}
+	rpt := &reportsapi.CVEReport{}
+	if oi != nil {
+		rpt.Request = &reportsapi.CVEReportRequest{ObjectInfo: *oi}
+	}
+	out, err := r.s.Create(ctx, rpt, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	rpt = out.(*reportsapi.CVEReport)
+
+	buf := shared.BufferPool.Get().(*bytes.Buffer)
+	defer shared.BufferPool.Put(buf)
+	buf.Reset()
+
+	if err := exporter.Render(buf, format, exporter.FromCVEReport(rpt.Response, exporter.Scope(oi), time.Now())); err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	in.Response = &policyapi.ExportedReport{
+		Format:      in.Request.ExportFormat,
+		ContentType: format.ContentType(),
+		Data:        buf.String(),
+	}
+	return in, nil
+}
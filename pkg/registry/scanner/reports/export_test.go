@@ -0,0 +1,110 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reports
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	policyapi "kubeops.dev/ui-server/apis/policy/v1alpha1"
+	uireportsapi "kubeops.dev/ui-server/apis/reports/v1alpha1"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	kmapi "kmodules.xyz/client-go/api/v1"
+	reportsapi "kubeops.dev/scanner/apis/reports/v1alpha1"
+	scannerapi "kubeops.dev/scanner/apis/scanner/v1alpha1"
+	"kubeops.dev/scanner/apis/trivy"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testImage = "ghcr.io/appscode/app:v1"
+
+func newExportStorage(t *testing.T) *ExportStorage {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := scannerapi.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	report := &scannerapi.ImageScanReport{
+		ObjectMeta: metav1.ObjectMeta{Name: scannerapi.GetReportName(testImage)},
+		Status: scannerapi.ImageScanReportStatus{
+			Report: trivy.SingleReport{
+				Results: []trivy.Result{{
+					Target: testImage,
+					Vulnerabilities: []trivy.Vulnerability{{
+						VulnerabilityID: "CVE-2024-0001",
+						PkgName:         "openssl",
+						Severity:        "CRITICAL",
+					}},
+				}},
+			},
+		},
+	}
+	kc := fake.NewClientBuilder().WithScheme(scheme).WithObjects(report).Build()
+	return NewExportStorage(kc)
+}
+
+func imageRequest(format policyapi.ExportFormat) *uireportsapi.CVEReportExport {
+	return &uireportsapi.CVEReportExport{
+		Request: &uireportsapi.CVEReportExportRequest{
+			ObjectInfo: kmapi.ObjectInfo{
+				Resource: kmapi.ResourceID{Group: reportsapi.SchemeGroupVersion.Group, Kind: "Image"},
+				Ref:      kmapi.ObjectReference{Name: testImage},
+			},
+			ExportFormat: format,
+		},
+	}
+}
+
+func TestExportStorageCreate(t *testing.T) {
+	s := newExportStorage(t)
+
+	obj, err := s.Create(context.Background(), imageRequest(policyapi.ExportFormatCSV), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := obj.(*uireportsapi.CVEReportExport)
+	if out.Response == nil {
+		t.Fatal("expected a response")
+	}
+	if out.Response.Format != policyapi.ExportFormatCSV || out.Response.ContentType == "" {
+		t.Errorf("unexpected response %+v", out.Response)
+	}
+	if !strings.Contains(out.Response.Data, "CVE-2024-0001") {
+		t.Errorf("expected the export to list CVE-2024-0001, found %q", out.Response.Data)
+	}
+}
+
+func TestExportStorageCreateInvalid(t *testing.T) {
+	s := newExportStorage(t)
+
+	for name, in := range map[string]*uireportsapi.CVEReportExport{
+		"missing request": {},
+		"unknown format":  imageRequest("pdf"),
+	} {
+		_, err := s.Create(context.Background(), in, nil, nil)
+		if !apierrors.IsBadRequest(err) {
+			t.Errorf("%s: expected a bad request, found %v", name, err)
+		}
+	}
+}
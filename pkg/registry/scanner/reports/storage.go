@@ -133,6 +133,23 @@ func (r *Storage) Create(ctx context.Context, obj runtime.Object, _ rest.Validat
 	return in, err
 }
 
+// PodsReport generates the CVE report of the images of pods.
+func PodsReport(ctx context.Context, kc client.Client, pods []core.Pod) (*reportsapi.CVEReportResponse, error) {
+	images := map[string]kmapi.ImageInfo{}
+	for i := range pods {
+		var err error
+		images, err = apiutil.CollectImageInfo(kc, &pods[i], images, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+	results, err := collectReports(ctx, kc, images)
+	if err != nil {
+		return nil, err
+	}
+	return GenerateReports(images, results, everything{})
+}
+
 type result struct {
 	ref     string
 	report  scannerapi.ImageScanReport
@@ -0,0 +1,110 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shared
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// WatchedKinds returns the kinds whose informers a watch of s is fed by. Each of them is
+// a cluster wide informer of the manager's cache that lasts as long as the server, so a
+// watch of every kind, ie, without a k8s.io/group or k8s.io/group-kind label selector,
+// is only served if allKinds is set.
+func WatchedKinds(s GroupKindSelector, allKinds bool) (map[schema.GroupKind]string, error) {
+	if s.everything && !allKinds {
+		return nil, apierrors.NewBadRequest("watch requires a k8s.io/group or k8s.io/group-kind label selector")
+	}
+	return RegisteredKinds(s), nil
+}
+
+// newCacheObject returns a typed object of gvk if it is in the scheme, so that the
+// informer is shared with the typed readers of the cache, otherwise an unstructured one.
+func newCacheObject(scheme *runtime.Scheme, gvk schema.GroupVersionKind) client.Object {
+	if obj, err := scheme.New(gvk); err == nil {
+		if o, ok := obj.(client.Object); ok {
+			return o
+		}
+	}
+	var obj unstructured.Unstructured
+	obj.SetGroupVersionKind(gvk)
+	return &obj
+}
+
+// GetInformer returns the informer of gvk. Its objects are converted with AsUnstructured.
+func GetInformer(ctx context.Context, informers cache.Informers, scheme *runtime.Scheme, gvk schema.GroupVersionKind) (cache.Informer, error) {
+	return informers.GetInformer(ctx, newCacheObject(scheme, gvk))
+}
+
+// AsUnstructured converts an object of an informer returned by GetInformer.
+func AsUnstructured(obj client.Object, gvk schema.GroupVersionKind) (*unstructured.Unstructured, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u, nil
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(gvk)
+	return u, nil
+}
+
+// ListUnstructured lists the objects of gvk as unstructured. Like GetInformer, a typed
+// list is used if gvk is in the scheme, so that reading from the cache does not start a
+// second informer of the kind.
+func ListUnstructured(ctx context.Context, kc client.Reader, scheme *runtime.Scheme, gvk schema.GroupVersionKind, opts ...client.ListOption) ([]unstructured.Unstructured, error) {
+	listGVK := gvk.GroupVersion().WithKind(gvk.Kind + "List")
+	obj, err := scheme.New(listGVK)
+	list, ok := obj.(client.ObjectList)
+	if err != nil || !ok {
+		var ul unstructured.UnstructuredList
+		ul.SetGroupVersionKind(listGVK)
+		if err := kc.List(ctx, &ul, opts...); err != nil {
+			return nil, err
+		}
+		return ul.Items, nil
+	}
+
+	if err := kc.List(ctx, list, opts...); err != nil {
+		return nil, err
+	}
+	objs, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]unstructured.Unstructured, 0, len(objs))
+	for _, o := range objs {
+		co, ok := o.(client.Object)
+		if !ok {
+			continue
+		}
+		u, err := AsUnstructured(co, gvk)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *u)
+	}
+	return items, nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shared
+
+import (
+	"context"
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWatchedKinds(t *testing.T) {
+	if _, err := WatchedKinds(NewGroupKindSelector(nil), false); !apierrors.IsBadRequest(err) {
+		t.Errorf("expected a bad request without a selector, found %v", err)
+	}
+	if _, err := WatchedKinds(NewGroupKindSelector(nil), true); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	sel := NewGroupKindSelector(labels.SelectorFromSet(map[string]string{"k8s.io/group-kind": "Pod"}))
+	if _, err := WatchedKinds(sel, false); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestListUnstructured(t *testing.T) {
+	kc := fake.NewClientBuilder().WithObjects(
+		&core.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "demo", Name: "web-0"}},
+		&core.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "web-1"}},
+	).Build()
+
+	gvk := core.SchemeGroupVersion.WithKind("Pod")
+	items, err := ListUnstructured(context.Background(), kc, clientgoscheme.Scheme, gvk, client.InNamespace("demo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].GetName() != "web-0" || items[0].GroupVersionKind() != gvk {
+		t.Errorf("unexpected items %+v", items)
+	}
+}
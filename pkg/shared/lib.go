@@ -25,8 +25,10 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/selection"
+	"kmodules.xyz/apiversion"
 	kmapi "kmodules.xyz/client-go/api/v1"
 	rscoreapi "kmodules.xyz/resource-metadata/apis/core/v1alpha1"
+	"kmodules.xyz/resource-metrics/api"
 )
 
 var BufferPool = sync.Pool{
@@ -94,6 +96,22 @@ func (s GroupKindSelector) Matches(gk schema.GroupKind) bool {
 	return ok
 }
 
+// RegisteredKinds returns the latest version of each resource-metrics registered kind
+// matched by the selector.
+func RegisteredKinds(s GroupKindSelector) map[schema.GroupKind]string {
+	gvks := make(map[schema.GroupKind]string)
+	for _, gvk := range api.RegisteredTypes() {
+		if !s.Matches(gvk.GroupKind()) {
+			continue
+		}
+		gk := gvk.GroupKind()
+		if v, exists := gvks[gk]; !exists || apiversion.MustCompare(v, gvk.Version) < 0 {
+			gvks[gk] = gvk.Version
+		}
+	}
+	return gvks
+}
+
 var (
 	podGVR     = schema.GroupVersionResource{Version: "v1", Resource: "Pods"}
 	podviewGVR = rscoreapi.SchemeGroupVersion.WithResource(rscoreapi.ResourcePodViews)
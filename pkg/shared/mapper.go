@@ -0,0 +1,195 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shared
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/klog/v2"
+)
+
+// minMapperResetInterval limits how often a RESTMapper rediscovers the api resources
+// when asked for a kind it does not know.
+const minMapperResetInterval = 30 * time.Second
+
+// RESTMapper is a discovery based RESTMapper shared by the storages. The discovery
+// information is cached and refreshed when a kind is not found, so that CRDs installed
+// after the server started are picked up, or when Reset is called.
+type RESTMapper struct {
+	mapper *restmapper.DeferredDiscoveryRESTMapper
+
+	mu        sync.Mutex
+	lastReset time.Time
+}
+
+var _ meta.RESTMapper = &RESTMapper{}
+
+func NewRESTMapper(dc discovery.DiscoveryInterface) *RESTMapper {
+	return &RESTMapper{
+		mapper: restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc)),
+	}
+}
+
+// Reset invalidates the cached discovery information.
+func (m *RESTMapper) Reset() {
+	m.mu.Lock()
+	m.lastReset = time.Now()
+	m.mu.Unlock()
+	m.mapper.Reset()
+}
+
+// resetOnMiss resets the mapper unless it was reset recently, and reports whether it did.
+func (m *RESTMapper) resetOnMiss() bool {
+	m.mu.Lock()
+	if time.Since(m.lastReset) < minMapperResetInterval {
+		m.mu.Unlock()
+		return false
+	}
+	m.lastReset = time.Now()
+	m.mu.Unlock()
+
+	klog.V(3).InfoS("resetting discovery cache")
+	m.mapper.Reset()
+	return true
+}
+
+func (m *RESTMapper) KindFor(resource schema.GroupVersionResource) (schema.GroupVersionKind, error) {
+	gvk, err := m.mapper.KindFor(resource)
+	if meta.IsNoMatchError(err) && m.resetOnMiss() {
+		return m.mapper.KindFor(resource)
+	}
+	return gvk, err
+}
+
+func (m *RESTMapper) KindsFor(resource schema.GroupVersionResource) ([]schema.GroupVersionKind, error) {
+	gvks, err := m.mapper.KindsFor(resource)
+	if meta.IsNoMatchError(err) && m.resetOnMiss() {
+		return m.mapper.KindsFor(resource)
+	}
+	return gvks, err
+}
+
+func (m *RESTMapper) ResourceFor(input schema.GroupVersionResource) (schema.GroupVersionResource, error) {
+	gvr, err := m.mapper.ResourceFor(input)
+	if meta.IsNoMatchError(err) && m.resetOnMiss() {
+		return m.mapper.ResourceFor(input)
+	}
+	return gvr, err
+}
+
+func (m *RESTMapper) ResourcesFor(input schema.GroupVersionResource) ([]schema.GroupVersionResource, error) {
+	gvrs, err := m.mapper.ResourcesFor(input)
+	if meta.IsNoMatchError(err) && m.resetOnMiss() {
+		return m.mapper.ResourcesFor(input)
+	}
+	return gvrs, err
+}
+
+func (m *RESTMapper) RESTMapping(gk schema.GroupKind, versions ...string) (*meta.RESTMapping, error) {
+	mapping, err := m.mapper.RESTMapping(gk, versions...)
+	if meta.IsNoMatchError(err) && m.resetOnMiss() {
+		return m.mapper.RESTMapping(gk, versions...)
+	}
+	return mapping, err
+}
+
+func (m *RESTMapper) RESTMappings(gk schema.GroupKind, versions ...string) ([]*meta.RESTMapping, error) {
+	mappings, err := m.mapper.RESTMappings(gk, versions...)
+	if meta.IsNoMatchError(err) && m.resetOnMiss() {
+		return m.mapper.RESTMappings(gk, versions...)
+	}
+	return mappings, err
+}
+
+func (m *RESTMapper) ResourceSingularizer(resource string) (string, error) {
+	return m.mapper.ResourceSingularizer(resource)
+}
+
+// RegisteredMappings returns the REST mappings of the registered kinds matched by the
+// selector, sorted by group and kind. Kinds not served by the cluster are skipped.
+func RegisteredMappings(mapper meta.RESTMapper, s GroupKindSelector) ([]*meta.RESTMapping, error) {
+	gvks := RegisteredKinds(s)
+	mappings := make([]*meta.RESTMapping, 0, len(gvks))
+	for gk, v := range gvks {
+		mapping, err := mapper.RESTMapping(gk, v)
+		if meta.IsNoMatchError(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, mapping)
+	}
+	sort.Slice(mappings, func(i, j int) bool {
+		return LessGroupKind(mappings[i].GroupVersionKind.GroupKind(), mappings[j].GroupVersionKind.GroupKind())
+	})
+	return mappings, nil
+}
+
+// LessGroupKind orders group kinds by group, then kind.
+func LessGroupKind(a, b schema.GroupKind) bool {
+	if a.Group != b.Group {
+		return a.Group < b.Group
+	}
+	return a.Kind < b.Kind
+}
+
+// ContinueToken is the position of a paginated List across kinds. GroupKind is the kind
+// to resume from and Continue the continue token of the list of that kind, if any.
+type ContinueToken struct {
+	GroupKind string `json:"gk"`
+	Continue  string `json:"continue,omitempty"`
+}
+
+func (t ContinueToken) Encode() string {
+	data, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeContinueToken(s string) (*ContinueToken, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var t ContinueToken
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ResumeIndex returns the index of the first mapping at or after the kind of the token.
+// It also returns the continue token of that kind's list, which only applies when the
+// kind is still served.
+func (t ContinueToken) ResumeIndex(mappings []*meta.RESTMapping) (int, string) {
+	gk := schema.ParseGroupKind(t.GroupKind)
+	i := sort.Search(len(mappings), func(i int) bool {
+		return !LessGroupKind(mappings[i].GroupVersionKind.GroupKind(), gk)
+	})
+	if i < len(mappings) && mappings[i].GroupVersionKind.GroupKind() == gk {
+		return i, t.Continue
+	}
+	return i, ""
+}
@@ -0,0 +1,59 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shared
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestContinueToken(t *testing.T) {
+	mapping := func(group, kind string) *meta.RESTMapping {
+		return &meta.RESTMapping{GroupVersionKind: schema.GroupVersionKind{Group: group, Version: "v1", Kind: kind}}
+	}
+	mappings := []*meta.RESTMapping{
+		mapping("apps", "Deployment"),
+		mapping("apps", "StatefulSet"),
+		mapping("kubedb.com", "MongoDB"),
+		mapping("kubedb.com", "Postgres"),
+	}
+
+	tok, err := DecodeContinueToken(ContinueToken{GroupKind: "StatefulSet.apps", Continue: "abc"}.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i, cont := tok.ResumeIndex(mappings); i != 1 || cont != "abc" {
+		t.Errorf("ResumeIndex() = %d, %q, want 1, abc", i, cont)
+	}
+
+	// the kind of the token is no longer served, so the list resumes from the next kind
+	tok = &ContinueToken{GroupKind: "MySQL.kubedb.com", Continue: "abc"}
+	if i, cont := tok.ResumeIndex(mappings); i != 3 || cont != "" {
+		t.Errorf("ResumeIndex() = %d, %q, want 3, empty", i, cont)
+	}
+
+	tok = &ContinueToken{GroupKind: "Redis.kubedb.com"}
+	if i, _ := tok.ResumeIndex(mappings); i != len(mappings) {
+		t.Errorf("ResumeIndex() = %d, want %d", i, len(mappings))
+	}
+
+	if _, err := DecodeContinueToken("not a token"); err == nil {
+		t.Error("expected an error for an invalid token")
+	}
+}
@@ -0,0 +1,213 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shared
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CanGet reports whether u may get obj. It is used to filter watch events, where an
+// authorization error drops the event instead of failing the watch.
+func CanGet(ctx context.Context, a authorizer.Authorizer, u user.Info, gr schema.GroupResource, obj metav1.Object) bool {
+	attrs := authorizer.AttributesRecord{
+		User:            u,
+		Verb:            "get",
+		Namespace:       obj.GetNamespace(),
+		APIGroup:        gr.Group,
+		Resource:        gr.Resource,
+		Name:            obj.GetName(),
+		ResourceRequest: true,
+	}
+	decision, _, err := a.Authorize(ctx, attrs)
+	if err != nil {
+		klog.ErrorS(err, "failed to authorize watch event", "resource", gr, "namespace", obj.GetNamespace(), "name", obj.GetName())
+		return false
+	}
+	return decision == authorizer.DecisionAllow
+}
+
+const (
+	// WatchBufferSize is the number of events buffered for an InformerWatcher.
+	WatchBufferSize = 100
+	// WatchSendTimeout is how long an event waits for room in a full buffer. As in the
+	// watch cache of the apiserver, the watch is closed if the client falls behind for
+	// longer, and the client is expected to watch again.
+	WatchSendTimeout = 5 * time.Second
+)
+
+// InformerEventFunc converts an event on an object of an informer into an event on a
+// synthetic type. It returns a nil object to drop the event.
+type InformerEventFunc func(t watch.EventType, obj client.Object) runtime.Object
+
+// InformerWatcher is a watch.Interface fed by the event handlers of shared informers.
+// Objects already in an informer's cache are sent as Added events when the handler is
+// added, so clients receive the current state before any change. If the watch starts
+// from a resource version, only the cached objects changed after it are sent.
+type InformerWatcher struct {
+	mu     sync.Mutex
+	closed bool
+	result chan watch.Event
+	done   chan struct{}
+	once   sync.Once
+
+	resourceVersion uint64
+
+	regs []informerRegistration
+}
+
+type informerRegistration struct {
+	inf cache.Informer
+	reg toolscache.ResourceEventHandlerRegistration
+}
+
+var _ watch.Interface = &InformerWatcher{}
+
+// NewInformerWatcher returns a watcher that is closed when ctx is done or Stop is called.
+// The resourceVersion of the watch request is used to skip the cached objects a client
+// has already listed, an empty or "0" version sends all of them.
+func NewInformerWatcher(ctx context.Context, resourceVersion string) *InformerWatcher {
+	w := &InformerWatcher{
+		result: make(chan watch.Event, WatchBufferSize),
+		done:   make(chan struct{}),
+	}
+	if rv, err := strconv.ParseUint(resourceVersion, 10, 64); err == nil {
+		w.resourceVersion = rv
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-w.done:
+		}
+		w.Stop()
+		w.close()
+	}()
+	return w
+}
+
+func (w *InformerWatcher) ResultChan() <-chan watch.Event {
+	return w.result
+}
+
+func (w *InformerWatcher) Stop() {
+	w.once.Do(func() {
+		close(w.done)
+	})
+}
+
+// Done is closed when the watch is stopped.
+func (w *InformerWatcher) Done() <-chan struct{} {
+	return w.done
+}
+
+// AddInformer sends the events of inf, converted by fn, to the watcher.
+func (w *InformerWatcher) AddInformer(inf cache.Informer, fn InformerEventFunc) error {
+	handle := func(t watch.EventType, obj any, initial bool) {
+		if d, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+			obj = d.Obj
+		}
+		o, ok := obj.(client.Object)
+		if !ok || initial && !w.changedSince(o) {
+			return
+		}
+		if out := fn(t, o); out != nil {
+			w.Send(watch.Event{Type: t, Object: out})
+		}
+	}
+	reg, err := inf.AddEventHandler(toolscache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj any, isInInitialList bool) {
+			handle(watch.Added, obj, isInInitialList)
+		},
+		UpdateFunc: func(_, newObj any) {
+			handle(watch.Modified, newObj, false)
+		},
+		DeleteFunc: func(obj any) {
+			handle(watch.Deleted, obj, false)
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return inf.RemoveEventHandler(reg)
+	}
+	w.regs = append(w.regs, informerRegistration{inf: inf, reg: reg})
+	return nil
+}
+
+// changedSince reports whether obj changed after the resource version of the watch.
+func (w *InformerWatcher) changedSince(obj client.Object) bool {
+	if w.resourceVersion == 0 {
+		return true
+	}
+	rv, err := strconv.ParseUint(obj.GetResourceVersion(), 10, 64)
+	return err != nil || rv > w.resourceVersion
+}
+
+// Send delivers e to the client. If the buffer stays full for WatchSendTimeout, the
+// watch is stopped.
+func (w *InformerWatcher) Send(e watch.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return
+	}
+	select {
+	case w.result <- e:
+		return
+	default:
+	}
+
+	t := time.NewTimer(WatchSendTimeout)
+	defer t.Stop()
+	select {
+	case w.result <- e:
+	case <-w.done:
+	case <-t.C:
+		klog.V(3).InfoS("closing watch of slow client")
+		w.Stop()
+	}
+}
+
+func (w *InformerWatcher) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, r := range w.regs {
+		if err := r.inf.RemoveEventHandler(r.reg); err != nil {
+			klog.ErrorS(err, "failed to remove watch handler")
+		}
+	}
+	w.regs = nil
+	w.closed = true
+	close(w.result)
+}
@@ -0,0 +1,176 @@
+/*
+Copyright AppsCode Inc. and Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shared
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestInformerWatcher(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	kc := fake.NewSimpleClientset(&core.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "demo", Name: "web-0"},
+	})
+	inf := informers.NewSharedInformerFactory(kc, 0).Core().V1().Pods().Informer()
+	go inf.Run(ctx.Done())
+	if !toolscache.WaitForCacheSync(ctx.Done(), inf.HasSynced) {
+		t.Fatal("informer did not sync")
+	}
+
+	w := NewInformerWatcher(ctx, "")
+	err := w.AddInformer(inf, func(_ watch.EventType, obj client.Object) runtime.Object {
+		if obj.GetName() == "ignored" {
+			return nil
+		}
+		return &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "view-" + obj.GetName()}}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next := func() watch.Event {
+		t.Helper()
+		select {
+		case e, ok := <-w.ResultChan():
+			if !ok {
+				t.Fatal("watch closed")
+			}
+			return e
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for event")
+		}
+		return watch.Event{}
+	}
+	expect := func(typ watch.EventType, name string) {
+		t.Helper()
+		e := next()
+		if got := e.Object.(client.Object).GetName(); e.Type != typ || got != name {
+			t.Fatalf("expected %s %s, found %s %s", typ, name, e.Type, got)
+		}
+	}
+
+	expect(watch.Added, "view-web-0")
+
+	pods := kc.CoreV1().Pods("demo")
+	if _, err := pods.Create(ctx, &core.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "demo", Name: "ignored"}}, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pods.Create(ctx, &core.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "demo", Name: "web-1"}}, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	expect(watch.Added, "view-web-1")
+
+	if err := pods.Delete(ctx, "web-0", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	expect(watch.Deleted, "view-web-0")
+
+	w.Stop()
+	select {
+	case _, ok := <-w.ResultChan():
+		if ok {
+			t.Fatal("expected the result channel to be closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch was not closed")
+	}
+}
+
+func TestInformerWatcherResourceVersion(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	kc := fake.NewSimpleClientset(
+		&core.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "demo", Name: "web-0", ResourceVersion: "5"}},
+		&core.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "demo", Name: "web-1", ResourceVersion: "10"}},
+	)
+	inf := informers.NewSharedInformerFactory(kc, 0).Core().V1().Pods().Informer()
+	go inf.Run(ctx.Done())
+	if !toolscache.WaitForCacheSync(ctx.Done(), inf.HasSynced) {
+		t.Fatal("informer did not sync")
+	}
+
+	w := NewInformerWatcher(ctx, "7")
+	defer w.Stop()
+	err := w.AddInformer(inf, func(_ watch.EventType, obj client.Object) runtime.Object {
+		return obj
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case e := <-w.ResultChan():
+		if got := e.Object.(client.Object).GetName(); e.Type != watch.Added || got != "web-1" {
+			t.Fatalf("expected ADDED web-1, found %s %s", e.Type, got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+	select {
+	case e := <-w.ResultChan():
+		t.Fatalf("unexpected event %s %s", e.Type, e.Object.(client.Object).GetName())
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestInformerWatcherFullBuffer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w := NewInformerWatcher(ctx, "")
+	defer w.Stop()
+	for i := 0; i < WatchBufferSize; i++ {
+		w.Send(watch.Event{Type: watch.Added, Object: &metav1.PartialObjectMetadata{}})
+	}
+
+	sent := make(chan struct{})
+	go func() {
+		w.Send(watch.Event{Type: watch.Modified, Object: &metav1.PartialObjectMetadata{}})
+		close(sent)
+	}()
+	select {
+	case <-sent:
+		t.Fatal("expected Send to wait for the client")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	<-w.ResultChan()
+	select {
+	case <-sent:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Send did not resume")
+	}
+	select {
+	case <-w.Done():
+		t.Fatal("expected the watch to stay open")
+	default:
+	}
+}